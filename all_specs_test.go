@@ -11,6 +11,15 @@ func TestAllSpecs(t *testing.T) {
 	r.AddSpec(Alloc1dSpec)
 	r.AddSpec(Alloc2dSpec)
 	r.AddSpec(Alloc3dSpec)
+	r.AddSpec(Window2dSpec)
+	r.AddSpec(WarmSpec)
+	r.AddSpec(WhitenSpec)
+	r.AddSpec(ArenaSpec)
+	r.AddSpec(ScaleSpec)
+	r.AddSpec(BinStatsSpec)
+	r.AddSpec(SpurSpec)
+	r.AddSpec(OneSidedSpec)
+	r.AddSpec(ScratchSpec)
 	gospec.MainGoTest(r, t)
 
 	// TODO: Investigate a less stupid way of doing tests in serial
@@ -25,9 +34,246 @@ func TestAllSpecs(t *testing.T) {
 	r.AddSpec(FFT3dSpec)
 	gospec.MainGoTest(r, t)
 	r = gospec.NewRunner()
+	r.AddSpec(FFTNdSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
 	r.AddSpec(FFTR2CSpec)
 	gospec.MainGoTest(r, t)
 	r = gospec.NewRunner()
 	r.AddSpec(FFTC2RSpec)
 	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FFTC2RPreserveSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FFTR2C2dSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FFTR2C3dSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DftMatrixSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GeometrySpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(LayoutSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DftFramesSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SuggestShapeSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ConvolveSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DftPairSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(CepstrumSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(TransferFunctionSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(WarmUpSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(InterpolateFFTSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(CircularShiftSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(MultitaperSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(BandPowerSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(WignerVilleSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(STFTSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SynchrosqueezeSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(HPSSSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GriffinLimSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FilterbankSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ApplyPhaseResponseSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DelayAndSumSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SubspaceSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(RenderSpectrogramSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(AmplitudeSpectrumSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PSDAccumulatorSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PlanSpecSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PipelineSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GraphSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ProgressSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(CheckpointSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ChunkedArraySpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SwappablePlanSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(R2R1dSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(R2RNdSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(WindowedFFTSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DctSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DstSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GoldenSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DhtSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(HalfComplexSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(StrictSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(InPlaceSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(TransformCacheSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ChannelizerSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SpectralGateSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PlanManySpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(StridedSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(BudgetedPlanSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(DftAxisSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(MorphSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GuruSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(LargeSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ImagePlanesSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SeparableFilterSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SplitSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(VolumeFilterSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ExecuteSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(OrientationSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FringeSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(StackSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(TurbulenceSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(CorrelationSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PMESpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(ThreadsSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GreenSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(PlanTimeLimitSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(VectorSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FlagSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(FieldInterpolatorSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(CleanupSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SpectrumTrackerSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(GraphAllocsSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(WindowedFFTAllocsSpec)
+	gospec.MainGoTest(r, t)
+	r = gospec.NewRunner()
+	r.AddSpec(SpectralGateAllocsSpec)
+	gospec.MainGoTest(r, t)
 }
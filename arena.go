@@ -0,0 +1,102 @@
+package fftw
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// The smallest size class holds 4 elements, 64 bytes, so every block in an
+// arena is aligned at least as well as the arena itself.
+const minArenaClass = 4
+
+// An Arena hands out buffers carved from a single region allocated with
+// fftw_malloc.  Buffers are rounded up to a power of two size class and
+// freed buffers are reused by later allocations of the same class, which is
+// much cheaper than fftw_malloc and fftw_free for workloads that create and
+// destroy thousands of small transform buffers.  Arenas are safe for
+// concurrent use.
+//
+// Memory is not returned to the system until Release is called, and there is
+// no finalizer, since buffers from the arena would outlive it.
+type Arena struct {
+	mu     sync.Mutex
+	region []complex128
+	next   int
+	free   map[int][]int
+	// live maps the offset of each allocated buffer to its size class.
+	live map[int]int
+}
+
+// NewArena allocates an arena with room for size complex128 elements.
+func NewArena(size int) *Arena {
+	a := new(Arena)
+	a.region = Alloc1d(size)
+	a.free = make(map[int][]int)
+	a.live = make(map[int]int)
+	return a
+}
+
+func arenaClass(n int) int {
+	class := minArenaClass
+	for class < n {
+		class *= 2
+	}
+	return class
+}
+
+// Alloc returns a zeroed buffer of n elements.  It panics if the arena
+// doesn't have room for it.
+func (a *Arena) Alloc(n int) []complex128 {
+	class := arenaClass(n)
+	a.mu.Lock()
+	var offset int
+	if list := a.free[class]; len(list) > 0 {
+		offset = list[len(list)-1]
+		a.free[class] = list[:len(list)-1]
+	} else {
+		if a.next+class > len(a.region) {
+			a.mu.Unlock()
+			panic(fmt.Sprint("Arena of ", len(a.region), " elements has no room for ", n, " more elements"))
+		}
+		offset = a.next
+		a.next += class
+	}
+	a.live[offset] = class
+	a.mu.Unlock()
+
+	buf := a.region[offset : offset+n : offset+class]
+	for i := range buf {
+		buf[i] = 0
+	}
+	return buf
+}
+
+// Free returns a buffer obtained from Alloc to the arena.  The buffer must
+// not be used afterwards.  Free panics if buf is not a buffer returned by
+// Alloc, such as a sub-slice of one, or has already been freed.
+func (a *Arena) Free(buf []complex128) {
+	base := (*reflect.SliceHeader)(unsafe.Pointer(&a.region)).Data
+	ptr := (*reflect.SliceHeader)(unsafe.Pointer(&buf)).Data
+	offset := int(ptr-base) / 16
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	class, ok := a.live[offset]
+	if ptr < base || !ok || cap(buf) != class {
+		panic("Buffer was not allocated by this arena, or was already freed")
+	}
+	delete(a.live, offset)
+	a.free[class] = append(a.free[class], offset)
+}
+
+// Release frees the arena's memory.  Neither the arena nor any buffer
+// allocated from it may be used afterwards.
+func (a *Arena) Release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.region != nil {
+		Free1d(a.region)
+		a.region = nil
+	}
+}
@@ -0,0 +1,74 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func ArenaSpec(c gospec.Context) {
+	a := NewArena(1024)
+	defer a.Release()
+
+	c.Specify("Arena buffers have the requested length and start zeroed.", func() {
+		b := a.Alloc(10)
+		c.Expect(len(b), gospec.Equals, 10)
+		c.Expect(cap(b), gospec.Equals, 16)
+		for i := range b {
+			c.Expect(b[i], gospec.Equals, complex128(0))
+			b[i] = 1
+		}
+		a.Free(b)
+		b = a.Alloc(12)
+		for i := range b {
+			c.Expect(b[i], gospec.Equals, complex128(0))
+		}
+		a.Free(b)
+	})
+
+	c.Specify("Freed buffers are reused by the same size class.", func() {
+		b := a.Alloc(30)
+		a.Free(b)
+		d := a.Alloc(20)
+		c.Expect(&d[0] == &b[0], gospec.IsTrue)
+		a.Free(d)
+	})
+
+	c.Specify("Live buffers don't overlap.", func() {
+		bufs := [][]complex128{a.Alloc(3), a.Alloc(100), a.Alloc(7), a.Alloc(64)}
+		for i, b := range bufs {
+			for j := range b {
+				b[j] = complex(float64(i), 0)
+			}
+		}
+		for i, b := range bufs {
+			for j := range b {
+				c.Expect(b[j], gospec.Equals, complex(float64(i), 0))
+			}
+			a.Free(b)
+		}
+	})
+
+	c.Specify("Buffers from elsewhere can't be freed into an arena.", func() {
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		a.Free(make([]complex128, 4))
+	})
+
+	c.Specify("Buffers can't be freed twice.", func() {
+		b := a.Alloc(8)
+		a.Free(b)
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		a.Free(b)
+	})
+
+	c.Specify("Sub-slices of buffers can't be freed.", func() {
+		b := a.Alloc(16)
+		defer a.Free(b)
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		a.Free(b[8:])
+	})
+}
@@ -0,0 +1,81 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// planDftAxis plans one dimensional transforms along the axis of the
+// row-major arrays in and out, of dimensions dims, with the guru planner,
+// which loops over the other axes without any transposes or copies.
+func planDftAxis(dims []int, axis int, in, out *C.fftw_complex, dir Direction, flag Flag) *Plan {
+	if axis < 0 || axis >= len(dims) {
+		panic(fmt.Sprint("Can't transform along axis ", axis, " of an array of dimensions ", dims))
+	}
+	strides := make([]int, len(dims))
+	stride := 1
+	for i := len(dims) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= dims[i]
+	}
+	var loop []C.fftw_iodim64
+	for i, d := range dims {
+		if i != axis {
+			loop = append(loop, C.fftw_iodim64{n: C.ptrdiff_t(d), is: C.ptrdiff_t(strides[i]), os: C.ptrdiff_t(strides[i])})
+		}
+	}
+	// fftw wants a valid pointer even for an empty loop.
+	loop = append(loop, C.fftw_iodim64{})
+	transform := C.fftw_iodim64{n: C.ptrdiff_t(dims[axis]), is: C.ptrdiff_t(strides[axis]), os: C.ptrdiff_t(strides[axis])}
+	planner.Lock()
+	p := C.fftw_plan_guru64_dft(1, &transform, C.int(len(dims)-1), &loop[0], in, out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	geom := Geometry{Kind: C2C, Dims: append([]int(nil), dims...), Dir: dir, Axes: []int{axis}}
+	return newPlan(p, geom, unsafe.Pointer(in), unsafe.Pointer(out))
+}
+
+// PlanDftAxis2d plans one dimensional transforms along axis 0 or 1 of in,
+// leaving the other axis alone: along axis 1 each row is transformed, along
+// axis 0 each column.
+func PlanDftAxis2d(in, out [][]complex128, axis int, dir Direction, flag Flag) *Plan {
+	dims := []int{len(in), len(in[0])}
+	if len(out) != dims[0] || len(out[0]) != dims[1] {
+		panic(fmt.Sprint("PlanDftAxis2d needs arrays of the same dimensions, got ", dims, " and ", []int{len(out), len(out[0])}))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0][0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0][0]))
+	return planDftAxis(dims, axis, fftw_in, fftw_out, dir, flag)
+}
+
+// PlanDftAxis3d plans one dimensional transforms along axis 0, 1 or 2 of
+// in, leaving the other axes alone.
+func PlanDftAxis3d(in, out [][][]complex128, axis int, dir Direction, flag Flag) *Plan {
+	dims := []int{len(in), len(in[0]), len(in[0][0])}
+	if len(out) != dims[0] || len(out[0]) != dims[1] || len(out[0][0]) != dims[2] {
+		panic(fmt.Sprint("PlanDftAxis3d needs arrays of the same dimensions, got ", dims, " and ", []int{len(out), len(out[0]), len(out[0][0])}))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0][0][0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0][0][0]))
+	return planDftAxis(dims, axis, fftw_in, fftw_out, dir, flag)
+}
+
+// PlanDftAxisNd plans one dimensional transforms along one axis of the
+// row-major arrays in and out, whose dimensions are dims.
+func PlanDftAxisNd(dims []int, in, out []complex128, axis int, dir Direction, flag Flag) *Plan {
+	size := 1
+	for _, d := range dims {
+		if d < 1 {
+			panic(fmt.Sprint("PlanDftAxisNd needs positive dimensions, got ", dims))
+		}
+		size *= d
+	}
+	if len(in) != size || len(out) != size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	return planDftAxis(dims, axis, fftw_in, fftw_out, dir, flag)
+}
@@ -0,0 +1,82 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func DftAxisSpec(c gospec.Context) {
+	fill := func(x []complex128) {
+		for i := range x {
+			x[i] = complex(float64(i*7%5), float64(i%3))
+		}
+	}
+
+	c.Specify("Transforming along each axis of a 2d array transforms its rows or columns.", func() {
+		in := Alloc2d(3, 4)
+		fill(in[0][:12])
+		out := Alloc2d(3, 4)
+		PlanDftAxis2d(in, out, 1, Forward, Estimate).Execute()
+		for i := range in {
+			want := make([]complex128, 4)
+			PlanDft1d(append([]complex128(nil), in[i]...), want, Forward, Estimate).Execute()
+			for j := range want {
+				c.Expect(cmplx.Abs(out[i][j]-want[j]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		PlanDftAxis2d(in, out, 0, Forward, Estimate).Execute()
+		for j := range in[0] {
+			col := []complex128{in[0][j], in[1][j], in[2][j]}
+			want := make([]complex128, 3)
+			PlanDft1d(col, want, Forward, Estimate).Execute()
+			for i := range want {
+				c.Expect(cmplx.Abs(out[i][j]-want[i]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	c.Specify("Transforming along every axis in turn is the full transform.", func() {
+		dims := []int{2, 3, 4}
+		in := make([]complex128, 24)
+		fill(in)
+		a := append([]complex128(nil), in...)
+		b := make([]complex128, 24)
+		for axis := range dims {
+			PlanDftAxisNd(dims, a, b, axis, Forward, Estimate).Execute()
+			a, b = b, a
+		}
+		want := make([]complex128, 24)
+		DftNd(dims, in, want, Forward, Estimate)
+		for i := range want {
+			c.Expect(cmplx.Abs(a[i]-want[i]), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("3d arrays can be transformed along their middle axis.", func() {
+		in := Alloc3d(2, 3, 2)
+		fill(in[0][0][:12])
+		out := Alloc3d(2, 3, 2)
+		p := PlanDftAxis3d(in, out, 1, Backward, Estimate)
+		p.Execute()
+		for i := range in {
+			for k := range in[0][0] {
+				col := []complex128{in[i][0][k], in[i][1][k], in[i][2][k]}
+				want := make([]complex128, 3)
+				PlanDft1d(col, want, Backward, Estimate).Execute()
+				for j := range want {
+					c.Expect(cmplx.Abs(out[i][j][k]-want[j]), gospec.IsWithin(1e-9), 0.0)
+				}
+			}
+		}
+		g := p.Geometry()
+		c.Expect(g.Axes, gospec.ContainsExactly, []int{1})
+		full := Geometry{Kind: C2C, Dims: []int{2, 3, 2}, Dir: Backward}
+		c.Expect(g.Equal(full), gospec.IsFalse)
+		c.Expect(g.Key() == full.Key(), gospec.IsFalse)
+	})
+
+	c.Specify("Axes outside the array are refused.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanDftAxisNd([]int{4}, make([]complex128, 4), make([]complex128, 4), 1, Forward, Estimate)
+	})
+}
@@ -0,0 +1,89 @@
+package fftw
+
+// A Band is a named range of frequencies, from Low up to but not including
+// High.
+type Band struct {
+	Name      string
+	Low, High float64
+}
+
+// EEGBands are the conventional EEG frequency bands, in Hz.
+var EEGBands = []Band{
+	{"delta", 0.5, 4},
+	{"theta", 4, 8},
+	{"alpha", 8, 13},
+	{"beta", 13, 30},
+}
+
+// BandPower integrates the power spectral density psd, sampled at the
+// evenly spaced frequencies freqs, over the range [low, high).
+func BandPower(freqs, psd []float64, low, high float64) float64 {
+	if len(freqs) < 2 {
+		return 0
+	}
+	df := freqs[1] - freqs[0]
+	p := 0.0
+	for i, f := range freqs {
+		if f >= low && f < high {
+			p += psd[i] * df
+		}
+	}
+	return p
+}
+
+// BandPowers returns the power in each of bands.
+func BandPowers(freqs, psd []float64, bands []Band) []float64 {
+	p := make([]float64, len(bands))
+	for i, b := range bands {
+		p[i] = BandPower(freqs, psd, b.Low, b.High)
+	}
+	return p
+}
+
+// RelativeBandPowers returns the power in each of bands as a fraction of the
+// total power of the spectrum.
+func RelativeBandPowers(freqs, psd []float64, bands []Band) []float64 {
+	p := BandPowers(freqs, psd, bands)
+	total := 0.0
+	if len(freqs) > 1 {
+		total = BandPower(freqs, psd, freqs[0], freqs[len(freqs)-1]+freqs[1]-freqs[0])
+	}
+	if total > 0 {
+		ScaleReal(p, 1/total)
+	}
+	return p
+}
+
+// A PSDEstimator estimates the power spectral density of x, returning the
+// frequency of each bin and the density there.  For example
+//
+//	func(x []float64, rate float64) ([]float64, []float64) {
+//		return MultitaperPSD(x, 4, 7, rate)
+//	}
+type PSDEstimator func(x []float64, sampleRate float64) ([]float64, []float64)
+
+// SlidingBandPowers estimates the PSD of each window of length samples of x,
+// starting every step samples, and returns the power in each of bands for
+// each window.  If relative is set the powers are fractions of each window's
+// total power.  If psd is nil a single Hann windowed periodogram of each
+// window is used.
+func SlidingBandPowers(x []float64, sampleRate float64, length, step int, bands []Band, relative bool, psd PSDEstimator) [][]float64 {
+	if psd == nil {
+		psd = func(x []float64, sampleRate float64) ([]float64, []float64) {
+			return WelchPSD(x, WelchOptions{SegmentLength: len(x), SampleRate: sampleRate})
+		}
+	}
+	if step < 1 {
+		step = 1
+	}
+	var powers [][]float64
+	for s := 0; s+length <= len(x); s += step {
+		freqs, p := psd(x[s:s+length], sampleRate)
+		if relative {
+			powers = append(powers, RelativeBandPowers(freqs, p, bands))
+		} else {
+			powers = append(powers, BandPowers(freqs, p, bands))
+		}
+	}
+	return powers
+}
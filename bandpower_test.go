@@ -0,0 +1,58 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+)
+
+func BandPowerSpec(c gospec.Context) {
+	rate := 256.0
+	x := make([]float64, 2048)
+	for i := range x {
+		t := float64(i) / rate
+		// An alpha rhythm of amplitude 2 in the first half, beta of
+		// amplitude 1 in the second.
+		if i < len(x)/2 {
+			x[i] = 2 * math.Sin(2*math.Pi*10*t)
+		} else {
+			x[i] = math.Sin(2 * math.Pi * 20 * t)
+		}
+	}
+
+	c.Specify("Welch's PSD integrates to the mean square of the signal.", func() {
+		rng := rand.New(rand.NewSource(4))
+		noise := make([]float64, 8192)
+		for i := range noise {
+			noise[i] = 3 * rng.NormFloat64()
+		}
+		freqs, psd := WelchPSD(noise, WelchOptions{SegmentLength: 256, Overlap: 128, SampleRate: 1000})
+		c.Expect(len(freqs), gospec.Equals, 129)
+		total := BandPower(freqs, psd, 0, 1000)
+		c.Expect(total, gospec.IsWithin(0.5), 9.0)
+	})
+
+	c.Specify("Band power finds the power of a sinusoid in its band.", func() {
+		freqs, psd := WelchPSD(x[:1024], WelchOptions{SegmentLength: 256, Overlap: 128, SampleRate: rate})
+		p := BandPowers(freqs, psd, EEGBands)
+		// A sinusoid of amplitude 2 has a mean square of 2.
+		c.Expect(p[2], gospec.IsWithin(0.1), 2.0)
+		c.Expect(p[3], gospec.IsWithin(0.01), 0.0)
+		r := RelativeBandPowers(freqs, psd, EEGBands)
+		c.Expect(r[2], gospec.IsWithin(0.01), 1.0)
+	})
+
+	c.Specify("Sliding band powers follow the signal over time.", func() {
+		powers := SlidingBandPowers(x, rate, 512, 512, EEGBands, true, nil)
+		c.Expect(len(powers), gospec.Equals, 4)
+		c.Expect(powers[0][2] > 0.9, gospec.IsTrue)
+		c.Expect(powers[3][3] > 0.9, gospec.IsTrue)
+		mt := func(x []float64, rate float64) ([]float64, []float64) {
+			return MultitaperPSD(x, 3, 5, rate)
+		}
+		absolute := SlidingBandPowers(x, rate, 512, 1024, EEGBands, false, mt)
+		c.Expect(len(absolute), gospec.Equals, 2)
+		c.Expect(absolute[0][2], gospec.IsWithin(0.1), 2.0)
+		c.Expect(absolute[1][3], gospec.IsWithin(0.05), 0.5)
+	})
+}
@@ -0,0 +1,45 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// SteeringDelays returns the time, in seconds, at which a plane wave from
+// direction reaches each of the sensors at positions, relative to the time
+// it reaches the origin.  direction is a unit vector pointing from the array
+// towards the source, and speed is the speed of the wave, for example 343
+// m/s for sound in air with positions in metres.
+func SteeringDelays(positions [][3]float64, direction [3]float64, speed float64) []float64 {
+	delays := make([]float64, len(positions))
+	for i, p := range positions {
+		delays[i] = -(p[0]*direction[0] + p[1]*direction[1] + p[2]*direction[2]) / speed
+	}
+	return delays
+}
+
+// DelayAndSum steers an array towards the source whose wave reaches each
+// channel delays[c] seconds late, by advancing every channel's spectrum by
+// its delay and averaging across channels.  spectra holds one single-sided
+// spectrum per channel, such as the output of DftFrames on one block of each
+// channel, and freqs the frequency of each bin in Hz.  Sound from the steered
+// direction adds up coherently, sound from elsewhere partly cancels.
+func DelayAndSum(spectra [][]complex128, freqs, delays []float64) []complex128 {
+	if len(spectra) != len(delays) {
+		panic(fmt.Sprint("DelayAndSum needs one delay per channel, got ", len(delays), " for ", len(spectra), " channels"))
+	}
+	out := make([]complex128, len(freqs))
+	for c, s := range spectra {
+		if len(s) != len(freqs) {
+			panic(fmt.Sprint("DelayAndSum channel ", c, " has ", len(s), " bins, expected ", len(freqs)))
+		}
+		for k, f := range freqs {
+			out[k] += s[k] * cmplx.Rect(1, 2*math.Pi*f*delays[c])
+		}
+	}
+	if len(spectra) > 0 {
+		Scale(out, 1/float64(len(spectra)))
+	}
+	return out
+}
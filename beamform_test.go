@@ -0,0 +1,52 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+	"math/rand"
+)
+
+func DelayAndSumSpec(c gospec.Context) {
+	// At 3400 Hz sound travels 0.1 m per sample, so a wave along the x axis
+	// reaches each of these sensors a whole number of samples apart.
+	rate, speed := 3400.0, 340.0
+	positions := [][3]float64{{0, 0, 0}, {0.1, 0, 0}, {0.2, 0, 0}, {0.3, 0, 0}}
+	rng := rand.New(rand.NewSource(7))
+	source := make([]float64, 64)
+	for i := range source {
+		source[i] = rng.NormFloat64()
+	}
+	direction := [3]float64{-1, 0, 0}
+	delays := SteeringDelays(positions, direction, speed)
+	channels := make([][]float64, len(positions))
+	for i := range channels {
+		channels[i] = CircularShift(source, delays[i]*rate)
+	}
+	spectra := DftFrames(channels)
+	want := DftFrames([][]float64{source})[0]
+	freqs := rfftFreqs(len(source), rate)
+
+	c.Specify("Steering delays grow along the direction of travel.", func() {
+		for i := range delays {
+			c.Expect(delays[i]*rate, gospec.IsWithin(1e-9), float64(i))
+		}
+	})
+
+	c.Specify("Steering towards the source recovers its spectrum.", func() {
+		out := DelayAndSum(spectra, freqs, delays)
+		for k := range out {
+			c.Expect(cmplx.Abs(out[k]-want[k]), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("Steering elsewhere picks up less power.", func() {
+		on := DelayAndSum(spectra, freqs, delays)
+		off := DelayAndSum(spectra, freqs, SteeringDelays(positions, [3]float64{0, 1, 0}, speed))
+		var pon, poff float64
+		for k := range on {
+			pon += real(on[k])*real(on[k]) + imag(on[k])*imag(on[k])
+			poff += real(off[k])*real(off[k]) + imag(off[k])*imag(off[k])
+		}
+		c.Expect(poff < pon/2, gospec.IsTrue)
+	})
+}
@@ -0,0 +1,84 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// BinStats keeps the running mean, variance, minimum and maximum of each
+// bin over a stream of frames, such as power spectra, without storing the
+// frames.  The mean and variance are updated with Welford's algorithm, which
+// stays accurate over millions of frames.
+type BinStats struct {
+	n        int
+	mean, m2 []float64
+	min, max []float64
+}
+
+// NewBinStats returns an empty accumulator for frames of the given number of
+// bins.
+func NewBinStats(bins int) *BinStats {
+	s := &BinStats{
+		mean: make([]float64, bins),
+		m2:   make([]float64, bins),
+		min:  make([]float64, bins),
+		max:  make([]float64, bins),
+	}
+	for k := range s.min {
+		s.min[k] = math.Inf(1)
+		s.max[k] = math.Inf(-1)
+	}
+	return s
+}
+
+// Add accumulates a frame.  It doesn't allocate.
+func (s *BinStats) Add(frame []float64) {
+	if len(frame) != len(s.mean) {
+		panic(fmt.Sprint("BinStats has ", len(s.mean), " bins, got a frame of ", len(frame)))
+	}
+	s.n++
+	n := float64(s.n)
+	for k, v := range frame {
+		d := v - s.mean[k]
+		s.mean[k] += d / n
+		s.m2[k] += d * (v - s.mean[k])
+		if v < s.min[k] {
+			s.min[k] = v
+		}
+		if v > s.max[k] {
+			s.max[k] = v
+		}
+	}
+}
+
+// Count returns the number of frames accumulated.
+func (s *BinStats) Count() int {
+	return s.n
+}
+
+// Mean returns the mean of each bin.
+func (s *BinStats) Mean() []float64 {
+	return append([]float64(nil), s.mean...)
+}
+
+// Variance returns the unbiased sample variance of each bin, which is zero
+// until at least two frames have been accumulated.
+func (s *BinStats) Variance() []float64 {
+	v := make([]float64, len(s.m2))
+	if s.n > 1 {
+		for k := range v {
+			v[k] = s.m2[k] / float64(s.n-1)
+		}
+	}
+	return v
+}
+
+// Min returns the smallest value seen in each bin, +Inf before any frames.
+func (s *BinStats) Min() []float64 {
+	return append([]float64(nil), s.min...)
+}
+
+// Max returns the largest value seen in each bin, -Inf before any frames.
+func (s *BinStats) Max() []float64 {
+	return append([]float64(nil), s.max...)
+}
@@ -0,0 +1,42 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func BinStatsSpec(c gospec.Context) {
+	frames := [][]float64{
+		{1, 10, -2},
+		{3, 10, 4},
+		{2, 10, 1},
+		{6, 10, 5},
+	}
+	s := NewBinStats(3)
+	for _, f := range frames {
+		s.Add(f)
+	}
+
+	c.Specify("Bin statistics match the two-pass formulas.", func() {
+		c.Expect(s.Count(), gospec.Equals, 4)
+		mean := s.Mean()
+		variance := s.Variance()
+		for k := range mean {
+			sum := 0.0
+			for _, f := range frames {
+				sum += f[k]
+			}
+			m := sum / 4
+			ss := 0.0
+			for _, f := range frames {
+				ss += (f[k] - m) * (f[k] - m)
+			}
+			c.Expect(mean[k], gospec.IsWithin(1e-12), m)
+			c.Expect(variance[k], gospec.IsWithin(1e-12), ss/3)
+		}
+	})
+
+	c.Specify("Bin statistics track the extremes.", func() {
+		c.Expect(s.Min(), gospec.ContainsInOrder, []float64{1, 10, -2})
+		c.Expect(s.Max(), gospec.ContainsInOrder, []float64{6, 10, 5})
+	})
+}
@@ -0,0 +1,188 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// FilterMethod selects how SeparableFilter2d filters an image.
+type FilterMethod int
+
+const (
+	// AutoFilter filters directly with kernels shorter than the crossover
+	// and by FFT convolution otherwise.
+	AutoFilter FilterMethod = iota
+	// SpatialFilter convolves each row and column directly, which takes
+	// time in proportion to the kernel length.
+	SpatialFilter
+	// FFTFilter convolves each row and column with Convolve, which takes
+	// time in proportion to the log of the row and column lengths.
+	FFTFilter
+)
+
+// filterCrossover is the kernel length from which AutoFilter uses FFTs.
+var filterCrossover int32 = 48
+
+// FilterCrossover returns the kernel length from which AutoFilter uses FFT
+// convolution.
+func FilterCrossover() int {
+	return int(atomic.LoadInt32(&filterCrossover))
+}
+
+// SetFilterCrossover sets the kernel length from which AutoFilter uses FFT
+// convolution.
+func SetFilterCrossover(n int) {
+	if n < 1 {
+		panic(fmt.Sprint("SetFilterCrossover needs a positive length, got ", n))
+	}
+	atomic.StoreInt32(&filterCrossover, int32(n))
+}
+
+// MeasureFilterCrossover times both methods on an n x n image with kernels
+// of growing length, sets the crossover to the first length at which FFT
+// convolution wins and returns it.  The crossover depends on the machine
+// and the fftw build, so programs that filter a lot can measure it once at
+// startup; without measuring a typical crossover is used.
+func MeasureFilterCrossover(n int) int {
+	x := alloc2dReal(n, n)
+	for i := range x {
+		for j := range x[i] {
+			x[i][j] = float64((i*31 + j*17) % 13)
+		}
+	}
+	best := 2*n + 1
+	for m := 3; m <= 2*n+1; m = 2*m - 1 {
+		k := Window1d(m, Hann)
+		spatial := timeFilter(x, k, SpatialFilter)
+		fft := timeFilter(x, k, FFTFilter)
+		if fft < spatial {
+			best = m
+			break
+		}
+	}
+	SetFilterCrossover(best)
+	return best
+}
+
+// timeFilter returns the quickest of a few runs of filtering x with k.
+func timeFilter(x [][]float64, k []float64, method FilterMethod) time.Duration {
+	best := time.Duration(math.MaxInt64)
+	for try := 0; try < 3; try++ {
+		start := time.Now()
+		SeparableFilter2d(x, k, k, Reflect, method)
+		if d := time.Since(start); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// SeparableFilter2d returns x convolved with the separable kernel whose
+// row kernel is rows and whose column kernel is cols, the same size as x
+// and centred as Convolve centres Same output, with x extended past its
+// edges as boundary says.  method picks the algorithm; the results agree
+// to within rounding.
+func SeparableFilter2d(x [][]float64, rows, cols []float64, boundary Boundary, method FilterMethod) [][]float64 {
+	if len(rows) == 0 || len(cols) == 0 {
+		panic("SeparableFilter2d needs non-empty kernels")
+	}
+	if method == AutoFilter {
+		method = SpatialFilter
+		if len(rows)+len(cols) >= 2*FilterCrossover() {
+			method = FFTFilter
+		}
+	}
+	h := len(x)
+	if h == 0 {
+		return nil
+	}
+	w := len(x[0])
+	out := alloc2dReal(h, w)
+	for i := range x {
+		copy(out[i], filter1d(x[i], rows, boundary, method))
+	}
+	col := make([]float64, h)
+	for j := 0; j < w; j++ {
+		for i := range out {
+			col[i] = out[i][j]
+		}
+		for i, v := range filter1d(col, cols, boundary, method) {
+			out[i][j] = v
+		}
+	}
+	return out
+}
+
+func filter1d(x, h []float64, boundary Boundary, method FilterMethod) []float64 {
+	if method == FFTFilter {
+		return Convolve(x, h, Same, boundary)
+	}
+	n, m := len(x), len(h)
+	start := (m - 1) / 2
+	out := make([]float64, n)
+	for i := range out {
+		sum := 0.0
+		for j, v := range h {
+			if k := boundary.index(i+start-j, n); k >= 0 {
+				sum += v * x[k]
+			}
+		}
+		out[i] = sum
+	}
+	return out
+}
+
+// GaussianBlur2d blurs x with a Gaussian of standard deviation sigma
+// pixels, truncated at three standard deviations, using whichever method
+// the crossover favours.
+func GaussianBlur2d(x [][]float64, sigma float64, boundary Boundary) [][]float64 {
+	if sigma <= 0 {
+		panic(fmt.Sprint("GaussianBlur2d needs a positive sigma, got ", sigma))
+	}
+	r := int(math.Ceil(3 * sigma))
+	k := make([]float64, 2*r+1)
+	for i := range k {
+		d := float64(i - r)
+		k[i] = math.Exp(-d * d / (2 * sigma * sigma))
+	}
+	ScaleReal(k, 1/sum(k))
+	return SeparableFilter2d(x, k, k, boundary, AutoFilter)
+}
+
+// BoxBlur2d replaces each pixel of x with the mean of the 2r+1 x 2r+1
+// pixels around it.  It sums with an integral image, which takes the same
+// time whatever the radius and so beats both of SeparableFilter2d's
+// methods.
+func BoxBlur2d(x [][]float64, r int, boundary Boundary) [][]float64 {
+	if r < 0 {
+		panic(fmt.Sprint("BoxBlur2d needs a non-negative radius, got ", r))
+	}
+	h := len(x)
+	if h == 0 {
+		return nil
+	}
+	w := len(x[0])
+	// sat[i][j] is the sum of the padded image above and left of (i, j).
+	sat := alloc2dReal(h+2*r+1, w+2*r+1)
+	for i := 1; i < len(sat); i++ {
+		row := boundary.index(i-1-r, h)
+		for j := 1; j < len(sat[i]); j++ {
+			v := 0.0
+			if col := boundary.index(j-1-r, w); row >= 0 && col >= 0 {
+				v = x[row][col]
+			}
+			sat[i][j] = v + sat[i-1][j] + sat[i][j-1] - sat[i-1][j-1]
+		}
+	}
+	out := alloc2dReal(h, w)
+	area := float64((2*r + 1) * (2*r + 1))
+	for i := range out {
+		for j := range out[i] {
+			a, b := i+2*r+1, j+2*r+1
+			out[i][j] = (sat[a][b] - sat[i][b] - sat[a][j] + sat[i][j]) / area
+		}
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func SeparableFilterSpec(c gospec.Context) {
+	x := alloc2dReal(12, 10)
+	for i := range x {
+		for j := range x[i] {
+			x[i][j] = float64((i*7+j*3)%11) - 5
+		}
+	}
+
+	c.Specify("Spatial and FFT filtering agree.", func() {
+		for _, b := range []Boundary{ZeroBoundary, Reflect, Periodic, Replicate} {
+			for _, m := range []int{1, 3, 4, 9} {
+				rows := Window1d(m, Hann)
+				cols := Window1d(m+2, Tukey(0.5))
+				a := SeparableFilter2d(x, rows, cols, b, SpatialFilter)
+				f := SeparableFilter2d(x, rows, cols, b, FFTFilter)
+				for i := range a {
+					for j := range a[i] {
+						c.Expect(f[i][j], gospec.IsWithin(1e-9), a[i][j])
+					}
+				}
+			}
+		}
+	})
+
+	c.Specify("Blurs keep constant images constant.", func() {
+		flat := alloc2dReal(8, 8)
+		for i := range flat {
+			for j := range flat[i] {
+				flat[i][j] = 3
+			}
+		}
+		for _, y := range [][][]float64{GaussianBlur2d(flat, 1.5, Reflect), BoxBlur2d(flat, 2, Replicate)} {
+			for i := range y {
+				for j := range y[i] {
+					c.Expect(y[i][j], gospec.IsWithin(1e-9), 3.0)
+				}
+			}
+		}
+	})
+
+	c.Specify("A box blur averages the pixels around each pixel.", func() {
+		y := BoxBlur2d(x, 1, ZeroBoundary)
+		want := 0.0
+		for i := 4; i <= 6; i++ {
+			for j := 2; j <= 4; j++ {
+				want += x[i][j]
+			}
+		}
+		c.Expect(y[5][3], gospec.IsWithin(1e-9), want/9)
+		for _, b := range []Boundary{ZeroBoundary, Reflect, Periodic, Replicate} {
+			k := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+			want := SeparableFilter2d(x, k, k, b, SpatialFilter)
+			got := BoxBlur2d(x, 2, b)
+			for i := range want {
+				for j := range want[i] {
+					c.Expect(got[i][j], gospec.IsWithin(1e-9), want[i][j])
+				}
+			}
+		}
+	})
+
+	c.Specify("The crossover can be set and measured.", func() {
+		defer SetFilterCrossover(FilterCrossover())
+		SetFilterCrossover(5)
+		c.Expect(FilterCrossover(), gospec.Equals, 5)
+		n := MeasureFilterCrossover(16)
+		c.Expect(n >= 3 && n <= 33, gospec.IsTrue)
+		c.Expect(FilterCrossover(), gospec.Equals, n)
+	})
+}
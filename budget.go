@@ -0,0 +1,121 @@
+package fftw
+
+import (
+	"fmt"
+	"time"
+)
+
+// BudgetOptions configures a BudgetedPlan.
+type BudgetOptions struct {
+	// Budget is the time each execution should take at most.
+	Budget time.Duration
+	// Misses is the number of executions in a row that must overrun the
+	// budget before it is reported and a cheaper plan is used, 3 if it is
+	// zero.
+	Misses int
+	// Recover, if positive, is the number of executions in a row that must
+	// finish within half the budget before a dearer plan is tried again.
+	Recover int
+	// Report, if not nil, is called on the executing goroutine each time
+	// the budget is repeatedly missed.
+	Report func(r BudgetReport)
+}
+
+// A BudgetReport describes repeatedly missed deadlines.
+type BudgetReport struct {
+	// Level is the index of the plan that missed them, and Downgraded is
+	// true if a cheaper plan is used from now on.
+	Level      int
+	Downgraded bool
+	// Elapsed is the time the last execution took.
+	Elapsed, Budget time.Duration
+	// Misses counts every overrun so far.
+	Misses int
+}
+
+// A BudgetedPlan executes plans against a time budget, for real-time
+// analyzers that would rather lose resolution than fall behind.  It holds
+// a ladder of plans, from the one it should run to ever cheaper ones such
+// as transforms of decimated data, and steps down the ladder when the
+// budget is repeatedly missed.  Callers check Level to see which plan's
+// arrays to fill.  A BudgetedPlan is not safe for concurrent use.
+type BudgetedPlan struct {
+	opts   BudgetOptions
+	plans  []*Plan
+	level  int
+	late   int
+	early  int
+	runs   int
+	misses int
+	// since measures executions, and is replaced by tests.
+	since func(t time.Time) time.Duration
+}
+
+// NewBudgetedPlan returns a BudgetedPlan that starts with the first of
+// plans and falls back to the later ones in turn.
+func NewBudgetedPlan(opts BudgetOptions, plans ...*Plan) *BudgetedPlan {
+	if len(plans) == 0 || opts.Budget <= 0 {
+		panic(fmt.Sprint("NewBudgetedPlan needs a plan and a positive budget, got ", len(plans), " plans and ", opts.Budget))
+	}
+	if opts.Misses <= 0 {
+		opts.Misses = 3
+	}
+	return &BudgetedPlan{opts: opts, plans: plans, since: time.Since}
+}
+
+// Level returns the index of the plan that Execute runs next.
+func (b *BudgetedPlan) Level() int {
+	return b.level
+}
+
+// Plan returns the plan that Execute runs next.
+func (b *BudgetedPlan) Plan() *Plan {
+	return b.plans[b.level]
+}
+
+// Stats returns the number of executions so far and how many of them
+// overran the budget.
+func (b *BudgetedPlan) Stats() (runs, misses int) {
+	return b.runs, b.misses
+}
+
+// Reset returns to the first plan and forgets any missed deadlines.
+func (b *BudgetedPlan) Reset() {
+	b.level, b.late, b.early = 0, 0, 0
+}
+
+// Execute runs the current plan, timing it against the budget, and reports
+// whether it finished in time.
+func (b *BudgetedPlan) Execute() bool {
+	start := time.Now()
+	b.plans[b.level].Execute()
+	elapsed := b.since(start)
+	b.runs++
+	if elapsed <= b.opts.Budget {
+		b.late = 0
+		if elapsed <= b.opts.Budget/2 {
+			b.early++
+		} else {
+			b.early = 0
+		}
+		if b.opts.Recover > 0 && b.early >= b.opts.Recover && b.level > 0 {
+			b.level--
+			b.early = 0
+		}
+		return true
+	}
+	b.misses++
+	b.early = 0
+	if b.late++; b.late >= b.opts.Misses {
+		r := BudgetReport{Level: b.level, Elapsed: elapsed, Budget: b.opts.Budget, Misses: b.misses}
+		if b.level+1 < len(b.plans) {
+			b.level++
+			r.Downgraded = true
+		}
+		b.late = 0
+		if b.opts.Report != nil {
+			b.opts.Report(r)
+		}
+	}
+	return false
+}
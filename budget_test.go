@@ -0,0 +1,58 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"time"
+)
+
+func BudgetedPlanSpec(c gospec.Context) {
+	full := PlanDft1d(make([]complex128, 16), make([]complex128, 16), Forward, Estimate)
+	half := PlanDft1d(make([]complex128, 8), make([]complex128, 8), Forward, Estimate)
+	var reports []BudgetReport
+	b := NewBudgetedPlan(BudgetOptions{Budget: time.Millisecond, Misses: 2, Recover: 3, Report: func(r BudgetReport) {
+		reports = append(reports, r)
+	}}, full, half)
+	// Each execution takes the next of these times.
+	var times []time.Duration
+	b.since = func(time.Time) time.Duration {
+		t := times[0]
+		times = times[1:]
+		return t
+	}
+
+	c.Specify("Repeated overruns are reported and step down to a cheaper plan.", func() {
+		times = []time.Duration{2 * time.Millisecond, 0, 2 * time.Millisecond, 2 * time.Millisecond}
+		c.Expect(b.Execute(), gospec.IsFalse)
+		c.Expect(b.Execute(), gospec.IsTrue)
+		c.Expect(b.Execute(), gospec.IsFalse)
+		c.Expect(len(reports), gospec.Equals, 0)
+		c.Expect(b.Execute(), gospec.IsFalse)
+		c.Expect(reports, gospec.ContainsExactly, []BudgetReport{
+			{Level: 0, Downgraded: true, Elapsed: 2 * time.Millisecond, Budget: time.Millisecond, Misses: 3},
+		})
+		c.Expect(b.Level(), gospec.Equals, 1)
+		c.Expect(b.Plan() == half, gospec.IsTrue)
+		runs, misses := b.Stats()
+		c.Expect(runs, gospec.Equals, 4)
+		c.Expect(misses, gospec.Equals, 3)
+
+		// Overruns on the cheapest plan are still reported.
+		times = []time.Duration{2 * time.Millisecond, 2 * time.Millisecond}
+		b.Execute()
+		b.Execute()
+		c.Expect(len(reports), gospec.Equals, 2)
+		c.Expect(reports[1].Downgraded, gospec.IsFalse)
+		c.Expect(b.Level(), gospec.Equals, 1)
+	})
+
+	c.Specify("Plenty of headroom steps back up.", func() {
+		times = []time.Duration{2 * time.Millisecond, 2 * time.Millisecond, 0, 0, 800 * time.Microsecond, 0, 0, 0}
+		for range times {
+			b.Execute()
+		}
+		c.Expect(b.Level(), gospec.Equals, 0)
+		b.level = 1
+		b.Reset()
+		c.Expect(b.Level(), gospec.Equals, 0)
+	})
+}
@@ -0,0 +1,80 @@
+package fftw
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// RealCepstrum returns the real cepstrum of x, the inverse transform of the
+// log magnitude of its spectrum.  Sample i of the cepstrum corresponds to a
+// quefrency of i samples, see Quefrencies.
+func RealCepstrum(x []float64) []float64 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	signal := make([]float64, n)
+	copy(signal, x)
+	F_signal := make([]complex128, n/2+1)
+	PlanDftR2C1d(signal, F_signal, Estimate).Execute()
+	for i, v := range F_signal {
+		// Keep silent bins from turning into -Inf.
+		F_signal[i] = complex(math.Log(cmplx.Abs(v)+1e-300), 0)
+	}
+	PlanDftC2R1d(F_signal, signal, Estimate).Execute()
+	for i := range signal {
+		signal[i] /= float64(n)
+	}
+	return signal
+}
+
+// Quefrencies returns the quefrency, in seconds, of each sample of the
+// cepstrum of an n sample signal sampled at sampleRate.
+func Quefrencies(n int, sampleRate float64) []float64 {
+	q := make([]float64, n)
+	for i := range q {
+		q[i] = float64(i) / sampleRate
+	}
+	return q
+}
+
+// An Echo is a delayed copy of a signal found by DetectEcho.
+type Echo struct {
+	// Delay is the delay of the echo in seconds.
+	Delay float64
+	// Quefrency is the delay of the echo in samples.
+	Quefrency int
+	// Peak is the height of the cepstral peak, which grows with the
+	// relative amplitude of the echo.
+	Peak float64
+}
+
+// DetectEcho finds the strongest echo in x with a delay between minDelay and
+// maxDelay seconds by picking the largest peak of its real cepstrum.  Only
+// delays of up to half the length of x can be detected; ok is false if no
+// delay in the range can be.
+func DetectEcho(x []float64, sampleRate, minDelay, maxDelay float64) (e Echo, ok bool) {
+	c := RealCepstrum(x)
+	lo := int(math.Ceil(minDelay * sampleRate))
+	hi := int(math.Floor(maxDelay * sampleRate))
+	if lo < 1 {
+		lo = 1
+	}
+	// The cepstrum of a real signal is symmetric, only the first half is
+	// informative.
+	if hi > len(c)/2 {
+		hi = len(c) / 2
+	}
+	if lo > hi {
+		return Echo{}, false
+	}
+	e.Peak = math.Inf(-1)
+	for i := lo; i <= hi; i++ {
+		if c[i] > e.Peak {
+			e.Peak = c[i]
+			e.Quefrency = i
+		}
+	}
+	e.Delay = float64(e.Quefrency) / sampleRate
+	return e, true
+}
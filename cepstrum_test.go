@@ -0,0 +1,50 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/rand"
+)
+
+func CepstrumSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(1))
+	x := make([]float64, 1024)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	delay := 37
+	y := make([]float64, len(x))
+	for i := range y {
+		y[i] = x[i]
+		if i >= delay {
+			y[i] += 0.6 * x[i-delay]
+		}
+	}
+
+	c.Specify("The cepstrum of a signal with an echo peaks at the echo's delay.", func() {
+		e, ok := DetectEcho(y, 1000, 0.005, 0.2)
+		c.Expect(ok, gospec.IsTrue)
+		c.Expect(e.Quefrency, gospec.Equals, delay)
+		c.Expect(e.Delay, gospec.IsWithin(1e-12), 0.037)
+		c.Expect(e.Peak > 0.1, gospec.IsTrue)
+	})
+
+	c.Specify("Delays past half the signal can't be detected.", func() {
+		_, ok := DetectEcho(y, 1000, float64(len(y)), float64(2*len(y)))
+		c.Expect(ok, gospec.IsFalse)
+		_, ok = DetectEcho(y, 1000, 0.2, 0.1)
+		c.Expect(ok, gospec.IsFalse)
+	})
+
+	c.Specify("The quefrency axis is in seconds.", func() {
+		q := Quefrencies(4, 8000)
+		c.Expect(q, gospec.ContainsInOrder, []float64{0, 1.0 / 8000, 2.0 / 8000, 3.0 / 8000})
+	})
+
+	c.Specify("The cepstrum of an impulse is zero.", func() {
+		impulse := make([]float64, 8)
+		impulse[0] = 1
+		for _, v := range RealCepstrum(impulse) {
+			c.Expect(v, gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+}
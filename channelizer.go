@@ -0,0 +1,171 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// A Channelizer splits a complex baseband stream into channels evenly spaced
+// in frequency with a weighted overlap-add polyphase filterbank.  Channel k
+// is centred on k/channels cycles per sample and is decimated by the
+// channelizer's decimation.  Decimating by half the number of channels or
+// less lets the matching Synthesizer recombine the channels into the
+// original stream.  A Channelizer is not safe for concurrent use.
+type Channelizer struct {
+	m, d     int
+	h        []float64
+	history  []complex128
+	rot      int
+	folded   []complex128
+	spectrum []complex128
+	plan     *Plan
+}
+
+// NewChannelizer returns a channelizer with the given number of channels,
+// whose outputs are decimated by decimation, using a Hann windowed sinc
+// prototype filter taps*channels+1 samples long.  More taps give sharper
+// channel edges and a more accurate round trip at the cost of more work
+// and latency.
+func NewChannelizer(channels, decimation, taps int) *Channelizer {
+	if channels < 1 || decimation < 1 || decimation > channels || taps < 1 {
+		panic(fmt.Sprint("NewChannelizer needs positive channels, taps and a decimation no more than channels, got ", channels, ", ", decimation, " and ", taps))
+	}
+	c := &Channelizer{m: channels, d: decimation}
+	c.h = channelPrototype(channels, taps, float64(channels))
+	ScaleReal(c.h, float64(channels)/sum(c.h))
+	c.history = make([]complex128, len(c.h)-decimation)
+	c.folded = make([]complex128, channels)
+	c.spectrum = make([]complex128, channels)
+	c.plan = PlanDft1d(c.folded, c.spectrum, Forward, Estimate)
+	return c
+}
+
+// channelPrototype returns a lowpass filter of taps*m+1 samples with its
+// cutoff at 1/(2 period) cycles per sample.  With a period of m it is a
+// Nyquist filter, zero at every m'th sample from its centre, so the
+// channels it makes add up to a flat response.
+func channelPrototype(m, taps int, period float64) []float64 {
+	h := Window1d(taps*m+1, Hann)
+	mid := taps * m / 2
+	for l := range h {
+		h[l] *= sinc(float64(l-mid) / period)
+	}
+	return h
+}
+
+func sum(x []float64) float64 {
+	s := 0.0
+	for _, v := range x {
+		s += v
+	}
+	return s
+}
+
+// Channels returns the number of channels.
+func (c *Channelizer) Channels() int {
+	return c.m
+}
+
+// Decimation returns the number of input samples per output frame.
+func (c *Channelizer) Decimation() int {
+	return c.d
+}
+
+// Process adds the samples x to the stream, returning one frame, holding a
+// sample of every channel, for each Decimation samples the stream has
+// gained.  Samples left over are kept for the next call.
+func (c *Channelizer) Process(x []complex128) [][]complex128 {
+	c.history = append(c.history, x...)
+	frames := 0
+	if len(c.history) >= len(c.h) {
+		frames = (len(c.history)-len(c.h))/c.d + 1
+	}
+	out := make([][]complex128, frames)
+	data := make([]complex128, frames*c.m)
+	for t := range out {
+		seg := c.history[t*c.d : t*c.d+len(c.h)]
+		for r := range c.folded {
+			c.folded[r] = 0
+		}
+		// Fold the segment by its position in the stream, rather than in
+		// the segment, so that each channel comes out at baseband.
+		r := c.rot
+		for l, v := range seg {
+			c.folded[r] += v * complex(c.h[l], 0)
+			if r++; r == c.m {
+				r = 0
+			}
+		}
+		c.plan.Execute()
+		out[t] = data[t*c.m : (t+1)*c.m]
+		copy(out[t], c.spectrum)
+		c.rot = (c.rot + c.d) % c.m
+	}
+	c.history = append(c.history[:0], c.history[frames*c.d:]...)
+	return out
+}
+
+// Synthesizer returns a synthesis bank that recombines the channels of c
+// back into a stream.
+func (c *Channelizer) Synthesizer() *Synthesizer {
+	s := &Synthesizer{m: c.m, d: c.d}
+	// The synthesis filter is twice as wide as the analysis filter, flat
+	// across each channel and its transition bands, and scaled for the
+	// overlap of its copies and the unnormalized inverse transform.
+	// Images of the channels fall outside it as long as the decimation is
+	// no more than half the number of channels.
+	s.g = channelPrototype(c.m, (len(c.h)-1)/c.m, float64(c.m)/2)
+	ScaleReal(s.g, float64(c.d)/(sum(s.g)*float64(c.m)))
+	s.acc = make([]complex128, len(c.h))
+	s.spectrum = make([]complex128, c.m)
+	s.samples = make([]complex128, c.m)
+	s.plan = PlanDft1d(s.spectrum, s.samples, Backward, Estimate)
+	return s
+}
+
+// A Synthesizer is the inverse of a Channelizer, recombining frames of
+// channel samples into a wideband stream, so that channels can be processed
+// separately and put back together.  Its output lags the channelizer's input
+// by Delay samples.  A Synthesizer is not safe for concurrent use.
+type Synthesizer struct {
+	m, d     int
+	g        []float64
+	acc      []complex128
+	rot      int
+	spectrum []complex128
+	samples  []complex128
+	plan     *Plan
+}
+
+// Delay returns the number of samples by which the synthesizer's output
+// lags the channelizer's input.
+func (s *Synthesizer) Delay() int {
+	return len(s.g) - s.d
+}
+
+// Process recombines frames, each holding one sample of every channel in
+// the order a Channelizer made them, returning Decimation samples of the
+// stream per frame.
+func (s *Synthesizer) Process(frames [][]complex128) []complex128 {
+	out := make([]complex128, 0, len(frames)*s.d)
+	for _, f := range frames {
+		if len(f) != s.m {
+			panic(fmt.Sprint("Synthesizer frame has ", len(f), " channels, expected ", s.m))
+		}
+		copy(s.spectrum, f)
+		s.plan.Execute()
+		r := s.rot
+		for l, w := range s.g {
+			s.acc[l] += s.samples[r] * complex(w, 0)
+			if r++; r == s.m {
+				r = 0
+			}
+		}
+		out = append(out, s.acc[:s.d]...)
+		copy(s.acc, s.acc[s.d:])
+		for l := len(s.acc) - s.d; l < len(s.acc); l++ {
+			s.acc[l] = 0
+		}
+		s.rot = (s.rot + s.d) % s.m
+	}
+	return out
+}
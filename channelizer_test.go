@@ -0,0 +1,80 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func ChannelizerSpec(c gospec.Context) {
+	const channels = 16
+	x := make([]complex128, 4096)
+	for i := range x {
+		// A tone in the middle of channel 3 and a weaker one in channel 10.
+		x[i] = cmplx.Rect(1, 2*math.Pi*3*float64(i)/channels) + cmplx.Rect(0.5, 2*math.Pi*10.2*float64(i)/channels)
+	}
+
+	c.Specify("Tones come out of the channels they fall in.", func() {
+		ch := NewChannelizer(channels, channels/2, 8)
+		frames := ch.Process(x)
+		c.Expect(len(frames), gospec.Equals, len(x)/(channels/2))
+		power := make([]float64, channels)
+		for _, f := range frames[len(frames)/2:] {
+			for k, v := range f {
+				power[k] += real(v)*real(v) + imag(v)*imag(v)
+			}
+		}
+		for k := range power {
+			if k != 3 && k != 10 {
+				c.Expect(power[k] < 1e-3*power[3], gospec.IsTrue)
+			}
+		}
+		c.Expect(power[10] > 0.1*power[3], gospec.IsTrue)
+	})
+
+	c.Specify("Feeding a stream in pieces gives the same frames.", func() {
+		whole := NewChannelizer(channels, 4, 4).Process(x[:1000])
+		ch := NewChannelizer(channels, 4, 4)
+		var pieces [][]complex128
+		for i := 0; i < 1000; i += 77 {
+			end := i + 77
+			if end > 1000 {
+				end = 1000
+			}
+			pieces = append(pieces, ch.Process(x[i:end])...)
+		}
+		c.Expect(len(pieces), gospec.Equals, len(whole))
+		for t := range whole {
+			for k := range whole[t] {
+				c.Expect(cmplx.Abs(pieces[t][k]-whole[t][k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	c.Specify("The synthesizer recombines the channels into the stream.", func() {
+		ch := NewChannelizer(channels, channels/2, 8)
+		syn := ch.Synthesizer()
+		y := syn.Process(ch.Process(x))
+		y = append(y, syn.Process(ch.Process(make([]complex128, syn.Delay()+channels)))...)
+		worst := 0.0
+		for i := range x {
+			worst = math.Max(worst, cmplx.Abs(y[i+syn.Delay()]-x[i]))
+		}
+		c.Expect(worst < 1e-2, gospec.IsTrue)
+	})
+
+	c.Specify("Channels can be processed separately before synthesis.", func() {
+		ch := NewChannelizer(channels, channels/2, 8)
+		syn := ch.Synthesizer()
+		frames := ch.Process(x)
+		for _, f := range frames {
+			f[3] = 0
+		}
+		y := syn.Process(frames)
+		// Only the tone in channel 10 is left.
+		for i := len(y) / 2; i < len(y); i++ {
+			want := cmplx.Rect(0.5, 2*math.Pi*10.2*float64(i-syn.Delay())/channels)
+			c.Expect(cmplx.Abs(y[i]-want) < 1e-2, gospec.IsTrue)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package fftw
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// graphCheckpoint is the saved state of a Graph.  The states of its source,
+// nodes and sinks are nil for those that don't implement
+// encoding.BinaryMarshaler.
+type graphCheckpoint struct {
+	Blocks int
+	Source []byte
+	Nodes  [][]byte
+	Sinks  [][]byte
+}
+
+// CheckpointTo makes g save its state to the file at path after every
+// every blocks and when its run is cancelled, so that a long job can be
+// resumed with Restore after a restart.  The state is the number of blocks
+// done and the state of each source, node and sink that implements
+// encoding.BinaryMarshaler; anything else must be stateless or rebuilt by
+// the caller.  The checkpoint is removed once the source is exhausted.
+func (g *Graph) CheckpointTo(path string, every int) *Graph {
+	if every < 1 {
+		panic(fmt.Sprint("CheckpointTo needs a positive interval, got ", every))
+	}
+	g.checkpointPath = path
+	g.checkpointEvery = every
+	return g
+}
+
+func marshalState(v interface{}) ([]byte, error) {
+	if m, ok := v.(encoding.BinaryMarshaler); ok {
+		return m.MarshalBinary()
+	}
+	return nil, nil
+}
+
+func unmarshalState(v interface{}, state []byte) error {
+	if state == nil {
+		return nil
+	}
+	u, ok := v.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("checkpoint has state for a %T, which can't restore it", v)
+	}
+	return u.UnmarshalBinary(state)
+}
+
+// saveCheckpoint writes g's state to its checkpoint file, replacing the old
+// one only once the new one is complete.
+func (g *Graph) saveCheckpoint(blocks int) error {
+	cp := graphCheckpoint{Blocks: blocks}
+	var err error
+	if cp.Source, err = marshalState(g.source); err != nil {
+		return err
+	}
+	for _, n := range g.nodes {
+		state, err := marshalState(n)
+		if err != nil {
+			return err
+		}
+		cp.Nodes = append(cp.Nodes, state)
+	}
+	for _, s := range g.sinks {
+		state, err := marshalState(s)
+		if err != nil {
+			return err
+		}
+		cp.Sinks = append(cp.Sinks, state)
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(cp); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(g.checkpointPath), filepath.Base(g.checkpointPath)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), g.checkpointPath)
+}
+
+// Restore loads the state saved by a graph built the same way as g from
+// g's checkpoint file, so that the next run carries on where that graph
+// stopped.  It returns false if there is no checkpoint to restore.
+func (g *Graph) Restore() (bool, error) {
+	if g.checkpointPath == "" {
+		panic("Restore needs a graph with a checkpoint file")
+	}
+	data, err := os.ReadFile(g.checkpointPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var cp graphCheckpoint
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cp); err != nil {
+		return false, err
+	}
+	if len(cp.Nodes) != len(g.nodes) || len(cp.Sinks) != len(g.sinks) {
+		return false, fmt.Errorf("checkpoint is of a graph with %d nodes and %d sinks, not %d and %d", len(cp.Nodes), len(cp.Sinks), len(g.nodes), len(g.sinks))
+	}
+	if err := unmarshalState(g.source, cp.Source); err != nil {
+		return false, err
+	}
+	for i, n := range g.nodes {
+		if err := unmarshalState(n, cp.Nodes[i]); err != nil {
+			return false, err
+		}
+	}
+	for i, s := range g.sinks {
+		if err := unmarshalState(s, cp.Sinks[i]); err != nil {
+			return false, err
+		}
+	}
+	g.start = cp.Blocks
+	return true, nil
+}
+
+func (s *sliceSource) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(s.pos)
+	return b.Bytes(), err
+}
+
+func (s *sliceSource) UnmarshalBinary(data []byte) error {
+	var pos int
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&pos); err != nil {
+		return err
+	}
+	if pos > len(s.x) {
+		return fmt.Errorf("checkpoint is %d samples into a source of %d", pos, len(s.x))
+	}
+	s.pos = pos
+	return nil
+}
+
+func (s *SampleCollector) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(s.Samples)
+	return b.Bytes(), err
+}
+
+func (s *SampleCollector) UnmarshalBinary(data []byte) error {
+	s.Samples = nil
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(&s.Samples)
+}
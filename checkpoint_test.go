@@ -0,0 +1,70 @@
+package fftw
+
+import (
+	"context"
+	"github.com/orfjackal/gospec/src/gospec"
+	"os"
+	"path/filepath"
+)
+
+func CheckpointSpec(c gospec.Context) {
+	x := make([]float64, 400)
+	for i := range x {
+		x[i] = float64(i*i%17) - 8
+	}
+	dir, err := os.MkdirTemp("", "fftw-checkpoint")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "job")
+	opts := WelchOptions{SegmentLength: 32, Overlap: 16}
+	_, want := WelchPSD(x, opts)
+
+	c.Specify("A cancelled graph resumes from its checkpoint.", func() {
+		acc := NewPSDAccumulator(opts)
+		var out SampleCollector
+		ctx, cancel := context.WithCancel(context.Background())
+		n, err := NewGraph(20, SliceSource(x)).To(&out).To(acc).CheckpointTo(path, 3).
+			RunContext(ctx, func(p Progress) {
+				if p.Done == 7 {
+					cancel()
+				}
+			})
+		c.Expect(err, gospec.Equals, context.Canceled)
+		c.Expect(n, gospec.Equals, 7)
+
+		// A fresh process rebuilds the graph and restores it.
+		acc = NewPSDAccumulator(opts)
+		out = SampleCollector{}
+		var indices []int
+		g := NewGraph(20, SliceSource(x)).To(&out).To(acc).
+			To(SinkFunc(func(f *Frame) { indices = append(indices, f.Index) })).
+			CheckpointTo(path, 3)
+		ok, err := g.Restore()
+		c.Expect(err, gospec.Not(gospec.IsNil))
+		c.Expect(ok, gospec.IsFalse)
+
+		g = NewGraph(20, SliceSource(x)).To(&out).To(acc).CheckpointTo(path, 3)
+		ok, err = g.Restore()
+		c.Expect(err, gospec.IsNil)
+		c.Expect(ok, gospec.IsTrue)
+		var reports []Progress
+		n, err = g.RunContext(context.Background(), func(p Progress) { reports = append(reports, p) })
+		c.Expect(err, gospec.IsNil)
+		c.Expect(n, gospec.Equals, 20)
+		c.Expect(reports[0].Done, gospec.Equals, 8)
+		c.Expect(reports[0].Total, gospec.Equals, 20)
+		c.Expect(out.Samples, gospec.ContainsInOrder, x)
+		_, psd := acc.PSD()
+		for k := range psd {
+			c.Expect(psd[k], gospec.IsWithin(1e-12), want[k])
+		}
+
+		_, err = os.Stat(path)
+		c.Expect(os.IsNotExist(err), gospec.IsTrue)
+		ok, err = g.Restore()
+		c.Expect(ok, gospec.IsFalse)
+		c.Expect(err, gospec.IsNil)
+	})
+}
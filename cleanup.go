@@ -0,0 +1,42 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// livePlans counts the plans made and not yet destroyed.
+var livePlans int64
+
+// LivePlans returns the number of plans that have been made and not yet
+// destroyed, by Destroy or by being garbage collected.
+func LivePlans() int {
+	return int(atomic.LoadInt64(&livePlans))
+}
+
+// Cleanup frees all of fftw's accumulated planner state, including its
+// wisdom and the tables it keeps for the sizes it has planned, as
+// fftw_cleanup_threads does, so that long running processes and test
+// suites can release it deterministically.  It returns an error, and does
+// nothing, while any plan is alive, since fftw can't clean up under one;
+// Destroy plans first.  No plans may be made while it runs.  Afterwards
+// plans can be made as before, with the same number of threads.
+func Cleanup() error {
+	threadsLock.Lock()
+	defer threadsLock.Unlock()
+	if n := LivePlans(); n > 0 {
+		return fmt.Errorf("can't clean up fftw while %d plans are alive", n)
+	}
+	C.fftw_cleanup_threads()
+	makePlannerThreadSafe()
+	initThreadsLock.Lock()
+	threadsReady = false
+	initThreadsLock.Unlock()
+	if planThreads != 1 {
+		setThreads(planThreads)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package fftw
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func CleanupSpec(c gospec.Context) {
+	c.Specify("Plans are counted until they are destroyed.", func() {
+		x := make([]complex128, 8)
+		before := LivePlans()
+		p := PlanDft1d(x, x, Forward, Estimate)
+		c.Expect(LivePlans(), gospec.Equals, before+1)
+		p.Destroy()
+		p.Destroy()
+		c.Expect(LivePlans(), gospec.Equals, before)
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		p.Execute()
+	})
+
+	c.Specify("Cleanup waits for every plan to be destroyed.", func() {
+		x := make([]complex128, 8)
+		p := PlanDft1d(x, x, Forward, Estimate)
+		c.Expect(Cleanup(), gospec.Not(gospec.IsNil))
+		p.Destroy()
+		for i := 0; i < 100 && LivePlans() > 0; i++ {
+			runtime.GC()
+			time.Sleep(time.Millisecond)
+		}
+		c.Expect(Cleanup(), gospec.IsNil)
+
+		// Plans work as before afterwards.
+		x[1] = 1
+		p = PlanDft1d(x, x, Forward, Estimate)
+		p.Execute()
+		c.Expect(imag(x[2]), gospec.IsWithin(1e-12), -1.0)
+		p.Destroy()
+	})
+}
@@ -0,0 +1,118 @@
+package fftw
+
+// ConvolveMode selects which part of the full convolution Convolve returns.
+// The modes match those of scipy.signal.convolve.
+type ConvolveMode int
+
+const (
+	// Full returns all len(x)+len(h)-1 samples of the convolution.
+	Full ConvolveMode = iota
+	// Same returns len(x) samples, centred with respect to Full.
+	Same
+	// Valid returns only the samples that don't depend on the boundary,
+	// of which there are max(len(x), len(h)) - min(len(x), len(h)) + 1.
+	Valid
+)
+
+// Boundary selects how Convolve extends x past its ends.
+type Boundary int
+
+const (
+	// ZeroBoundary treats x as zero outside of its ends.
+	ZeroBoundary Boundary = iota
+	// Reflect mirrors x about its ends, repeating the edge samples
+	// (d c b a | a b c d | d c b a).
+	Reflect
+	// Periodic wraps x around (a b c d | a b c d | a b c d).
+	Periodic
+	// Replicate repeats the edge samples (a a a a | a b c d | d d d d).
+	Replicate
+)
+
+// index maps index i, which may lie outside of [0, n), onto the
+// index of x it takes its value from, or -1 if it is zero.
+func (b Boundary) index(i, n int) int {
+	if i >= 0 && i < n {
+		return i
+	}
+	switch b {
+	case Reflect:
+		i %= 2 * n
+		if i < 0 {
+			i += 2 * n
+		}
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+		return i
+	case Periodic:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+		return i
+	case Replicate:
+		if i < 0 {
+			return 0
+		}
+		return n - 1
+	}
+	return -1
+}
+
+// Convolve returns the convolution of x with the kernel h, computed with
+// real-to-complex transforms.  See ConvolveMode and Boundary for the
+// meaning of mode and boundary.
+func Convolve(x, h []float64, mode ConvolveMode, boundary Boundary) []float64 {
+	n, m := len(x), len(h)
+	if n == 0 || m == 0 {
+		return nil
+	}
+
+	// Extend x by m-1 samples at each end, so that every output sample
+	// that depends on the boundary can see it.
+	pad := 0
+	if boundary != ZeroBoundary {
+		pad = m - 1
+	}
+	size := n + 2*pad + m - 1
+	signal := scratch.reals(size)
+	defer scratch.releaseReals(signal)
+	for i := range signal[:n+2*pad] {
+		if j := boundary.index(i-pad, n); j >= 0 {
+			signal[i] = x[j]
+		}
+	}
+	kernel := scratch.reals(size)
+	defer scratch.releaseReals(kernel)
+	copy(kernel, h)
+
+	F_signal := scratch.complexes(size/2 + 1)
+	defer scratch.releaseComplexes(F_signal)
+	F_kernel := scratch.complexes(size/2 + 1)
+	defer scratch.releaseComplexes(F_kernel)
+	PlanDftR2C1d(signal, F_signal, Estimate).Execute()
+	PlanDftR2C1d(kernel, F_kernel, Estimate).Execute()
+	scale := complex(1/float64(size), 0)
+	for i := range F_signal {
+		F_signal[i] *= F_kernel[i] * scale
+	}
+	PlanDftC2R1d(F_signal, signal, Estimate).Execute()
+
+	full := signal[pad : pad+n+m-1]
+	var r []float64
+	switch mode {
+	case Same:
+		start := (m - 1) / 2
+		r = full[start : start+n]
+	case Valid:
+		if n >= m {
+			r = full[m-1 : n]
+		} else {
+			r = full[n-1 : m]
+		}
+	default:
+		r = full
+	}
+	return append([]float64(nil), r...)
+}
@@ -0,0 +1,68 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+// directConvolve is the O(n*m) reference for Convolve.
+func directConvolve(x, h []float64, boundary Boundary) []float64 {
+	n, m := len(x), len(h)
+	r := make([]float64, n+m-1)
+	for k := range r {
+		for j := range h {
+			if i := boundary.index(k-j, n); i >= 0 {
+				r[k] += h[j] * x[i]
+			}
+		}
+	}
+	return r
+}
+
+func ConvolveSpec(c gospec.Context) {
+	x := []float64{1, 4, -2, 3, 0, 5, 7, -1, 2}
+	h := []float64{0.5, -1, 2, 0.25}
+
+	c.Specify("Full convolution matches direct convolution for every boundary.", func() {
+		for _, b := range []Boundary{ZeroBoundary, Reflect, Periodic, Replicate} {
+			expected := directConvolve(x, h, b)
+			y := Convolve(x, h, Full, b)
+			c.Expect(len(y), gospec.Equals, len(x)+len(h)-1)
+			for i := range y {
+				c.Expect(y[i], gospec.IsWithin(1e-9), expected[i])
+			}
+		}
+	})
+
+	c.Specify("Same convolution is centred like scipy's.", func() {
+		full := directConvolve(x, h, Reflect)
+		y := Convolve(x, h, Same, Reflect)
+		c.Expect(len(y), gospec.Equals, len(x))
+		for i := range y {
+			c.Expect(y[i], gospec.IsWithin(1e-9), full[i+1])
+		}
+	})
+
+	c.Specify("Valid convolution only keeps samples that ignore the boundary.", func() {
+		full := directConvolve(x, h, ZeroBoundary)
+		y := Convolve(x, h, Valid, Periodic)
+		c.Expect(len(y), gospec.Equals, len(x)-len(h)+1)
+		for i := range y {
+			c.Expect(y[i], gospec.IsWithin(1e-9), full[i+len(h)-1])
+		}
+		c.Expect(len(Convolve(h, x, Valid, ZeroBoundary)), gospec.Equals, len(x)-len(h)+1)
+	})
+
+	c.Specify("Boundaries extend the signal the way they should.", func() {
+		idx := func(b Boundary) []int {
+			r := []int{}
+			for i := -4; i < 8; i++ {
+				r = append(r, b.index(i, 4))
+			}
+			return r
+		}
+		c.Expect(idx(Reflect), gospec.ContainsInOrder, []int{3, 2, 1, 0, 0, 1, 2, 3, 3, 2, 1, 0})
+		c.Expect(idx(Periodic), gospec.ContainsInOrder, []int{0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3})
+		c.Expect(idx(Replicate), gospec.ContainsInOrder, []int{0, 0, 0, 0, 0, 1, 2, 3, 3, 3, 3, 3})
+		c.Expect(ZeroBoundary.index(-1, 4), gospec.Equals, -1)
+	})
+}
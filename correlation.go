@@ -0,0 +1,32 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// Overdensity3d returns the density contrast rho/mean(rho) - 1 of the
+// density grid rho, such as galaxy counts in cells.
+func Overdensity3d(rho [][][]float64) [][][]float64 {
+	dims, flat := flatten3d(rho)
+	mean := sum(flat) / float64(len(flat))
+	if mean <= 0 {
+		panic(fmt.Sprint("Overdensity3d needs a positive mean density, got ", mean))
+	}
+	for i := range flat {
+		flat[i] = flat[i]/mean - 1
+	}
+	return unflatten3d(dims, flat)
+}
+
+// CorrelationFunction3d returns the two-point correlation function of the
+// periodic density contrast delta, as Overdensity3d gives it: xi[r] is
+// <delta(p) delta(p+d)> averaged over every cell p and every separation d
+// whose length in cells is nearest r, so that xi[0] is the variance of
+// delta.  Multiply r by the cell size for physical separations.  It is
+// computed as the inverse transform of the power spectrum, in O(n log n)
+// time rather than by counting O(n²) pairs of cells.  Shot noise and the
+// smoothing of mass assignment are left for the caller to correct.
+func CorrelationFunction3d(delta [][][]float64) []float64 {
+	dims, flat := flatten3d(delta)
+	return shellAverage(dims, autocorrelation(dims, flat))
+}
@@ -0,0 +1,49 @@
+package fftw
+
+import (
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func CorrelationSpec(c gospec.Context) {
+	c.Specify("Overdensities have zero mean.", func() {
+		rho := [][][]float64{{{1, 3}, {2, 2}}}
+		d := Overdensity3d(rho)
+		c.Expect(d[0][0][0], gospec.IsWithin(1e-12), -0.5)
+		c.Expect(d[0][0][1], gospec.IsWithin(1e-12), 0.5)
+		c.Expect(d[0][1][0], gospec.IsWithin(1e-12), 0.0)
+	})
+
+	c.Specify("The correlation function matches counting pairs of cells.", func() {
+		rng := rand.New(rand.NewSource(4))
+		dims := []int{4, 6, 5}
+		rho := make([][][]float64, dims[0])
+		for i := range rho {
+			rho[i] = alloc2dReal(dims[1], dims[2])
+			for j := range rho[i] {
+				for k := range rho[i][j] {
+					rho[i][j][k] = float64(rng.Intn(5))
+				}
+			}
+		}
+		delta := Overdensity3d(rho)
+		xi := CorrelationFunction3d(delta)
+		sums := make([]float64, len(xi))
+		counts := make([]float64, len(xi))
+		forEachSeparation(dims, func(_ int, r []int) {
+			s := shellIndex(r)
+			for i := range delta {
+				for j := range delta[i] {
+					for k := range delta[i][j] {
+						sums[s] += delta[i][j][k] * delta[(i+r[0]+dims[0])%dims[0]][(j+r[1]+dims[1])%dims[1]][(k+r[2]+dims[2])%dims[2]]
+						counts[s]++
+					}
+				}
+			}
+		})
+		for r := range xi {
+			c.Expect(xi[r], gospec.IsWithin(1e-9), sums[r]/counts[r])
+		}
+	})
+}
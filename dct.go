@@ -0,0 +1,140 @@
+package fftw
+
+import (
+	"math"
+)
+
+// r2rScaled transforms the row-major array data, of dimensions dims, in
+// place with kind along every axis.  Each element is multiplied, before the
+// transform if before is set and after it otherwise, by the product over
+// the axes of scale(n)[i], where n is the length of the axis and i the
+// element's index along it.
+func r2rScaled(dims []int, data []float64, kind R2RKind, scale func(n int) []float64, before bool) {
+	factors := make([][]float64, len(dims))
+	for a, n := range dims {
+		factors[a] = scale(n)
+	}
+	apply := func() {
+		index := make([]int, len(dims))
+		for i := range data {
+			f := 1.0
+			for a := range dims {
+				f *= factors[a][index[a]]
+			}
+			data[i] *= f
+			// Step the row-major index.
+			for a := len(dims) - 1; a >= 0; a-- {
+				index[a]++
+				if index[a] < dims[a] {
+					break
+				}
+				index[a] = 0
+			}
+		}
+	}
+	if before {
+		apply()
+	}
+	kinds := make([]R2RKind, len(dims))
+	for a := range kinds {
+		kinds[a] = kind
+	}
+	PlanR2RNd(dims, data, data, kinds, Estimate).Execute()
+	if !before {
+		apply()
+	}
+}
+
+// flatten2d and flatten3d copy an array into a new row-major slice, and
+// unflatten2d and unflatten3d slice such a slice back into rows.
+func flatten2d(x [][]float64) ([]int, []float64) {
+	dims := []int{len(x), len(x[0])}
+	flat := make([]float64, 0, dims[0]*dims[1])
+	for _, row := range x {
+		flat = append(flat, row...)
+	}
+	return dims, flat
+}
+
+func unflatten2d(dims []int, flat []float64) [][]float64 {
+	r := make([][]float64, dims[0])
+	for i := range r {
+		r[i] = flat[i*dims[1] : (i+1)*dims[1]]
+	}
+	return r
+}
+
+func flatten3d(x [][][]float64) ([]int, []float64) {
+	dims := []int{len(x), len(x[0]), len(x[0][0])}
+	flat := make([]float64, 0, dims[0]*dims[1]*dims[2])
+	for _, plane := range x {
+		for _, row := range plane {
+			flat = append(flat, row...)
+		}
+	}
+	return dims, flat
+}
+
+func unflatten3d(dims []int, flat []float64) [][][]float64 {
+	r := make([][][]float64, dims[0])
+	for i := range r {
+		r[i] = unflatten2d(dims[1:], flat[i*dims[1]*dims[2]:(i+1)*dims[1]*dims[2]])
+	}
+	return r
+}
+
+// dctScale returns the factors that turn fftw's REDFT10 into the
+// orthonormal DCT-II, and REDFT01 into its inverse.
+func dctScale(n int) []float64 {
+	s := make([]float64, n)
+	s[0] = math.Sqrt(1 / float64(4*n))
+	for k := 1; k < n; k++ {
+		s[k] = math.Sqrt(1 / float64(2*n))
+	}
+	return s
+}
+
+// idctScale returns the factors applied to the input of REDFT01 to invert
+// the orthonormal DCT-II.
+func idctScale(n int) []float64 {
+	s := dctScale(n)
+	s[0] *= 2
+	return s
+}
+
+// Dct1d returns the orthonormal DCT-II of x, the transform used by JPEG and
+// for MFCCs, X[k] = f(k) * sum(x[j]*cos(pi*k*(j+1/2)/n)) with f(0) = sqrt(1/n)
+// and f(k) = sqrt(2/n) otherwise.  Idct1d inverts it exactly.  The DCT-II
+// also diagonalizes Neumann problems on grids whose boundaries lie half way
+// between samples.
+func Dct1d(x []float64) []float64 {
+	out := append([]float64(nil), x...)
+	if len(out) > 0 {
+		r2rScaled([]int{len(out)}, out, REDFT10, dctScale, false)
+	}
+	return out
+}
+
+// Idct1d returns the inverse of Dct1d, the orthonormal DCT-III of x.
+func Idct1d(x []float64) []float64 {
+	out := append([]float64(nil), x...)
+	if len(out) > 0 {
+		r2rScaled([]int{len(out)}, out, REDFT01, idctScale, true)
+	}
+	return out
+}
+
+// Dct2d returns the orthonormal DCT-II of x along both dimensions, as used
+// on the 8x8 blocks of JPEG.
+func Dct2d(x [][]float64) [][]float64 {
+	dims, flat := flatten2d(x)
+	r2rScaled(dims, flat, REDFT10, dctScale, false)
+	return unflatten2d(dims, flat)
+}
+
+// Idct2d returns the inverse of Dct2d.
+func Idct2d(x [][]float64) [][]float64 {
+	dims, flat := flatten2d(x)
+	r2rScaled(dims, flat, REDFT01, idctScale, true)
+	return unflatten2d(dims, flat)
+}
@@ -0,0 +1,68 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func DctSpec(c gospec.Context) {
+	x := []float64{3, -1, 4, 1, -5, 9, 2, -6}
+	n := len(x)
+
+	c.Specify("Dct1d is the orthonormal DCT-II.", func() {
+		X := Dct1d(x)
+		energy, spectral := 0.0, 0.0
+		for k := range X {
+			f := math.Sqrt(2 / float64(n))
+			if k == 0 {
+				f = math.Sqrt(1 / float64(n))
+			}
+			sum := 0.0
+			for j, v := range x {
+				sum += v * math.Cos(math.Pi*float64(k)*(float64(j)+0.5)/float64(n))
+			}
+			c.Expect(X[k], gospec.IsWithin(1e-9), f*sum)
+			energy += x[k] * x[k]
+			spectral += X[k] * X[k]
+		}
+		c.Expect(spectral, gospec.IsWithin(1e-9), energy)
+		back := Idct1d(X)
+		for i := range x {
+			c.Expect(back[i], gospec.IsWithin(1e-9), x[i])
+		}
+		c.Expect(len(Dct1d(nil)), gospec.Equals, 0)
+	})
+
+	c.Specify("Dct2d transforms both dimensions and Idct2d inverts it.", func() {
+		block := make([][]float64, 4)
+		for i := range block {
+			block[i] = make([]float64, 8)
+			for j := range block[i] {
+				block[i][j] = float64((i+1)*(j+2)%7) - 3
+			}
+		}
+		X := Dct2d(block)
+		// Rows first, then columns, one dimension at a time.
+		rows := make([][]float64, 4)
+		for i := range rows {
+			rows[i] = Dct1d(block[i])
+		}
+		for j := 0; j < 8; j++ {
+			col := make([]float64, 4)
+			for i := range col {
+				col[i] = rows[i][j]
+			}
+			col = Dct1d(col)
+			for i := range col {
+				c.Expect(X[i][j], gospec.IsWithin(1e-9), col[i])
+			}
+		}
+		back := Idct2d(X)
+		for i := range block {
+			for j := range block[i] {
+				c.Expect(back[i][j], gospec.IsWithin(1e-9), block[i][j])
+			}
+		}
+		c.Expect(block[1][1], gospec.Equals, float64(2*3%7)-3)
+	})
+}
@@ -0,0 +1,57 @@
+package fftw
+
+import (
+	"math"
+)
+
+// dstScale returns the factors that make fftw's RODFT00 orthonormal.
+func dstScale(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = 1 / math.Sqrt(float64(2*(n+1)))
+	}
+	return s
+}
+
+// Dst1d returns the orthonormal DST-I of x,
+// X[k] = sqrt(2/(n+1)) * sum(x[j]*sin(pi*(j+1)*(k+1)/(n+1))), which is its own
+// inverse.  Its basis functions vanish just beyond both ends of x, so it
+// diagonalizes the second difference operator on a grid with zero,
+// Dirichlet, boundaries: see DirichletEigenvalues.  Neumann problems on
+// grids whose boundaries lie half way between samples use Dct1d instead.
+func Dst1d(x []float64) []float64 {
+	out := append([]float64(nil), x...)
+	if len(out) > 0 {
+		r2rScaled([]int{len(out)}, out, RODFT00, dstScale, false)
+	}
+	return out
+}
+
+// Dst2d returns the orthonormal DST-I of x along both dimensions.
+func Dst2d(x [][]float64) [][]float64 {
+	dims, flat := flatten2d(x)
+	r2rScaled(dims, flat, RODFT00, dstScale, false)
+	return unflatten2d(dims, flat)
+}
+
+// Dst3d returns the orthonormal DST-I of x along all three dimensions.
+func Dst3d(x [][][]float64) [][][]float64 {
+	dims, flat := flatten3d(x)
+	r2rScaled(dims, flat, RODFT00, dstScale, false)
+	return unflatten3d(dims, flat)
+}
+
+// DirichletEigenvalues returns the eigenvalues of the second difference
+// (x[j-1] - 2x[j] + x[j+1])/h^2 on n points spaced h apart with zero
+// boundaries, in the order of the DST-I coefficients they scale.  To solve
+// a Poisson problem, transform the right hand side with Dst1d, divide each
+// coefficient by its eigenvalue, summed over the axes for Dst2d and Dst3d,
+// and transform back.
+func DirichletEigenvalues(n int, h float64) []float64 {
+	ev := make([]float64, n)
+	for k := range ev {
+		s := math.Sin(math.Pi * float64(k+1) / float64(2*(n+1)))
+		ev[k] = -4 * s * s / (h * h)
+	}
+	return ev
+}
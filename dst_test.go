@@ -0,0 +1,70 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func DstSpec(c gospec.Context) {
+	x := []float64{2, 7, 1, -8, 2, 8, -1}
+	n := len(x)
+
+	c.Specify("Dst1d is the orthonormal DST-I and its own inverse.", func() {
+		X := Dst1d(x)
+		for k := range X {
+			sum := 0.0
+			for j, v := range x {
+				sum += v * math.Sin(math.Pi*float64((j+1)*(k+1))/float64(n+1))
+			}
+			c.Expect(X[k], gospec.IsWithin(1e-9), math.Sqrt(2/float64(n+1))*sum)
+		}
+		back := Dst1d(X)
+		for i := range x {
+			c.Expect(back[i], gospec.IsWithin(1e-9), x[i])
+		}
+	})
+
+	c.Specify("Dst2d and Dst3d are their own inverses.", func() {
+		a := make([][]float64, 3)
+		for i := range a {
+			a[i] = []float64{float64(i), 1, -2, float64(i * i)}
+		}
+		back := Dst2d(Dst2d(a))
+		for i := range a {
+			for j := range a[i] {
+				c.Expect(back[i][j], gospec.IsWithin(1e-9), a[i][j])
+			}
+		}
+		b := make([][][]float64, 2)
+		for i := range b {
+			b[i] = [][]float64{{1, float64(i)}, {-3, 2}, {float64(i), 5}}
+		}
+		back3 := Dst3d(Dst3d(b))
+		for i := range b {
+			for j := range b[i] {
+				for k := range b[i][j] {
+					c.Expect(back3[i][j][k], gospec.IsWithin(1e-9), b[i][j][k])
+				}
+			}
+		}
+	})
+
+	c.Specify("The DST solves a Dirichlet Poisson problem.", func() {
+		h := 0.1
+		F := Dst1d(x)
+		ev := DirichletEigenvalues(n, h)
+		for k := range F {
+			F[k] /= ev[k]
+		}
+		u := Dst1d(F)
+		at := func(j int) float64 {
+			if j < 0 || j >= n {
+				return 0
+			}
+			return u[j]
+		}
+		for j := range u {
+			c.Expect((at(j-1)-2*at(j)+at(j+1))/(h*h), gospec.IsWithin(1e-9), x[j])
+		}
+	})
+}
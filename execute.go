@@ -0,0 +1,80 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// checkExecute panics unless p can be executed on arrays in and out, with
+// inLen and outLen elements, in place of the ones it was planned for.
+func (p *Plan) checkExecute(name string, kind Kind, in, out unsafe.Pointer, inLen, outLen int) {
+	if p.geom.Kind != kind {
+		panic(fmt.Sprint(name, " needs a ", kind, " plan, got a ", p.geom.Kind, " plan"))
+	}
+	if p.geom.Split {
+		panic(fmt.Sprint(name, " can't execute split plans"))
+	}
+	if p.prepare != nil {
+		panic(fmt.Sprint(name, " can't execute plans that emulate PreserveInput"))
+	}
+	if wantIn, wantOut := p.geom.extents(); inLen < wantIn || outLen < wantOut {
+		panic(fmt.Sprint(name, " needs arrays of at least ", wantIn, " and ", wantOut, " elements, got ", inLen, " and ", outLen))
+	}
+	if (in == out) != p.geom.InPlace {
+		panic(fmt.Sprint(name, " needs arrays that are in place exactly when the plan's are"))
+	}
+	if C.fftw_alignment_of((*C.double)(in)) != p.inAlign || C.fftw_alignment_of((*C.double)(out)) != p.outAlign {
+		panic(fmt.Sprint(name, " needs arrays with the same alignment as the plan's"))
+	}
+}
+
+// ExecuteDft executes the complex plan p on in and out instead of the
+// arrays it was planned for, so that one plan, made once with Measure, can
+// transform any number of buffers.  in and out must be at least as long as
+// the plan's arrays, in place exactly when they were, and have the same
+// alignment, as arrays from Alloc1d always do.
+func (p *Plan) ExecuteDft(in, out []complex128) {
+	fftw_in := unsafe.Pointer(&in[0])
+	fftw_out := unsafe.Pointer(&out[0])
+	p.checkExecute("ExecuteDft", C2C, fftw_in, fftw_out, len(in), len(out))
+	p.executeOn(fftw_in, fftw_out)
+}
+
+// ExecuteDftR2C executes the real-to-complex plan p on in and out, as
+// ExecuteDft does.
+func (p *Plan) ExecuteDftR2C(in []float64, out []complex128) {
+	fftw_in := unsafe.Pointer(&in[0])
+	fftw_out := unsafe.Pointer(&out[0])
+	inLen := len(in)
+	if fftw_in == fftw_out {
+		// In place, the real array takes up the complex one.
+		inLen = 2 * len(out)
+	}
+	p.checkExecute("ExecuteDftR2C", R2C, fftw_in, fftw_out, inLen, len(out))
+	p.executeOn(fftw_in, fftw_out)
+}
+
+// ExecuteDftC2R executes the complex-to-real plan p on in and out, as
+// ExecuteDft does.  Like the plan's own arrays, in is overwritten.
+func (p *Plan) ExecuteDftC2R(in []complex128, out []float64) {
+	fftw_in := unsafe.Pointer(&in[0])
+	fftw_out := unsafe.Pointer(&out[0])
+	outLen := len(out)
+	if fftw_in == fftw_out {
+		outLen = 2 * len(in)
+	}
+	p.checkExecute("ExecuteDftC2R", C2R, fftw_in, fftw_out, len(in), outLen)
+	p.executeOn(fftw_in, fftw_out)
+}
+
+// ExecuteR2R executes the real-to-real plan p on in and out, as ExecuteDft
+// does.
+func (p *Plan) ExecuteR2R(in, out []float64) {
+	fftw_in := unsafe.Pointer(&in[0])
+	fftw_out := unsafe.Pointer(&out[0])
+	p.checkExecute("ExecuteR2R", R2R, fftw_in, fftw_out, len(in), len(out))
+	p.executeOn(fftw_in, fftw_out)
+}
@@ -0,0 +1,62 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func ExecuteSpec(c gospec.Context) {
+	c.Specify("A complex plan transforms other arrays.", func() {
+		p := PlanDft1d(Alloc1d(8), Alloc1d(8), Forward, Estimate)
+		in, out := Alloc1d(8), Alloc1d(8)
+		for i := range in {
+			in[i] = complex(float64(i), float64(i%3))
+		}
+		want := make([]complex128, 8)
+		PlanDft1d(append([]complex128(nil), in...), want, Forward, Estimate).Execute()
+		p.ExecuteDft(in, out)
+		for k := range want {
+			c.Expect(real(out[k]), gospec.IsWithin(1e-9), real(want[k]))
+			c.Expect(imag(out[k]), gospec.IsWithin(1e-9), imag(want[k]))
+		}
+	})
+
+	c.Specify("Real plans transform other arrays in both directions.", func() {
+		p := PlanDftR2C1d(allocReal1d(6), Alloc1d(4), Estimate)
+		q := PlanDftC2R1d(Alloc1d(4), allocReal1d(6), Estimate)
+		in, spectrum, back := allocReal1d(6), Alloc1d(4), allocReal1d(6)
+		for i := range in {
+			in[i] = float64(i*i) - 3
+		}
+		p.ExecuteDftR2C(in, spectrum)
+		q.ExecuteDftC2R(spectrum, back)
+		for i := range in {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-9), in[i])
+		}
+	})
+
+	c.Specify("R2R plans transform other arrays.", func() {
+		p := PlanR2R1d(allocReal1d(5), allocReal1d(5), REDFT00, Estimate)
+		in, out := allocReal1d(5), allocReal1d(5)
+		in[0] = 1
+		p.ExecuteR2R(in, out)
+		for _, v := range out {
+			c.Expect(v, gospec.IsWithin(1e-9), 1.0)
+		}
+	})
+
+	c.Specify("Unsuitable arrays and plans are rejected.", func() {
+		p := PlanDft1d(Alloc1d(8), Alloc1d(8), Forward, Estimate)
+		expectPanic := func(f func()) {
+			defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+			f()
+		}
+		expectPanic(func() { p.ExecuteDft(Alloc1d(4), Alloc1d(8)) })
+		buf := Alloc1d(8)
+		expectPanic(func() { p.ExecuteDft(buf, buf) })
+		expectPanic(func() { p.ExecuteDftR2C(allocReal1d(8), Alloc1d(5)) })
+		r := PlanR2R1d(allocReal1d(5), allocReal1d(5), REDFT00, Estimate)
+		expectPanic(func() { r.ExecuteR2R(allocReal1d(6)[1:], allocReal1d(5)) })
+		q := PlanDftC2R2d(Alloc2d(2, 4), alloc2dReal(2, 6), Estimate|PreserveInput)
+		expectPanic(func() { q.ExecuteDftC2R(Alloc1d(8), allocReal1d(12)) })
+	})
+}
@@ -8,26 +8,109 @@ import (
 	"fmt"
 	"reflect"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"unsafe"
 )
 
 type Plan struct {
 	fftw_p C.fftw_plan
+	geom   Geometry
+	// The arrays p was planned for.  Holding them here keeps them alive for
+	// as long as p can execute on them.  For split plans in and out are the
+	// real parts and imIn and imOut the imaginary parts.
+	in, out     unsafe.Pointer
+	imIn, imOut unsafe.Pointer
+	// The fftw_alignment_of the arrays p was planned for, which the arrays
+	// of any new-array execution must share.
+	inAlign, outAlign C.int
+	// prepare, if set, is run before every execution.
+	prepare func()
 }
 
 func destroyPlan(p *Plan) {
+	planner.Lock()
 	C.fftw_destroy_plan(p.fftw_p)
+	planner.Unlock()
+	p.fftw_p = nil
+	atomic.AddInt64(&livePlans, -1)
 }
 
-func newPlan(fftw_p C.fftw_plan) *Plan {
+func newPlan(fftw_p C.fftw_plan, geom Geometry, in, out unsafe.Pointer) *Plan {
+	if fftw_p == nil {
+		panic(fmt.Sprint("fftw could not plan a transform of geometry ", geom))
+	}
 	np := new(Plan)
 	np.fftw_p = fftw_p
+	np.geom = geom
+	np.geom.InPlace = in == out
+	np.in, np.out = in, out
+	np.inAlign = C.fftw_alignment_of((*C.double)(in))
+	np.outAlign = C.fftw_alignment_of((*C.double)(out))
 	runtime.SetFinalizer(np, destroyPlan)
+	atomic.AddInt64(&livePlans, 1)
 	return np
 }
 
+// Destroy frees p's fftw plan now rather than when p is garbage collected,
+// after which p can't be executed.  Destroying a plan twice does nothing.
+func (p *Plan) Destroy() {
+	if p.fftw_p == nil {
+		return
+	}
+	runtime.SetFinalizer(p, nil)
+	destroyPlan(p)
+}
+
 func (p *Plan) Execute() {
+	if p.fftw_p == nil {
+		panic("Can't execute a destroyed plan")
+	}
+	if p.prepare != nil {
+		p.prepare()
+	}
 	C.fftw_execute(p.fftw_p)
+	// Don't let the finalizer destroy the plan while fftw is still using it.
+	runtime.KeepAlive(p)
+}
+
+// executeOn executes p on the arrays in and out instead of the ones it was
+// planned for, which fftw allows as long as they have the same alignment
+// and are in place exactly when those were.
+func (p *Plan) executeOn(in, out unsafe.Pointer) {
+	if p.fftw_p == nil {
+		panic("Can't execute a destroyed plan")
+	}
+	if p.geom.Split {
+		panic("Split plans can't be executed on other arrays")
+	}
+	switch p.geom.Kind {
+	case R2C:
+		C.fftw_execute_dft_r2c(p.fftw_p, (*C.double)(in), (*C.fftw_complex)(out))
+	case C2R:
+		C.fftw_execute_dft_c2r(p.fftw_p, (*C.fftw_complex)(in), (*C.double)(out))
+	case R2R:
+		C.fftw_execute_r2r(p.fftw_p, (*C.double)(in), (*C.double)(out))
+	default:
+		C.fftw_execute_dft(p.fftw_p, (*C.fftw_complex)(in), (*C.fftw_complex)(out))
+	}
+	runtime.KeepAlive(p)
+}
+
+// Geometry returns the shape of the transform p was planned for.
+func (p *Plan) Geometry() Geometry {
+	g := p.geom
+	g.Dims = append([]int(nil), g.Dims...)
+	if g.R2R != nil {
+		g.R2R = append([]R2RKind(nil), g.R2R...)
+	}
+	if g.Axes != nil {
+		g.Axes = append([]int(nil), g.Axes...)
+	}
+	if g.IO != nil {
+		g.IO = append([]IODim(nil), g.IO...)
+	}
+	return g
 }
 
 type Direction int
@@ -35,23 +118,93 @@ type Direction int
 var Forward Direction = C.FFTW_FORWARD
 var Backward Direction = C.FFTW_BACKWARD
 
+// A Flag tells the planner how to plan.  Flags are or'ed together, as in
+// Measure|PreserveInput: at most one of Estimate, Measure, Patient and
+// Exhaustive, which say how hard to look for a fast plan and default to
+// Measure, with any of the rest, which say what the plan may assume or do.
+// In strict mode, see SetStrict, plans are made with Estimate|Unaligned
+// whatever the first kind of flag asks for, and WisdomOnly is ignored.
 type Flag uint
 
+// Estimate picks a plan by a heuristic without running anything, so planning
+// is quick and leaves the arrays alone.  Measure times a number of plans and
+// keeps the fastest, and Patient and Exhaustive time ever more of them; all
+// three overwrite the arrays while planning, so fill them afterwards.
 var Estimate Flag = C.FFTW_ESTIMATE
 var Measure Flag = C.FFTW_MEASURE
+var Patient Flag = C.FFTW_PATIENT
+var Exhaustive Flag = C.FFTW_EXHAUSTIVE
 
-func Alloc1d(n int) []complex128 {
+// WisdomOnly makes a plan only if wisdom for it has been imported or made
+// earlier in the process; planning panics otherwise.
+var WisdomOnly Flag = C.FFTW_WISDOM_ONLY
+
+// PreserveInput may be or'ed into the flags of a complex-to-real plan to keep
+// it from destroying its input.
+var PreserveInput Flag = C.FFTW_PRESERVE_INPUT
+
+// DestroyInput lets an out of place plan overwrite its input, which can
+// make it faster.  It is the default for complex-to-real plans.
+var DestroyInput Flag = C.FFTW_DESTROY_INPUT
+
+// Unaligned plans without SIMD code that needs aligned arrays, so that the
+// plan can be executed on arrays of any alignment.
+var Unaligned Flag = C.FFTW_UNALIGNED
+
+// ConserveMemory prefers plans that use less memory, even if they are
+// slower.
+var ConserveMemory Flag = C.FFTW_CONSERVE_MEMORY
+
+var flagNames = []struct {
+	flag Flag
+	name string
+}{
+	{Estimate, "Estimate"},
+	{Patient, "Patient"},
+	{Exhaustive, "Exhaustive"},
+	{WisdomOnly, "WisdomOnly"},
+	{PreserveInput, "PreserveInput"},
+	{DestroyInput, "DestroyInput"},
+	{Unaligned, "Unaligned"},
+	{ConserveMemory, "ConserveMemory"},
+}
+
+// String returns f as the flags it is made of, as in "Measure|PreserveInput".
+func (f Flag) String() string {
+	var names []string
+	if f&(Estimate|Patient|Exhaustive) == 0 {
+		names = append(names, "Measure")
+	}
+	for _, n := range flagNames {
+		if f&n.flag != 0 {
+			names = append(names, n.name)
+			f &^= n.flag
+		}
+	}
+	if f != 0 {
+		names = append(names, fmt.Sprintf("%#x", uint(f)))
+	}
+	return strings.Join(names, "|")
+}
+
+// fftwMalloc allocates memory for n elements of the given size with fftw_malloc.
+func fftwMalloc(n, size int) unsafe.Pointer {
 	// Try to allocate memory.
-	buffer, err := C.fftw_malloc(C.size_t(16 * n))
+	buffer, err := C.fftw_malloc(C.size_t(size * n))
 	if err != nil {
 		// If malloc failed, invoke garbage collector and try again.
 		runtime.GC()
-		buffer, err = C.fftw_malloc(C.size_t(16 * n))
+		buffer, err = C.fftw_malloc(C.size_t(size * n))
 		if err != nil {
 			// If it still failed, then panic.
 			panic(fmt.Sprint("Could not fftw_malloc for ", n, " elements: ", err))
 		}
 	}
+	return buffer
+}
+
+func Alloc1d(n int) []complex128 {
+	buffer := fftwMalloc(n, 16)
 	// Create a slice header for the memory.
 	var slice []complex128
 	header := (*reflect.SliceHeader)(unsafe.Pointer(&slice))
@@ -65,6 +218,20 @@ func Alloc1d(n int) []complex128 {
 	return slice
 }
 
+// allocReal1d is the float64 counterpart of Alloc1d.
+func allocReal1d(n int) []float64 {
+	buffer := fftwMalloc(n, 8)
+	var slice []float64
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&slice))
+	header.Data = uintptr(buffer)
+	header.Len = n
+	header.Cap = n
+	for i := 0; i < n; i++ {
+		slice[i] = 0
+	}
+	return slice
+}
+
 func Alloc2d(n0, n1 int) [][]complex128 {
 	a := Alloc1d(n0 * n1)
 	r := make([][]complex128, n0)
@@ -99,6 +266,10 @@ func Free3d(x [][][]complex128) {
 	C.fftw_free(unsafe.Pointer(&x[0][0][0]))
 }
 
+func freeReal1d(x []float64) {
+	C.fftw_free(unsafe.Pointer(&x[0]))
+}
+
 func Dft1d(in, out []complex128, dir Direction, flag Flag) {
 	p := PlanDft1d(in, out, dir, flag)
 	p.Execute()
@@ -116,10 +287,15 @@ func Dft3d(in, out [][][]complex128, dir Direction, flag Flag) {
 
 func PlanDft1d(in, out []complex128, dir Direction, flag Flag) *Plan {
 	// TODO: check that len(in) == len(out)
+	if !fitsInt(len(in)) {
+		return planLarge1d(PlanGuruDft([]IODim{{len(in), 1, 1}}, nil, in, out, dir, flag))
+	}
 	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
 	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
-	p := C.fftw_plan_dft_1d(C.int(len(in)), fftw_in, fftw_out, C.int(dir), C.uint(flag))
-	return newPlan(p)
+	planner.Lock()
+	p := C.fftw_plan_dft_1d(C.int(len(in)), fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: []int{len(in)}, Dir: dir}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
 }
 
 func PlanDft2d(in, out [][]complex128, dir Direction, flag Flag) *Plan {
@@ -128,8 +304,10 @@ func PlanDft2d(in, out [][]complex128, dir Direction, flag Flag) *Plan {
 	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0][0]))
 	n0 := len(in)
 	n1 := len(in[0])
-	p := C.fftw_plan_dft_2d(C.int(n0), C.int(n1), fftw_in, fftw_out, C.int(dir), C.uint(flag))
-	return newPlan(p)
+	planner.Lock()
+	p := C.fftw_plan_dft_2d(cInt(n0), cInt(n1), fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: []int{n0, n1}, Dir: dir}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
 }
 
 func PlanDft3d(in, out [][][]complex128, dir Direction, flag Flag) *Plan {
@@ -139,31 +317,212 @@ func PlanDft3d(in, out [][][]complex128, dir Direction, flag Flag) *Plan {
 	n0 := len(in)
 	n1 := len(in[0])
 	n2 := len(in[0][0])
-	p := C.fftw_plan_dft_3d(C.int(n0), C.int(n1), C.int(n2), fftw_in, fftw_out, C.int(dir), C.uint(flag))
-	return newPlan(p)
+	planner.Lock()
+	p := C.fftw_plan_dft_3d(cInt(n0), cInt(n1), cInt(n2), fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: []int{n0, n1, n2}, Dir: dir}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func DftNd(dims []int, in, out []complex128, dir Direction, flag Flag) {
+	p := PlanDftNd(dims, in, out, dir, flag)
+	p.Execute()
+}
+
+// PlanDftNd plans a transform of any rank on the row-major arrays in and
+// out, whose dimensions are dims, for data with more dimensions than
+// PlanDft3d handles.
+func PlanDftNd(dims []int, in, out []complex128, dir Direction, flag Flag) *Plan {
+	if len(dims) == 0 {
+		panic("PlanDftNd needs at least one dimension")
+	}
+	size := 1
+	n := make([]C.int, len(dims))
+	for i, d := range dims {
+		if d < 1 {
+			panic(fmt.Sprint("PlanDftNd needs positive dimensions, got ", dims))
+		}
+		size *= d
+		n[i] = cInt(d)
+	}
+	if len(in) != size || len(out) != size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_dft(C.int(len(n)), &n[0], fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: append([]int(nil), dims...), Dir: dir}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
 }
 
-// TODO: Once we can create go arrays out of pre-existing data we can do these real-to-complex and complex-to-real
-//       transforms in-place.
 // The real-to-complex and complex-to-real transforms save roughly a factor of two in time and space, with
 // the following caveats:
 // 1. The real array is of size N, the complex array is of size N/2+1.
 // 2. The output array contains only the non-redundant output, the complete output is symmetric and the last half
 //    is the complex conjugate of the first half.
-// 3. Doing a complex-to-real transform destroys the input signal.
+// 3. Doing a complex-to-real transform destroys the input signal, unless PreserveInput is used.
+// PlanDftR2CInPlace1d and PlanDftC2RInPlace1d do these transforms in place.
 func PlanDftR2C1d(in []float64, out []complex128, flag Flag) *Plan {
 	// TODO: check that in and out have the appropriate dimensions
+	if !fitsInt(len(in)) {
+		return planLarge1d(PlanGuruDftR2C([]IODim{{len(in), 1, 1}}, nil, in, out, flag))
+	}
 	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
 	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
-	p := C.fftw_plan_dft_r2c_1d(C.int(len(in)), fftw_in, fftw_out, C.uint(flag))
-	return newPlan(p)
+	planner.Lock()
+	p := C.fftw_plan_dft_r2c_1d(C.int(len(in)), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2C, Dims: []int{len(in)}, Dir: Forward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
 }
 
-// Note: Executing this plan will destroy the data contained by in
+// Note: Executing this plan will destroy the data contained by in, unless flag includes PreserveInput.
 func PlanDftC2R1d(in []complex128, out []float64, flag Flag) *Plan {
 	// TODO: check that in and out have the appropriate dimensions
+	if !fitsInt(len(out)) {
+		return planLarge1d(PlanGuruDftC2R([]IODim{{len(out), 1, 1}}, nil, in, out, flag))
+	}
 	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
 	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
-	p := C.fftw_plan_dft_c2r_1d(C.int(len(out)), fftw_in, fftw_out, C.uint(flag))
-	return newPlan(p)
+	planner.Lock()
+	p := C.fftw_plan_dft_c2r_1d(C.int(len(out)), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	if p == nil && flag&PreserveInput != 0 {
+		// fftw couldn't find a plan that preserves its input.
+		return planDftC2R1dScratch(in, out, flag)
+	}
+	return newPlan(p, Geometry{Kind: C2R, Dims: []int{len(out)}, Dir: Backward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// planDftC2R1dScratch emulates PreserveInput by planning on a scratch copy
+// of in, which is refreshed before each execution.
+func planDftC2R1dScratch(in []complex128, out []float64, flag Flag) *Plan {
+	scratch := make([]complex128, len(in))
+	p := PlanDftC2R1d(scratch, out, flag&^PreserveInput)
+	p.prepare = func() {
+		copy(scratch, in)
+	}
+	return p
+}
+
+func DftR2C2d(in [][]float64, out [][]complex128, flag Flag) {
+	p := PlanDftR2C2d(in, out, flag)
+	p.Execute()
+}
+
+func DftC2R2d(in [][]complex128, out [][]float64, flag Flag) {
+	p := PlanDftC2R2d(in, out, flag)
+	p.Execute()
+}
+
+// PlanDftR2C2d plans the transform of the real n0 x n1 array in into the
+// n0 x (n1/2+1) array out, which holds the non-redundant half of the
+// spectrum.  Both arrays must be contiguous, as those made by Alloc2d are.
+func PlanDftR2C2d(in [][]float64, out [][]complex128, flag Flag) *Plan {
+	n0 := len(in)
+	n1 := len(in[0])
+	if len(out) != n0 || len(out[0]) != n1/2+1 {
+		panic(fmt.Sprint("A real ", n0, "x", n1, " array needs a ", n0, "x", n1/2+1, " spectrum, got ", len(out), "x", len(out[0])))
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0][0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0][0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_r2c_2d(cInt(n0), cInt(n1), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2C, Dims: []int{n0, n1}, Dir: Forward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftC2R2d plans the inverse of PlanDftR2C2d, from the n0 x (n1/2+1)
+// array in to the real n0 x n1 array out.  fftw can't preserve the input of
+// multi-dimensional complex-to-real transforms, so PreserveInput is
+// emulated with a scratch copy of in.
+func PlanDftC2R2d(in [][]complex128, out [][]float64, flag Flag) *Plan {
+	n0 := len(out)
+	n1 := len(out[0])
+	if len(in) != n0 || len(in[0]) != n1/2+1 {
+		panic(fmt.Sprint("A real ", n0, "x", n1, " array needs a ", n0, "x", n1/2+1, " spectrum, got ", len(in), "x", len(in[0])))
+	}
+	if flag&PreserveInput != 0 {
+		scratch := make([][]complex128, n0)
+		data := make([]complex128, n0*(n1/2+1))
+		for i := range scratch {
+			scratch[i] = data[i*(n1/2+1) : (i+1)*(n1/2+1)]
+		}
+		p := PlanDftC2R2d(scratch, out, flag&^PreserveInput)
+		p.prepare = func() {
+			for i := range in {
+				copy(scratch[i], in[i])
+			}
+		}
+		return p
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0][0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0][0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_c2r_2d(cInt(n0), cInt(n1), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2R, Dims: []int{n0, n1}, Dir: Backward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func DftR2C3d(in [][][]float64, out [][][]complex128, flag Flag) {
+	p := PlanDftR2C3d(in, out, flag)
+	p.Execute()
+}
+
+func DftC2R3d(in [][][]complex128, out [][][]float64, flag Flag) {
+	p := PlanDftC2R3d(in, out, flag)
+	p.Execute()
+}
+
+// PlanDftR2C3d plans the transform of the real n0 x n1 x n2 array in into
+// the n0 x n1 x (n2/2+1) array out.  Both arrays must be contiguous.
+func PlanDftR2C3d(in [][][]float64, out [][][]complex128, flag Flag) *Plan {
+	n0 := len(in)
+	n1 := len(in[0])
+	n2 := len(in[0][0])
+	if len(out) != n0 || len(out[0]) != n1 || len(out[0][0]) != n2/2+1 {
+		panic(fmt.Sprint("A real ", n0, "x", n1, "x", n2, " array needs a ", n0, "x", n1, "x", n2/2+1, " spectrum, got ", len(out), "x", len(out[0]), "x", len(out[0][0])))
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0][0][0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0][0][0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_r2c_3d(cInt(n0), cInt(n1), cInt(n2), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2C, Dims: []int{n0, n1, n2}, Dir: Forward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftC2R3d plans the inverse of PlanDftR2C3d, emulating PreserveInput
+// as PlanDftC2R2d does.
+func PlanDftC2R3d(in [][][]complex128, out [][][]float64, flag Flag) *Plan {
+	n0 := len(out)
+	n1 := len(out[0])
+	n2 := len(out[0][0])
+	if len(in) != n0 || len(in[0]) != n1 || len(in[0][0]) != n2/2+1 {
+		panic(fmt.Sprint("A real ", n0, "x", n1, "x", n2, " array needs a ", n0, "x", n1, "x", n2/2+1, " spectrum, got ", len(in), "x", len(in[0]), "x", len(in[0][0])))
+	}
+	if flag&PreserveInput != 0 {
+		scratch := make([][][]complex128, n0)
+		data := make([]complex128, n0*n1*(n2/2+1))
+		for i := range scratch {
+			scratch[i] = make([][]complex128, n1)
+			for j := range scratch[i] {
+				k := (i*n1 + j) * (n2/2 + 1)
+				scratch[i][j] = data[k : k+n2/2+1]
+			}
+		}
+		p := PlanDftC2R3d(scratch, out, flag&^PreserveInput)
+		p.prepare = func() {
+			for i := range in {
+				for j := range in[i] {
+					copy(scratch[i][j], in[i][j])
+				}
+			}
+		}
+		return p
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0][0][0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0][0][0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_c2r_3d(cInt(n0), cInt(n1), cInt(n2), fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2R, Dims: []int{n0, n1, n2}, Dir: Backward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
 }
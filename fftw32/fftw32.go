@@ -0,0 +1,255 @@
+// Package fftw32 binds fftw's single precision library, fftwf, with the
+// same API as package fftw but on complex64 and float32 arrays, for
+// pipelines that are single precision end to end and would otherwise
+// convert to float64 and back just for their transforms.
+//
+// Directions, flags and geometries are package fftw's, and plans honor its
+// strict mode.
+package fftw32
+
+// #cgo pkg-config: fftw3f
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/runningwild/go-fftw"
+)
+
+type Plan struct {
+	fftw_p C.fftwf_plan
+	geom   fftw.Geometry
+	// The arrays p was planned for, kept alive for as long as p can
+	// execute on them.
+	in, out unsafe.Pointer
+}
+
+// planner guards fftwf's planner, which isn't safe for concurrent use, as
+// every plan is made and destroyed.
+var planner sync.Mutex
+
+func destroyPlan(p *Plan) {
+	planner.Lock()
+	C.fftwf_destroy_plan(p.fftw_p)
+	planner.Unlock()
+}
+
+func newPlan(fftw_p C.fftwf_plan, geom fftw.Geometry, in, out unsafe.Pointer) *Plan {
+	if fftw_p == nil {
+		panic(fmt.Sprint("fftwf could not plan a transform of geometry ", geom))
+	}
+	np := &Plan{fftw_p: fftw_p, geom: geom, in: in, out: out}
+	np.geom.InPlace = in == out
+	runtime.SetFinalizer(np, destroyPlan)
+	return np
+}
+
+func (p *Plan) Execute() {
+	C.fftwf_execute(p.fftw_p)
+	runtime.KeepAlive(p)
+}
+
+// Geometry returns the shape of the transform p was planned for.
+func (p *Plan) Geometry() fftw.Geometry {
+	g := p.geom
+	g.Dims = append([]int(nil), g.Dims...)
+	return g
+}
+
+func flags(flag fftw.Flag) C.uint {
+	return C.uint(fftw.PlannerFlags(flag))
+}
+
+// fftwMalloc allocates n elements of the given size with fftwf_malloc.
+func fftwMalloc(n, size int) unsafe.Pointer {
+	buffer, err := C.fftwf_malloc(C.size_t(size * n))
+	if err != nil {
+		runtime.GC()
+		buffer, err = C.fftwf_malloc(C.size_t(size * n))
+		if err != nil {
+			panic(fmt.Sprint("Could not fftwf_malloc for ", n, " elements: ", err))
+		}
+	}
+	return buffer
+}
+
+// Alloc1d returns a zeroed array of n elements allocated by fftwf, aligned
+// for its SIMD code.  It must be freed with Free1d.
+func Alloc1d(n int) []complex64 {
+	slice := unsafe.Slice((*complex64)(fftwMalloc(n, 8)), n)
+	for i := range slice {
+		slice[i] = 0
+	}
+	return slice
+}
+
+// AllocReal1d is the float32 counterpart of Alloc1d, freed with
+// FreeReal1d.
+func AllocReal1d(n int) []float32 {
+	slice := unsafe.Slice((*float32)(fftwMalloc(n, 4)), n)
+	for i := range slice {
+		slice[i] = 0
+	}
+	return slice
+}
+
+func Alloc2d(n0, n1 int) [][]complex64 {
+	a := Alloc1d(n0 * n1)
+	r := make([][]complex64, n0)
+	for i := range r {
+		r[i] = a[i*n1 : (i+1)*n1]
+	}
+	return r
+}
+
+func Alloc3d(n0, n1, n2 int) [][][]complex64 {
+	a := Alloc1d(n0 * n1 * n2)
+	r := make([][][]complex64, n0)
+	for i := range r {
+		b := make([][]complex64, n1)
+		for j := range b {
+			b[j] = a[i*(n1*n2)+j*n2 : i*(n1*n2)+(j+1)*n2]
+		}
+		r[i] = b
+	}
+	return r
+}
+
+func Free1d(x []complex64) {
+	C.fftwf_free(unsafe.Pointer(&x[0]))
+}
+
+func FreeReal1d(x []float32) {
+	C.fftwf_free(unsafe.Pointer(&x[0]))
+}
+
+func Free2d(x [][]complex64) {
+	C.fftwf_free(unsafe.Pointer(&x[0][0]))
+}
+
+func Free3d(x [][][]complex64) {
+	C.fftwf_free(unsafe.Pointer(&x[0][0][0]))
+}
+
+func cInt(n int) C.int {
+	if int(C.int(n)) != n {
+		panic(fmt.Sprint("Dimension ", n, " is too large for fftwf's basic planners"))
+	}
+	return C.int(n)
+}
+
+func cDims(name string, dims []int) []C.int {
+	if len(dims) == 0 {
+		panic(fmt.Sprint(name, " needs at least one dimension"))
+	}
+	n := make([]C.int, len(dims))
+	for i, d := range dims {
+		if d < 1 {
+			panic(fmt.Sprint(name, " needs positive dimensions, got ", dims))
+		}
+		n[i] = cInt(d)
+	}
+	return n
+}
+
+func PlanDft1d(in, out []complex64, dir fftw.Direction, flag fftw.Flag) *Plan {
+	if len(in) != len(out) {
+		panic(fmt.Sprint("PlanDft1d needs arrays of the same length, got ", len(in), " and ", len(out)))
+	}
+	return PlanDftNd([]int{len(in)}, in, out, dir, flag)
+}
+
+// PlanDft2d plans a transform of the n0 x n1 arrays in and out, which must
+// be contiguous, as those made by Alloc2d are.
+func PlanDft2d(in, out [][]complex64, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n0, n1 := len(in), len(in[0])
+	if len(out) != n0 || len(out[0]) != n1 {
+		panic(fmt.Sprint("PlanDft2d needs arrays of the same dimensions, got ", n0, "x", n1, " and ", len(out), "x", len(out[0])))
+	}
+	return PlanDftNd([]int{n0, n1}, in[0][:cap(in[0])], out[0][:cap(out[0])], dir, flag)
+}
+
+// PlanDft3d plans a transform of the contiguous n0 x n1 x n2 arrays in and
+// out.
+func PlanDft3d(in, out [][][]complex64, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n0, n1, n2 := len(in), len(in[0]), len(in[0][0])
+	if len(out) != n0 || len(out[0]) != n1 || len(out[0][0]) != n2 {
+		panic(fmt.Sprint("PlanDft3d needs arrays of the same dimensions, got ", n0, "x", n1, "x", n2, " and ", len(out), "x", len(out[0]), "x", len(out[0][0])))
+	}
+	return PlanDftNd([]int{n0, n1, n2}, in[0][0][:cap(in[0][0])], out[0][0][:cap(out[0][0])], dir, flag)
+}
+
+// PlanDftNd plans a transform of any rank on the row-major arrays in and
+// out, whose dimensions are dims.
+func PlanDftNd(dims []int, in, out []complex64, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n := cDims("PlanDftNd", dims)
+	if size := volume(dims); len(in) < size || len(out) < size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.fftwf_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftwf_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftwf_plan_dft(C.int(len(n)), &n[0], fftw_in, fftw_out, C.int(dir), flags(flag))
+	planner.Unlock()
+	return newPlan(p, fftw.Geometry{Kind: fftw.C2C, Dims: append([]int(nil), dims...), Dir: dir}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func volume(dims []int) int {
+	size := 1
+	for _, d := range dims {
+		size *= d
+	}
+	return size
+}
+
+// halfVolume is the number of complex elements in the spectrum of a real
+// array of dimensions dims.
+func halfVolume(dims []int) int {
+	last := dims[len(dims)-1]
+	return volume(dims) / last * (last/2 + 1)
+}
+
+// PlanDftR2CNd plans the transform of the real row-major array in, of
+// dimensions dims, into the non-redundant half of its spectrum, whose last
+// dimension is n/2+1 long.
+func PlanDftR2CNd(dims []int, in []float32, out []complex64, flag fftw.Flag) *Plan {
+	n := cDims("PlanDftR2CNd", dims)
+	if len(in) < volume(dims) || len(out) < halfVolume(dims) {
+		panic(fmt.Sprint("A real array of dimensions ", dims, " needs ", volume(dims), " reals and a spectrum of ", halfVolume(dims), ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.float)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftwf_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftwf_plan_dft_r2c(C.int(len(n)), &n[0], fftw_in, fftw_out, flags(flag))
+	planner.Unlock()
+	return newPlan(p, fftw.Geometry{Kind: fftw.R2C, Dims: append([]int(nil), dims...), Dir: fftw.Forward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftC2RNd plans the inverse of PlanDftR2CNd, destroying its input
+// unless it is one dimensional.  Unlike package fftw's plans it doesn't
+// emulate PreserveInput: fftwf can't keep the input of multi-dimensional
+// c2r transforms, so planning one with PreserveInput panics.
+func PlanDftC2RNd(dims []int, in []complex64, out []float32, flag fftw.Flag) *Plan {
+	n := cDims("PlanDftC2RNd", dims)
+	if len(out) < volume(dims) || len(in) < halfVolume(dims) {
+		panic(fmt.Sprint("A real array of dimensions ", dims, " needs ", volume(dims), " reals and a spectrum of ", halfVolume(dims), ", got ", len(out), " and ", len(in)))
+	}
+	fftw_in := (*C.fftwf_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.float)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftwf_plan_dft_c2r(C.int(len(n)), &n[0], fftw_in, fftw_out, flags(flag))
+	planner.Unlock()
+	return newPlan(p, fftw.Geometry{Kind: fftw.C2R, Dims: append([]int(nil), dims...), Dir: fftw.Backward}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func PlanDftR2C1d(in []float32, out []complex64, flag fftw.Flag) *Plan {
+	return PlanDftR2CNd([]int{len(in)}, in, out, flag)
+}
+
+func PlanDftC2R1d(in []complex64, out []float32, flag fftw.Flag) *Plan {
+	return PlanDftC2RNd([]int{len(out)}, in, out, flag)
+}
@@ -0,0 +1,81 @@
+package fftw32
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	"github.com/runningwild/go-fftw"
+)
+
+func FFTW32Spec(c gospec.Context) {
+	c.Specify("Complex transforms match double precision ones.", func() {
+		in, out := Alloc1d(12), Alloc1d(12)
+		defer Free1d(in)
+		defer Free1d(out)
+		in64, out64 := make([]complex128, 12), make([]complex128, 12)
+		for i := range in {
+			in[i] = complex(float32(math.Sin(float64(i))), float32(i%4))
+			in64[i] = complex128(in[i])
+		}
+		p := PlanDft1d(in, out, fftw.Forward, fftw.Estimate)
+		p.Execute()
+		fftw.PlanDft1d(in64, out64, fftw.Forward, fftw.Estimate).Execute()
+		for k := range out {
+			c.Expect(cmplx.Abs(complex128(out[k])-out64[k]), gospec.IsWithin(1e-4), 0.0)
+		}
+		g := p.Geometry()
+		c.Expect(g.Kind, gospec.Equals, fftw.C2C)
+		c.Expect(g.Dims, gospec.ContainsExactly, []int{12})
+	})
+
+	c.Specify("2d and 3d transforms round trip in place.", func() {
+		a := Alloc2d(4, 6)
+		defer Free2d(a)
+		a[1][2] = 1
+		PlanDft2d(a, a, fftw.Forward, fftw.Estimate).Execute()
+		c.Expect(real(a[2][0]), gospec.IsWithin(1e-6), -1.0)
+		PlanDft2d(a, a, fftw.Backward, fftw.Estimate).Execute()
+		c.Expect(real(a[1][2]), gospec.IsWithin(1e-5), 24.0)
+
+		b := Alloc3d(2, 3, 4)
+		defer Free3d(b)
+		b[0][0][0] = 1
+		PlanDft3d(b, b, fftw.Forward, fftw.Estimate).Execute()
+		c.Expect(real(b[1][2][3]), gospec.IsWithin(1e-6), 1.0)
+	})
+
+	c.Specify("Real transforms round trip.", func() {
+		x := AllocReal1d(10)
+		defer FreeReal1d(x)
+		for i := range x {
+			x[i] = float32(i*i) / 10
+		}
+		want := append([]float32(nil), x...)
+		s := make([]complex64, 6)
+		PlanDftR2C1d(x, s, fftw.Estimate).Execute()
+		c.Expect(real(s[0]), gospec.IsWithin(1e-4), 28.5)
+		PlanDftC2R1d(s, x, fftw.Estimate).Execute()
+		for i := range x {
+			c.Expect(float64(x[i]/10), gospec.IsWithin(1e-5), float64(want[i]))
+		}
+		c.Expect(PlanDftR2CNd([]int{2, 10}, make([]float32, 20), make([]complex64, 12), fftw.Estimate).Geometry().Kind, gospec.Equals, fftw.R2C)
+	})
+
+	c.Specify("Multi-dimensional complex-to-real plans can't preserve their input.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanDftC2RNd([]int{2, 10}, make([]complex64, 12), make([]float32, 20), fftw.Estimate|fftw.PreserveInput)
+	})
+
+	c.Specify("Mismatched arrays are rejected.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanDftR2C1d(make([]float32, 10), make([]complex64, 5), fftw.Estimate)
+	})
+}
+
+func TestAllSpecs(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(FFTW32Spec)
+	gospec.MainGoTest(r, t)
+}
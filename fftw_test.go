@@ -4,6 +4,7 @@ import (
 	"github.com/orfjackal/gospec/src/gospec"
 	//. "github.com/orfjackal/gospec/src/gospec"
 	"math"
+	"math/cmplx"
 )
 
 func Alloc1dSpec(c gospec.Context) {
@@ -265,3 +266,171 @@ func FFTC2RSpec(c gospec.Context) {
 		}
 	})
 }
+
+func FFTC2RPreserveSpec(c gospec.Context) {
+	F_signal := make([]complex128, 9)
+	for i := range F_signal {
+		F_signal[i] = complex(float64(i), 0)
+	}
+	original := make([]complex128, len(F_signal))
+	copy(original, F_signal)
+	plain := make([]float64, 16)
+	preserved := make([]float64, 16)
+	emulated := make([]float64, 16)
+
+	PlanDftC2R1d(F_signal, preserved, Estimate|PreserveInput).Execute()
+	c.Specify("PreserveInput keeps a C2R transform from destroying its input.", func() {
+		for i := range F_signal {
+			c.Expect(F_signal[i], gospec.Equals, original[i])
+		}
+	})
+
+	scratch := planDftC2R1dScratch(F_signal, emulated, Estimate|PreserveInput)
+	scratch.Execute()
+	c.Specify("Emulating PreserveInput with a scratch copy keeps the input too.", func() {
+		for i := range F_signal {
+			c.Expect(F_signal[i], gospec.Equals, original[i])
+		}
+	})
+
+	c.Specify("Preserving the input doesn't change the result.", func() {
+		PlanDftC2R1d(F_signal, plain, Estimate).Execute()
+		for i := range plain {
+			c.Expect(preserved[i], gospec.IsWithin(1e-9), plain[i])
+			c.Expect(emulated[i], gospec.IsWithin(1e-9), plain[i])
+		}
+	})
+}
+
+func FFTR2C2dSpec(c gospec.Context) {
+	n0, n1 := 6, 5
+	signal := alloc2dReal(n0, n1)
+	full := Alloc2d(n0, n1)
+	for i := range signal {
+		for j := range signal[i] {
+			signal[i][j] = float64((i*7+j*3)%11) - 5
+			full[i][j] = complex(signal[i][j], 0)
+		}
+	}
+	F_signal := Alloc2d(n0, n1/2+1)
+	PlanDftR2C2d(signal, F_signal, Estimate).Execute()
+	Dft2d(full, full, Forward, Estimate)
+
+	c.Specify("Forward 2d Real to Complex FFT matches the complex FFT.", func() {
+		for i := range F_signal {
+			for j := range F_signal[i] {
+				c.Expect(real(F_signal[i][j]), gospec.IsWithin(1e-9), real(full[i][j]))
+				c.Expect(imag(F_signal[i][j]), gospec.IsWithin(1e-9), imag(full[i][j]))
+			}
+		}
+	})
+
+	c.Specify("Backward 2d Complex to Real FFT inverts it.", func() {
+		original := Alloc2d(n0, n1/2+1)
+		for i := range F_signal {
+			copy(original[i], F_signal[i])
+		}
+		out := alloc2dReal(n0, n1)
+		DftC2R2d(F_signal, out, Estimate|PreserveInput)
+		for i := range out {
+			for j := range out[i] {
+				c.Expect(out[i][j]/float64(n0*n1), gospec.IsWithin(1e-9), signal[i][j])
+			}
+		}
+		for i := range F_signal {
+			for j := range F_signal[i] {
+				c.Expect(F_signal[i][j], gospec.Equals, original[i][j])
+			}
+		}
+	})
+}
+
+func FFTR2C3dSpec(c gospec.Context) {
+	n0, n1, n2 := 3, 4, 6
+	data := make([]float64, n0*n1*n2)
+	signal := make([][][]float64, n0)
+	full := Alloc3d(n0, n1, n2)
+	for i := range signal {
+		signal[i] = make([][]float64, n1)
+		for j := range signal[i] {
+			signal[i][j] = data[(i*n1+j)*n2 : (i*n1+j+1)*n2]
+			for k := range signal[i][j] {
+				signal[i][j][k] = float64((i*5+j*3+k*k)%13) - 6
+				full[i][j][k] = complex(signal[i][j][k], 0)
+			}
+		}
+	}
+	F_signal := Alloc3d(n0, n1, n2/2+1)
+	DftR2C3d(signal, F_signal, Estimate)
+	Dft3d(full, full, Forward, Estimate)
+
+	c.Specify("Forward 3d Real to Complex FFT matches the complex FFT.", func() {
+		for i := range F_signal {
+			for j := range F_signal[i] {
+				for k := range F_signal[i][j] {
+					c.Expect(real(F_signal[i][j][k]), gospec.IsWithin(1e-9), real(full[i][j][k]))
+					c.Expect(imag(F_signal[i][j][k]), gospec.IsWithin(1e-9), imag(full[i][j][k]))
+				}
+			}
+		}
+	})
+
+	c.Specify("Backward 3d Complex to Real FFT inverts it.", func() {
+		outData := make([]float64, n0*n1*n2)
+		out := make([][][]float64, n0)
+		for i := range out {
+			out[i] = make([][]float64, n1)
+			for j := range out[i] {
+				out[i][j] = outData[(i*n1+j)*n2 : (i*n1+j+1)*n2]
+			}
+		}
+		first := F_signal[1][2][1]
+		PlanDftC2R3d(F_signal, out, Estimate|PreserveInput).Execute()
+		c.Expect(F_signal[1][2][1], gospec.Equals, first)
+		for i := range outData {
+			c.Expect(outData[i]/float64(n0*n1*n2), gospec.IsWithin(1e-9), data[i])
+		}
+	})
+}
+
+func FFTNdSpec(c gospec.Context) {
+	dims := []int{2, 3, 2, 3}
+	in := make([]complex128, 36)
+	for i := range in {
+		in[i] = complex(float64(i%5), float64(i%3)-1)
+	}
+	out := make([]complex128, 36)
+	p := PlanDftNd(dims, in, out, Forward, Estimate)
+	p.Execute()
+
+	c.Specify("Forward 4d FFT matches the definition.", func() {
+		index := func(i []int) int {
+			return ((i[0]*dims[1]+i[1])*dims[2]+i[2])*dims[3] + i[3]
+		}
+		for a := 0; a < 36; a++ {
+			k := []int{a / 18, a / 6 % 3, a / 3 % 2, a % 3}
+			var sum complex128
+			for b := 0; b < 36; b++ {
+				x := []int{b / 18, b / 6 % 3, b / 3 % 2, b % 3}
+				phase := 0.0
+				for d := range dims {
+					phase += float64(k[d]*x[d]) / float64(dims[d])
+				}
+				sum += in[index(x)] * cmplx.Rect(1, -2*math.Pi*phase)
+			}
+			c.Expect(real(out[index(k)]), gospec.IsWithin(1e-9), real(sum))
+			c.Expect(imag(out[index(k)]), gospec.IsWithin(1e-9), imag(sum))
+		}
+		g := p.Geometry()
+		c.Expect(g.Dims, gospec.ContainsInOrder, dims)
+	})
+
+	c.Specify("Backward 4d FFT inverts it.", func() {
+		back := make([]complex128, 36)
+		DftNd(dims, out, back, Backward, Estimate)
+		for i := range back {
+			c.Expect(real(back[i])/36, gospec.IsWithin(1e-9), real(in[i]))
+			c.Expect(imag(back[i])/36, gospec.IsWithin(1e-9), imag(in[i]))
+		}
+	})
+}
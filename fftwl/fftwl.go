@@ -0,0 +1,230 @@
+// Package fftwl binds fftw's long double library, fftwl, for checking the
+// numerical error of lower precision pipelines against transforms done in
+// extended precision.
+//
+// Go has no long double type, so plans take float64 arrays like package
+// fftw's and keep long double copies of them: Execute widens the input,
+// transforms it in extended precision and rounds the result into the
+// output.  Dimensions, flags and plan lifetimes are as in package fftw.
+package fftwl
+
+// #cgo pkg-config: fftw3l
+// #include <stddef.h>
+// #include <fftw3.h>
+//
+// // cgo can't pass long doubles, so the arrays are handled as void * and
+// // only touched in C.
+//
+// static void *alloc_ld(ptrdiff_t n) {
+//   return fftwl_malloc(n * sizeof(long double));
+// }
+//
+// static void widen(void *dst, const double *src, ptrdiff_t n) {
+//   long double *d = dst;
+//   for (ptrdiff_t i = 0; i < n; i++) d[i] = src[i];
+// }
+//
+// static void narrow(double *dst, const void *src, ptrdiff_t n) {
+//   const long double *s = src;
+//   for (ptrdiff_t i = 0; i < n; i++) dst[i] = (double)s[i];
+// }
+//
+// static fftwl_plan plan_dft(int rank, const int *n, void *in, void *out, int sign, unsigned flags) {
+//   return fftwl_plan_dft(rank, n, in, out, sign, flags);
+// }
+//
+// static fftwl_plan plan_dft_r2c(int rank, const int *n, void *in, void *out, unsigned flags) {
+//   return fftwl_plan_dft_r2c(rank, n, in, out, flags);
+// }
+//
+// static fftwl_plan plan_dft_c2r(int rank, const int *n, void *in, void *out, unsigned flags) {
+//   return fftwl_plan_dft_c2r(rank, n, in, out, flags);
+// }
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/runningwild/go-fftw"
+)
+
+type Plan struct {
+	fftw_p C.fftwl_plan
+	geom   fftw.Geometry
+	// The Go arrays, as float64s, and their long double copies.
+	in, out     []float64
+	ldIn, ldOut unsafe.Pointer
+}
+
+// planner guards fftwl's planner, which isn't safe for concurrent use, as
+// every plan is made and destroyed.
+var planner sync.Mutex
+
+func destroyPlan(p *Plan) {
+	planner.Lock()
+	C.fftwl_destroy_plan(p.fftw_p)
+	planner.Unlock()
+	C.fftwl_free(p.ldIn)
+	C.fftwl_free(p.ldOut)
+}
+
+// float64s returns the float64s making up the complex128s of x.
+func float64s(x []complex128) []float64 {
+	return unsafe.Slice((*float64)(unsafe.Pointer(&x[0])), 2*len(x))
+}
+
+func cDims(name string, dims []int) []C.int {
+	if len(dims) == 0 {
+		panic(fmt.Sprint(name, " needs at least one dimension"))
+	}
+	n := make([]C.int, len(dims))
+	for i, d := range dims {
+		if d < 1 || int(C.int(d)) != d {
+			panic(fmt.Sprint(name, " needs positive dimensions that fit in a C int, got ", dims))
+		}
+		n[i] = C.int(d)
+	}
+	return n
+}
+
+func volume(dims []int) int {
+	size := 1
+	for _, d := range dims {
+		size *= d
+	}
+	return size
+}
+
+// halfVolume is the number of complex elements in the spectrum of a real
+// array of dimensions dims.
+func halfVolume(dims []int) int {
+	last := dims[len(dims)-1]
+	return volume(dims) / last * (last/2 + 1)
+}
+
+// newPlan makes the long double copies of in and out, which hold inLen
+// and outLen float64s, and plans on them with plan.  The copies are always
+// separate, so fftwl's plans are out of place even when in and out are the
+// same array.
+func newPlan(geom fftw.Geometry, in, out []float64, inLen, outLen int, plan func(ldIn, ldOut unsafe.Pointer) C.fftwl_plan) *Plan {
+	p := &Plan{geom: geom, in: in[:inLen], out: out[:outLen]}
+	p.geom.InPlace = &in[0] == &out[0]
+	if p.geom.InPlace && geom.Kind != fftw.C2C {
+		panic("fftwl can't plan in place real transforms")
+	}
+	p.ldIn = C.alloc_ld(C.ptrdiff_t(inLen))
+	p.ldOut = C.alloc_ld(C.ptrdiff_t(outLen))
+	if p.ldIn == nil || p.ldOut == nil {
+		panic(fmt.Sprint("Could not fftwl_malloc for ", inLen, " and ", outLen, " elements"))
+	}
+	planner.Lock()
+	p.fftw_p = plan(p.ldIn, p.ldOut)
+	planner.Unlock()
+	if p.fftw_p == nil {
+		panic(fmt.Sprint("fftwl could not plan a transform of geometry ", geom))
+	}
+	runtime.SetFinalizer(p, destroyPlan)
+	return p
+}
+
+// Execute transforms the plan's input array, in extended precision, into
+// its output array.  The input is never destroyed, since fftwl only works
+// on its long double copy.
+func (p *Plan) Execute() {
+	C.widen(p.ldIn, (*C.double)(unsafe.Pointer(&p.in[0])), C.ptrdiff_t(len(p.in)))
+	C.fftwl_execute(p.fftw_p)
+	C.narrow((*C.double)(unsafe.Pointer(&p.out[0])), p.ldOut, C.ptrdiff_t(len(p.out)))
+	runtime.KeepAlive(p)
+}
+
+// Geometry returns the shape of the transform p was planned for.
+func (p *Plan) Geometry() fftw.Geometry {
+	g := p.geom
+	g.Dims = append([]int(nil), g.Dims...)
+	return g
+}
+
+func flags(flag fftw.Flag) C.uint {
+	return C.uint(fftw.PlannerFlags(flag))
+}
+
+func PlanDft1d(in, out []complex128, dir fftw.Direction, flag fftw.Flag) *Plan {
+	if len(in) != len(out) {
+		panic(fmt.Sprint("PlanDft1d needs arrays of the same length, got ", len(in), " and ", len(out)))
+	}
+	return PlanDftNd([]int{len(in)}, in, out, dir, flag)
+}
+
+// PlanDft2d plans a transform of the n0 x n1 arrays in and out, which must
+// be contiguous, as those made by fftw.Alloc2d are.
+func PlanDft2d(in, out [][]complex128, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n0, n1 := len(in), len(in[0])
+	if len(out) != n0 || len(out[0]) != n1 {
+		panic(fmt.Sprint("PlanDft2d needs arrays of the same dimensions, got ", n0, "x", n1, " and ", len(out), "x", len(out[0])))
+	}
+	return PlanDftNd([]int{n0, n1}, in[0][:cap(in[0])], out[0][:cap(out[0])], dir, flag)
+}
+
+// PlanDft3d plans a transform of the contiguous n0 x n1 x n2 arrays in and
+// out.
+func PlanDft3d(in, out [][][]complex128, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n0, n1, n2 := len(in), len(in[0]), len(in[0][0])
+	if len(out) != n0 || len(out[0]) != n1 || len(out[0][0]) != n2 {
+		panic(fmt.Sprint("PlanDft3d needs arrays of the same dimensions, got ", n0, "x", n1, "x", n2, " and ", len(out), "x", len(out[0]), "x", len(out[0][0])))
+	}
+	return PlanDftNd([]int{n0, n1, n2}, in[0][0][:cap(in[0][0])], out[0][0][:cap(out[0][0])], dir, flag)
+}
+
+// PlanDftNd plans a transform of any rank on the row-major arrays in and
+// out, whose dimensions are dims.
+func PlanDftNd(dims []int, in, out []complex128, dir fftw.Direction, flag fftw.Flag) *Plan {
+	n := cDims("PlanDftNd", dims)
+	size := volume(dims)
+	if len(in) < size || len(out) < size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	geom := fftw.Geometry{Kind: fftw.C2C, Dims: append([]int(nil), dims...), Dir: dir}
+	return newPlan(geom, float64s(in), float64s(out), 2*size, 2*size, func(ldIn, ldOut unsafe.Pointer) C.fftwl_plan {
+		return C.plan_dft(C.int(len(n)), &n[0], ldIn, ldOut, C.int(dir), flags(flag))
+	})
+}
+
+// PlanDftR2CNd plans the transform of the real row-major array in, of
+// dimensions dims, into the non-redundant half of its spectrum, whose last
+// dimension is n/2+1 long.
+func PlanDftR2CNd(dims []int, in []float64, out []complex128, flag fftw.Flag) *Plan {
+	n := cDims("PlanDftR2CNd", dims)
+	if len(in) < volume(dims) || len(out) < halfVolume(dims) {
+		panic(fmt.Sprint("A real array of dimensions ", dims, " needs ", volume(dims), " reals and a spectrum of ", halfVolume(dims), ", got ", len(in), " and ", len(out)))
+	}
+	geom := fftw.Geometry{Kind: fftw.R2C, Dims: append([]int(nil), dims...), Dir: fftw.Forward}
+	return newPlan(geom, in, float64s(out), volume(dims), 2*halfVolume(dims), func(ldIn, ldOut unsafe.Pointer) C.fftwl_plan {
+		return C.plan_dft_r2c(C.int(len(n)), &n[0], ldIn, ldOut, flags(flag))
+	})
+}
+
+// PlanDftC2RNd plans the inverse of PlanDftR2CNd.  Since plans work on a
+// copy, in is never destroyed, and PreserveInput, which fftwl can't honour
+// for multi-dimensional c2r transforms, is ignored.
+func PlanDftC2RNd(dims []int, in []complex128, out []float64, flag fftw.Flag) *Plan {
+	flag &^= fftw.PreserveInput
+	n := cDims("PlanDftC2RNd", dims)
+	if len(out) < volume(dims) || len(in) < halfVolume(dims) {
+		panic(fmt.Sprint("A real array of dimensions ", dims, " needs ", volume(dims), " reals and a spectrum of ", halfVolume(dims), ", got ", len(out), " and ", len(in)))
+	}
+	geom := fftw.Geometry{Kind: fftw.C2R, Dims: append([]int(nil), dims...), Dir: fftw.Backward}
+	return newPlan(geom, float64s(in), out, 2*halfVolume(dims), volume(dims), func(ldIn, ldOut unsafe.Pointer) C.fftwl_plan {
+		return C.plan_dft_c2r(C.int(len(n)), &n[0], ldIn, ldOut, flags(flag))
+	})
+}
+
+func PlanDftR2C1d(in []float64, out []complex128, flag fftw.Flag) *Plan {
+	return PlanDftR2CNd([]int{len(in)}, in, out, flag)
+}
+
+func PlanDftC2R1d(in []complex128, out []float64, flag fftw.Flag) *Plan {
+	return PlanDftC2RNd([]int{len(out)}, in, out, flag)
+}
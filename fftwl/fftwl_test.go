@@ -0,0 +1,78 @@
+package fftwl
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	"github.com/runningwild/go-fftw"
+)
+
+func FFTWLSpec(c gospec.Context) {
+	c.Specify("Complex transforms match double precision ones.", func() {
+		in, out := make([]complex128, 12), make([]complex128, 12)
+		for i := range in {
+			in[i] = complex(math.Sin(float64(i)), float64(i%4))
+		}
+		want := make([]complex128, 12)
+		fftw.PlanDft1d(append([]complex128(nil), in...), want, fftw.Forward, fftw.Estimate).Execute()
+		p := PlanDft1d(in, out, fftw.Forward, fftw.Estimate)
+		p.Execute()
+		for k := range out {
+			c.Expect(cmplx.Abs(out[k]-want[k]), gospec.IsWithin(1e-12), 0.0)
+		}
+		c.Expect(p.Geometry().Kind, gospec.Equals, fftw.C2C)
+		c.Expect(p.Geometry().InPlace, gospec.IsFalse)
+	})
+
+	c.Specify("In place 2d transforms round trip.", func() {
+		a := fftw.Alloc2d(3, 4)
+		defer fftw.Free2d(a)
+		a[1][2] = 1 + 1i
+		PlanDft2d(a, a, fftw.Forward, fftw.Estimate).Execute()
+		c.Expect(real(a[0][1]), gospec.IsWithin(1e-12), -1.0)
+		c.Expect(imag(a[0][1]), gospec.IsWithin(1e-12), -1.0)
+		PlanDft2d(a, a, fftw.Backward, fftw.Estimate).Execute()
+		c.Expect(real(a[1][2]), gospec.IsWithin(1e-12), 12.0)
+	})
+
+	c.Specify("Complex-to-real plans leave their input alone.", func() {
+		x := []float64{1, 4, 2, 8, 5, 7}
+		s := make([]complex128, 4)
+		PlanDftR2C1d(x, s, fftw.Estimate).Execute()
+		c.Expect(real(s[0]), gospec.IsWithin(1e-12), 27.0)
+		saved := append([]complex128(nil), s...)
+		back := make([]float64, 6)
+		PlanDftC2R1d(s, back, fftw.Estimate).Execute()
+		for i := range x {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-12), x[i])
+		}
+		c.Expect(s, gospec.ContainsExactly, saved)
+	})
+
+	c.Specify("Multi-dimensional complex-to-real plans accept PreserveInput.", func() {
+		x := []float64{1, 4, 2, 8, 5, 7}
+		s := make([]complex128, 4)
+		PlanDftR2CNd([]int{2, 3}, x, s, fftw.Estimate).Execute()
+		saved := append([]complex128(nil), s...)
+		back := make([]float64, 6)
+		PlanDftC2RNd([]int{2, 3}, s, back, fftw.Estimate|fftw.PreserveInput).Execute()
+		for i := range x {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-12), x[i])
+		}
+		c.Expect(s, gospec.ContainsExactly, saved)
+	})
+
+	c.Specify("In place real transforms are refused.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		s := make([]complex128, 4)
+		PlanDftR2C1d(float64s(s)[:6], s, fftw.Estimate)
+	})
+}
+
+func TestAllSpecs(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(FFTWLSpec)
+	gospec.MainGoTest(r, t)
+}
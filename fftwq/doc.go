@@ -0,0 +1,14 @@
+// Package fftwq binds fftw's quad precision library, fftwq, for metrology
+// and for generating reference results to check other transforms against.
+//
+// Go has no 128 bit float, so values are passed as Quads, unevaluated sums
+// of two float64s that hold about 106 bits of mantissa, which can be
+// converted to and from big.Floats.  Plans keep __float128 copies of their
+// arrays: Execute converts the input to quad precision, transforms it and
+// converts the result back.
+//
+// The package needs fftw built with --enable-quad-precision and gcc's
+// libquadmath, and is only built with the quad build tag, as in
+//
+//	go build -tags quad
+package fftwq
@@ -0,0 +1,170 @@
+//go:build quad
+
+package fftwq
+
+// #cgo pkg-config: fftw3q
+// #cgo LDFLAGS: -lquadmath
+// #include <stddef.h>
+// #include <fftw3.h>
+//
+// // cgo can't pass __float128s, so the arrays are handled as void * and
+// // only touched in C, each value going to and from Go as a pair of
+// // doubles whose sum it is.
+//
+// static void *alloc_q(ptrdiff_t n) {
+//   return fftwq_malloc(n * sizeof(__float128));
+// }
+//
+// static void widen(void *dst, const double *src, ptrdiff_t n) {
+//   __float128 *d = dst;
+//   for (ptrdiff_t i = 0; i < n; i++) d[i] = (__float128)src[2*i] + src[2*i+1];
+// }
+//
+// static void narrow(double *dst, const void *src, ptrdiff_t n) {
+//   const __float128 *s = src;
+//   for (ptrdiff_t i = 0; i < n; i++) {
+//     double hi = (double)s[i];
+//     dst[2*i] = hi;
+//     dst[2*i+1] = (double)(s[i] - hi);
+//   }
+// }
+//
+// static fftwq_plan plan_dft(int rank, const int *n, void *in, void *out, int sign, unsigned flags) {
+//   return fftwq_plan_dft(rank, n, in, out, sign, flags);
+// }
+import "C"
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/runningwild/go-fftw"
+)
+
+// A Quad is the value Hi + Lo, where Lo is at most half a unit in the last
+// place of Hi.
+type Quad struct {
+	Hi, Lo float64
+}
+
+// A Complex is a complex number with Quad parts.
+type Complex struct {
+	Re, Im Quad
+}
+
+// Float returns q rounded to a float64.
+func (q Quad) Float() float64 {
+	return q.Hi + q.Lo
+}
+
+// Big returns q exactly as a big.Float.
+func (q Quad) Big() *big.Float {
+	f := new(big.Float).SetPrec(113).SetFloat64(q.Hi)
+	return f.Add(f, new(big.Float).SetFloat64(q.Lo))
+}
+
+// FromFloat returns the Quad equal to x.
+func FromFloat(x float64) Quad {
+	return Quad{Hi: x}
+}
+
+// FromBig returns f rounded to a Quad.
+func FromBig(f *big.Float) Quad {
+	hi, _ := f.Float64()
+	rest := new(big.Float).SetPrec(f.Prec()).Sub(f, new(big.Float).SetFloat64(hi))
+	lo, _ := rest.Float64()
+	return Quad{hi, lo}
+}
+
+// FromComplex128 returns the Complex equal to z.
+func FromComplex128(z complex128) Complex {
+	return Complex{FromFloat(real(z)), FromFloat(imag(z))}
+}
+
+// Complex128 returns z rounded to a complex128.
+func (z Complex) Complex128() complex128 {
+	return complex(z.Re.Float(), z.Im.Float())
+}
+
+type Plan struct {
+	fftw_p C.fftwq_plan
+	geom   fftw.Geometry
+	// The Go arrays and their __float128 copies.
+	in, out   []Complex
+	qIn, qOut unsafe.Pointer
+}
+
+// planner guards fftwq's planner, which isn't safe for concurrent use, as
+// every plan is made and destroyed.
+var planner sync.Mutex
+
+func destroyPlan(p *Plan) {
+	planner.Lock()
+	C.fftwq_destroy_plan(p.fftw_p)
+	planner.Unlock()
+	C.fftwq_free(p.qIn)
+	C.fftwq_free(p.qOut)
+}
+
+func PlanDft1d(in, out []Complex, dir fftw.Direction, flag fftw.Flag) *Plan {
+	if len(in) != len(out) {
+		panic(fmt.Sprint("PlanDft1d needs arrays of the same length, got ", len(in), " and ", len(out)))
+	}
+	return PlanDftNd([]int{len(in)}, in, out, dir, flag)
+}
+
+// PlanDftNd plans a transform of any rank on the row-major arrays in and
+// out, whose dimensions are dims.  The quad precision copies are always
+// separate, so fftwq's plans are out of place even when in and out are the
+// same array.
+func PlanDftNd(dims []int, in, out []Complex, dir fftw.Direction, flag fftw.Flag) *Plan {
+	if len(dims) == 0 {
+		panic("PlanDftNd needs at least one dimension")
+	}
+	size := 1
+	n := make([]C.int, len(dims))
+	for i, d := range dims {
+		if d < 1 || int(C.int(d)) != d {
+			panic(fmt.Sprint("PlanDftNd needs positive dimensions that fit in a C int, got ", dims))
+		}
+		size *= d
+		n[i] = C.int(d)
+	}
+	if len(in) < size || len(out) < size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	p := &Plan{in: in[:size], out: out[:size]}
+	p.geom = fftw.Geometry{Kind: fftw.C2C, Dims: append([]int(nil), dims...), Dir: dir, InPlace: &in[0] == &out[0]}
+	p.qIn = C.alloc_q(C.ptrdiff_t(2 * size))
+	p.qOut = C.alloc_q(C.ptrdiff_t(2 * size))
+	if p.qIn == nil || p.qOut == nil {
+		panic(fmt.Sprint("Could not fftwq_malloc for ", size, " elements"))
+	}
+	planner.Lock()
+	p.fftw_p = C.plan_dft(C.int(len(n)), &n[0], p.qIn, p.qOut, C.int(dir), C.uint(fftw.PlannerFlags(flag)))
+	planner.Unlock()
+	if p.fftw_p == nil {
+		panic(fmt.Sprint("fftwq could not plan a transform of dimensions ", dims))
+	}
+	runtime.SetFinalizer(p, destroyPlan)
+	return p
+}
+
+// Execute transforms the plan's input array, in quad precision, into its
+// output array.
+func (p *Plan) Execute() {
+	C.widen(p.qIn, (*C.double)(unsafe.Pointer(&p.in[0])), C.ptrdiff_t(2*len(p.in)))
+	C.fftwq_execute(p.fftw_p)
+	C.narrow((*C.double)(unsafe.Pointer(&p.out[0])), p.qOut, C.ptrdiff_t(2*len(p.out)))
+	runtime.KeepAlive(p)
+}
+
+// Geometry returns the shape of the transform p was planned for.
+func (p *Plan) Geometry() fftw.Geometry {
+	g := p.geom
+	g.Dims = append([]int(nil), g.Dims...)
+	return g
+}
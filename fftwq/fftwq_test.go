@@ -0,0 +1,51 @@
+//go:build quad
+
+package fftwq
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	"github.com/runningwild/go-fftw"
+)
+
+func FFTWQSpec(c gospec.Context) {
+	c.Specify("Quads carry more precision than a float64.", func() {
+		third := new(big.Float).SetPrec(113).Quo(big.NewFloat(1), big.NewFloat(3))
+		q := FromBig(third)
+		c.Expect(q.Hi, gospec.Equals, 1.0/3)
+		c.Expect(q.Lo != 0, gospec.IsTrue)
+		diff := new(big.Float).Sub(q.Big(), third)
+		d, _ := diff.Float64()
+		c.Expect(math.Abs(d) < 1e-32, gospec.IsTrue)
+	})
+
+	c.Specify("Transforms match double precision ones and round trip.", func() {
+		x := make([]complex128, 10)
+		in := make([]Complex, 10)
+		for i := range x {
+			x[i] = complex(math.Sin(float64(i)), float64(i%3))
+			in[i] = FromComplex128(x[i])
+		}
+		want := make([]complex128, 10)
+		fftw.PlanDft1d(x, want, fftw.Forward, fftw.Estimate).Execute()
+		out := make([]Complex, 10)
+		PlanDft1d(in, out, fftw.Forward, fftw.Estimate).Execute()
+		for k := range out {
+			c.Expect(real(out[k].Complex128()), gospec.IsWithin(1e-12), real(want[k]))
+			c.Expect(imag(out[k].Complex128()), gospec.IsWithin(1e-12), imag(want[k]))
+		}
+		PlanDft1d(out, out, fftw.Backward, fftw.Estimate).Execute()
+		for i := range out {
+			c.Expect(out[i].Re.Float()/10, gospec.IsWithin(1e-15), real(x[i]))
+		}
+	})
+}
+
+func TestAllSpecs(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(FFTWQSpec)
+	gospec.MainGoTest(r, t)
+}
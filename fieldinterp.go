@@ -0,0 +1,82 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// A FieldInterpolator samples a periodic field between its grid points
+// with the field's trigonometric interpolant, which is exact for band
+// limited fields and otherwise converges spectrally as the grid is refined.
+// Each sample costs time proportional to the size of the grid.
+type FieldInterpolator struct {
+	dims []int
+	// spectrum is the half spectrum of the field, normalized.
+	spectrum []complex128
+	// basis holds, for each dimension, the basis function of each bin at
+	// the point being sampled.
+	basis [][]complex128
+}
+
+func newFieldInterpolator(dims []int, flat []float64) *FieldInterpolator {
+	f := &FieldInterpolator{dims: dims, spectrum: realSpectrum(dims, flat)}
+	Scale(f.spectrum, 1/float64(len(flat)))
+	f.basis = make([][]complex128, len(dims))
+	for d, n := range dims {
+		f.basis[d] = make([]complex128, n)
+	}
+	return f
+}
+
+// NewFieldInterpolator2d returns an interpolator for the periodic field u.
+func NewFieldInterpolator2d(u [][]float64) *FieldInterpolator {
+	return newFieldInterpolator(flatten2d(u))
+}
+
+// NewFieldInterpolator3d returns an interpolator for the periodic field u.
+func NewFieldInterpolator3d(u [][][]float64) *FieldInterpolator {
+	return newFieldInterpolator(flatten3d(u))
+}
+
+// At returns the field at the point p, in grid units along each axis, so
+// that At(i, j) is u[i][j].  Points outside the grid wrap around it.  At
+// isn't safe for concurrent use.
+func (f *FieldInterpolator) At(p ...float64) float64 {
+	if len(p) != len(f.dims) {
+		panic(fmt.Sprint("Sampling a field of dimensions ", f.dims, " needs a point of as many coordinates, got ", p))
+	}
+	for d, n := range f.dims {
+		for j := range f.basis[d] {
+			if 2*j == n {
+				// The Nyquist bin is split evenly between the positive and
+				// negative frequencies, so that the interpolant is real.
+				f.basis[d][j] = complex(math.Cos(math.Pi*p[d]), 0)
+				continue
+			}
+			k := j
+			if 2*k > n {
+				k -= n
+			}
+			f.basis[d][j] = cmplx.Exp(complex(0, 2*math.Pi*float64(k)*p[d]/float64(n)))
+		}
+	}
+	v := 0.0
+	forEachMode(f.dims, func(i int, k []int, weight int) {
+		t := f.spectrum[i]
+		for d, kd := range k {
+			t *= f.basis[d][mod(kd, f.dims[d])]
+		}
+		v += float64(weight) * real(t)
+	})
+	return v
+}
+
+// Sample returns the field at each of points, as At gives it.
+func (f *FieldInterpolator) Sample(points [][]float64) []float64 {
+	v := make([]float64, len(points))
+	for i, p := range points {
+		v[i] = f.At(p...)
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package fftw
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func FieldInterpolatorSpec(c gospec.Context) {
+	c.Specify("Interpolants pass through the grid points.", func() {
+		rng := rand.New(rand.NewSource(5))
+		u := alloc2dReal(6, 5)
+		for i := range u {
+			for j := range u[i] {
+				u[i][j] = rng.NormFloat64()
+			}
+		}
+		f := NewFieldInterpolator2d(u)
+		for i := range u {
+			for j := range u[i] {
+				c.Expect(f.At(float64(i), float64(j)), gospec.IsWithin(1e-12), u[i][j])
+			}
+		}
+		c.Expect(f.At(-1, 7), gospec.IsWithin(1e-12), u[5][2])
+	})
+
+	c.Specify("Band limited fields are sampled exactly between grid points.", func() {
+		field := func(x, y, z float64) float64 {
+			return math.Sin(2*math.Pi*x/8) + math.Cos(2*math.Pi*(2*y/6+z/4)) + 0.5
+		}
+		u := alloc3dReal(8, 6, 4)
+		for i := range u {
+			for j := range u[i] {
+				for k := range u[i][j] {
+					u[i][j][k] = field(float64(i), float64(j), float64(k))
+				}
+			}
+		}
+		f := NewFieldInterpolator3d(u)
+		points := [][]float64{{0.3, 1.7, 2.2}, {7.9, 0.1, 3.5}, {12.25, -3.5, 0.75}}
+		for i, v := range f.Sample(points) {
+			p := points[i]
+			c.Expect(v, gospec.IsWithin(1e-12), field(p[0], p[1], p[2]))
+		}
+	})
+}
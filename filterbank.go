@@ -0,0 +1,120 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A Filterbank is a set of causal FIR filters, one per band, applied by FFT
+// convolution.  Filters may be built by hand or by GammatoneFilterbank and
+// OctaveFilterbank.
+type Filterbank struct {
+	// Centers holds the centre frequency of each band, in Hz.
+	Centers []float64
+	// Filters holds the impulse response of each band.
+	Filters [][]float64
+}
+
+// Filter returns the first len(x) samples of the output of each band for
+// the input x.  Like any causal filter, each band lags x by its group delay.
+func (f *Filterbank) Filter(x []float64) [][]float64 {
+	out := make([][]float64, len(f.Filters))
+	for i, h := range f.Filters {
+		out[i] = Convolve(x, h, Full, ZeroBoundary)[:len(x)]
+	}
+	return out
+}
+
+// Energies returns the mean square output of each band for the input x.
+// Energies of successive frames of a signal, one slice per frame, have the
+// same layout as the band powers of SlidingBandPowers.
+func (f *Filterbank) Energies(x []float64) []float64 {
+	e := make([]float64, len(f.Filters))
+	if len(x) == 0 {
+		return e
+	}
+	for i, y := range f.Filter(x) {
+		for _, v := range y {
+			e[i] += v * v
+		}
+		e[i] /= float64(len(x))
+	}
+	return e
+}
+
+// erb returns the equivalent rectangular bandwidth of the auditory filter
+// centred on f Hz, from Glasberg and Moore.
+func erb(f float64) float64 {
+	return 24.7 + 0.108*f
+}
+
+// GammatoneFilterbank returns fourth order gammatone filters centred on
+// centers, the usual model of the auditory filters, truncated to taps
+// samples.  Each filter has unit gain at its centre frequency.
+func GammatoneFilterbank(centers []float64, sampleRate float64, taps int) *Filterbank {
+	fb := &Filterbank{Centers: centers, Filters: make([][]float64, len(centers))}
+	for i, fc := range centers {
+		if fc <= 0 || fc >= sampleRate/2 {
+			panic(fmt.Sprint("GammatoneFilterbank centre ", fc, " is outside (0, ", sampleRate/2, ")"))
+		}
+		b := 2 * math.Pi * 1.019 * erb(fc)
+		h := make([]float64, taps)
+		var re, im float64
+		for n := range h {
+			t := float64(n) / sampleRate
+			c, s := math.Cos(2*math.Pi*fc*t), math.Sin(2*math.Pi*fc*t)
+			h[n] = t * t * t * math.Exp(-b*t) * c
+			// Accumulate the response at fc, for normalising.
+			re += h[n] * c
+			im += h[n] * s
+		}
+		if g := math.Hypot(re, im); g > 0 {
+			ScaleReal(h, 1/g)
+		}
+		fb.Filters[i] = h
+	}
+	return fb
+}
+
+// OctaveFilterbank returns 1/fraction octave band-pass filters, with centres
+// on the base two series 1000*2^(k/fraction) Hz between low and high.  The
+// filters are Hann windowed sinc designs of taps samples, linear phase with a
+// delay of (taps-1)/2 samples.
+func OctaveFilterbank(fraction int, low, high, sampleRate float64, taps int) *Filterbank {
+	if fraction < 1 {
+		panic(fmt.Sprint("OctaveFilterbank needs a positive fraction, got ", fraction))
+	}
+	if low <= 0 || high < low || sampleRate <= 0 {
+		panic(fmt.Sprint("OctaveFilterbank needs 0 < low <= high and a positive sample rate, got ", low, ", ", high, " and ", sampleRate))
+	}
+	fb := new(Filterbank)
+	k := int(math.Ceil(float64(fraction) * math.Log2(low/1000)))
+	for ; ; k++ {
+		fc := 1000 * math.Pow(2, float64(k)/float64(fraction))
+		if fc > high {
+			break
+		}
+		edge := math.Pow(2, 1/(2*float64(fraction)))
+		fl, fh := fc/edge/sampleRate, fc*edge/sampleRate
+		if fh > 0.5 {
+			fh = 0.5
+		}
+		h := Window1d(taps, Hann)
+		mid := float64(taps-1) / 2
+		for n := range h {
+			t := float64(n) - mid
+			h[n] *= 2*fh*sinc(2*fh*t) - 2*fl*sinc(2*fl*t)
+		}
+		fb.Centers = append(fb.Centers, fc)
+		fb.Filters = append(fb.Filters, h)
+	}
+	return fb
+}
+
+// sinc returns the normalised sinc function, sin(pi x)/(pi x).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
@@ -0,0 +1,46 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func FilterbankSpec(c gospec.Context) {
+	rate := 8000.0
+	x := make([]float64, 1024)
+	for i := range x {
+		x[i] = math.Sin(2 * math.Pi * 1000 * float64(i) / rate)
+	}
+
+	c.Specify("Gammatone filters pass tones at their centre frequency.", func() {
+		fb := GammatoneFilterbank([]float64{250, 1000, 3000}, rate, 256)
+		e := fb.Energies(x)
+		c.Expect(e[1] > 10*e[0], gospec.IsTrue)
+		c.Expect(e[1] > 10*e[2], gospec.IsTrue)
+		// A unit sine has a mean square of a half, less the edges.
+		c.Expect(e[1], gospec.IsWithin(0.1), 0.5)
+	})
+
+	c.Specify("Octave filters have their centres on the base two series.", func() {
+		fb := OctaveFilterbank(3, 250, 3000, rate, 255)
+		c.Expect(len(fb.Centers), gospec.Equals, len(fb.Filters))
+		best := 0
+		e := fb.Energies(x)
+		for i := range e {
+			if e[i] > e[best] {
+				best = i
+			}
+		}
+		c.Expect(fb.Centers[best], gospec.IsWithin(1e-9), 1000.0)
+		c.Expect(e[best], gospec.IsWithin(0.1), 0.5)
+	})
+
+	c.Specify("Octave filterbanks reject bands that don't make sense.", func() {
+		for _, b := range [][3]float64{{0, 3000, rate}, {-10, 3000, rate}, {3000, 250, rate}, {250, 3000, 0}} {
+			func() {
+				defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+				OctaveFilterbank(3, b[0], b[1], b[2], 255)
+			}()
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package fftw
+
+// DftFrames computes the real-to-complex transform of each frame in frames.
+// Frames shorter than the longest frame, such as a ragged final frame, are
+// zero padded to its length n, and each result has n/2+1 elements.
+//
+// All of the frames are packed into a single aligned buffer and transformed
+// by one batched plan, which is much faster than planning and executing each
+// frame separately.
+func DftFrames(frames [][]float64) [][]complex128 {
+	n := 0
+	for _, f := range frames {
+		if len(f) > n {
+			n = len(f)
+		}
+	}
+	if n == 0 {
+		return make([][]complex128, len(frames))
+	}
+	m := n/2 + 1
+	in := allocReal1d(len(frames) * n)
+	defer freeReal1d(in)
+	out := Alloc1d(len(frames) * m)
+	defer Free1d(out)
+	for i, f := range frames {
+		copy(in[i*n:], f)
+	}
+
+	PlanManyDftR2C([]int{n}, len(frames), in, out, Estimate).Execute()
+
+	// Copy the results out of fftw's memory so that it can be freed.
+	spectra := make([][]complex128, len(frames))
+	data := make([]complex128, len(out))
+	copy(data, out)
+	for i := range spectra {
+		spectra[i] = data[i*m : (i+1)*m]
+	}
+	return spectra
+}
@@ -0,0 +1,38 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func DftFramesSpec(c gospec.Context) {
+	frames := make([][]float64, 5)
+	for i := range frames {
+		frames[i] = make([]float64, 16)
+		for j := range frames[i] {
+			frames[i][j] = math.Sin(float64(j*(i+1))) + float64(i)
+		}
+	}
+	// The final frame is ragged.
+	frames[4] = frames[4][:11]
+	spectra := DftFrames(frames)
+
+	c.Specify("Each frame is transformed as if by PlanDftR2C1d.", func() {
+		c.Expect(len(spectra), gospec.Equals, len(frames))
+		for i := range frames {
+			signal := make([]float64, 16)
+			copy(signal, frames[i])
+			expected := make([]complex128, 9)
+			PlanDftR2C1d(signal, expected, Estimate).Execute()
+			c.Expect(len(spectra[i]), gospec.Equals, 9)
+			for k := range expected {
+				c.Expect(cmplx.Abs(spectra[i][k]-expected[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	c.Specify("Transforming no frames gives no spectra.", func() {
+		c.Expect(len(DftFrames(nil)), gospec.Equals, 0)
+	})
+}
@@ -0,0 +1,177 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// FringeOptions controls FringeAnalysis.
+type FringeOptions struct {
+	// Carrier is the spatial frequency of the fringes, in cycles per
+	// sample down the rows and along the columns.  If it is zero the
+	// strongest peak of the spectrum away from its centre is used.
+	Carrier [2]float64
+	// Bandwidth is the radius, in cycles per sample, of the band kept
+	// around the carrier.  It defaults to half the carrier's frequency,
+	// which keeps the band clear of the spectrum's centre.
+	Bandwidth float64
+}
+
+// A Fringes holds the result of FringeAnalysis.
+type Fringes struct {
+	// Carrier is the carrier frequency that was removed, refined to a
+	// fraction of a bin if it was found from the spectrum.
+	Carrier [2]float64
+	// Wrapped is the phase of the fringes, less the carrier, wrapped
+	// into (-π, π].
+	Wrapped [][]float64
+	// Phase is Wrapped unwrapped with UnwrapPhase2d.
+	Phase [][]float64
+	// Modulation is the amplitude of the fringes, low where they fade
+	// out and their phase can't be trusted.
+	Modulation [][]float64
+}
+
+// FringeAnalysis recovers the phase of the carrier fringes of an
+// interferogram by Fourier transform profilometry: it selects the band of
+// the spectrum around the carrier, transforms it back to get the analytic
+// fringe signal, removes the carrier's linear phase ramp and unwraps what
+// is left.
+func FringeAnalysis(img [][]float64, opts FringeOptions) *Fringes {
+	n0 := len(img)
+	if n0 < 2 || len(img[0]) < 2 {
+		panic("FringeAnalysis needs an image of at least 2x2")
+	}
+	n1 := len(img[0])
+	a := Alloc2d(n0, n1)
+	defer Free2d(a)
+	for i := range img {
+		if len(img[i]) != n1 {
+			panic(fmt.Sprint("FringeAnalysis needs a rectangular image, row ", i, " has length ", len(img[i]), ", not ", n1))
+		}
+		for j, v := range img[i] {
+			a[i][j] = complex(v, 0)
+		}
+	}
+	PlanDft2d(a, a, Forward, Estimate).Execute()
+
+	f := &Fringes{Carrier: opts.Carrier}
+	if f.Carrier == [2]float64{} {
+		f.Carrier = fringeCarrier(a)
+	}
+	band := opts.Bandwidth
+	if band <= 0 {
+		band = math.Hypot(f.Carrier[0], f.Carrier[1]) / 2
+	}
+	// Keep the lobe around the carrier, tapering it to zero at the edge
+	// of the band so that the cut doesn't ring, and drop its conjugate.
+	taper := Tukey(0.25)
+	for i := range a {
+		for j := range a[i] {
+			d := math.Hypot(signedFreq(i, n0)-f.Carrier[0], signedFreq(j, n1)-f.Carrier[1]) / band
+			a[i][j] *= complex(taper(d), 0)
+		}
+	}
+	PlanDft2d(a, a, Backward, Estimate).Execute()
+
+	f.Wrapped = alloc2dReal(n0, n1)
+	f.Modulation = alloc2dReal(n0, n1)
+	scale := 2 / float64(n0*n1)
+	for i := range a {
+		for j, v := range a[i] {
+			ramp := -2 * math.Pi * (f.Carrier[0]*float64(i) + f.Carrier[1]*float64(j))
+			v *= cmplx.Rect(scale, ramp)
+			f.Wrapped[i][j] = cmplx.Phase(v)
+			f.Modulation[i][j] = cmplx.Abs(v)
+		}
+	}
+	f.Phase = UnwrapPhase2d(f.Wrapped)
+	return f
+}
+
+// fringeCarrier returns the frequency of the strongest peak of the
+// spectrum a in the half plane of positive frequencies, leaving out the
+// bins next to its centre, refined by fitting a parabola through the peak
+// and its neighbours along each axis.
+func fringeCarrier(a [][]complex128) [2]float64 {
+	n0, n1 := len(a), len(a[0])
+	power := func(i, j int) float64 {
+		v := a[(i+n0)%n0][(j+n1)%n1]
+		return real(v)*real(v) + imag(v)*imag(v)
+	}
+	bi, bj, best := 0, 0, -1.0
+	for i := range a {
+		for j := range a[i] {
+			fi, fj := signedFreq(i, n0)*float64(n0), signedFreq(j, n1)*float64(n1)
+			if fj < 0 || (fj == 0 && fi <= 0) || (math.Abs(fi) <= 1 && math.Abs(fj) <= 1) {
+				continue
+			}
+			if p := power(i, j); p > best {
+				bi, bj, best = i, j, p
+			}
+		}
+	}
+	refine := func(l, c, r float64) float64 {
+		if d := l - 2*c + r; d < 0 {
+			return 0.5 * (l - r) / d
+		}
+		return 0
+	}
+	di := refine(power(bi-1, bj), best, power(bi+1, bj))
+	dj := refine(power(bi, bj-1), best, power(bi, bj+1))
+	return [2]float64{signedFreq(bi, n0) + di/float64(n0), signedFreq(bj, n1) + dj/float64(n1)}
+}
+
+// UnwrapPhase2d unwraps the phase wrapped into (-π, π] by least squares,
+// finding the phase whose gradients best match the wrapped differences of
+// wrapped, as Ghiglia and Romero do, by solving the Poisson equation they
+// lead to with Dct2d.  Where the true phase changes by less than π between
+// neighbours it is recovered exactly.  The result is offset to agree with
+// wrapped at the first sample.
+func UnwrapPhase2d(wrapped [][]float64) [][]float64 {
+	n0 := len(wrapped)
+	if n0 == 0 {
+		return nil
+	}
+	n1 := len(wrapped[0])
+	wrap := func(d float64) float64 {
+		return d - 2*math.Pi*math.Round(d/(2*math.Pi))
+	}
+	dx := func(i, j int) float64 {
+		if j < 0 || j >= n1-1 {
+			return 0
+		}
+		return wrap(wrapped[i][j+1] - wrapped[i][j])
+	}
+	dy := func(i, j int) float64 {
+		if i < 0 || i >= n0-1 {
+			return 0
+		}
+		return wrap(wrapped[i+1][j] - wrapped[i][j])
+	}
+	rho := alloc2dReal(n0, n1)
+	for i := range rho {
+		for j := range rho[i] {
+			rho[i][j] = dx(i, j) - dx(i, j-1) + dy(i, j) - dy(i-1, j)
+		}
+	}
+	r := Dct2d(rho)
+	for i := range r {
+		for j := range r[i] {
+			if i == 0 && j == 0 {
+				r[i][j] = 0
+				continue
+			}
+			r[i][j] /= 2*math.Cos(math.Pi*float64(i)/float64(n0)) + 2*math.Cos(math.Pi*float64(j)/float64(n1)) - 4
+		}
+	}
+	phi := Idct2d(r)
+	offset := wrapped[0][0] - phi[0][0]
+	for i := range phi {
+		for j := range phi[i] {
+			phi[i][j] += offset
+		}
+	}
+	return phi
+}
@@ -0,0 +1,60 @@
+package fftw
+
+import (
+	"math"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func FringeSpec(c gospec.Context) {
+	c.Specify("Unwrapping recovers phase that changes slowly between samples.", func() {
+		want := alloc2dReal(12, 10)
+		wrapped := alloc2dReal(12, 10)
+		for i := range want {
+			for j := range want[i] {
+				want[i][j] = 0.9*float64(i) + 0.4*float64(j) + 0.02*float64(i*j)
+				wrapped[i][j] = math.Remainder(want[i][j], 2*math.Pi)
+			}
+		}
+		got := UnwrapPhase2d(wrapped)
+		for i := range got {
+			for j := range got[i] {
+				c.Expect(got[i][j], gospec.IsWithin(1e-9), want[i][j])
+			}
+		}
+	})
+
+	c.Specify("The carrier is found to a fraction of a bin.", func() {
+		img := alloc2dReal(32, 32)
+		for i := range img {
+			for j := range img[i] {
+				img[i][j] = 2 + math.Cos(2*math.Pi*(0.09*float64(i)+0.2*float64(j)))
+			}
+		}
+		f := FringeAnalysis(img, FringeOptions{})
+		c.Expect(f.Carrier[0], gospec.IsWithin(0.01), 0.09)
+		c.Expect(f.Carrier[1], gospec.IsWithin(0.01), 0.2)
+	})
+
+	c.Specify("The phase of carrier fringes is recovered.", func() {
+		n := 32
+		// The phase is periodic, like the image, and spans more than 2π.
+		phase := func(i, j int) float64 {
+			return 1.5 * (math.Cos(2*math.Pi*float64(i)/float64(n)) + math.Cos(2*math.Pi*float64(j)/float64(n)))
+		}
+		img := alloc2dReal(n, n)
+		for i := range img {
+			for j := range img[i] {
+				img[i][j] = 2 + math.Cos(2*math.Pi*0.25*float64(j)+phase(i, j))
+			}
+		}
+		f := FringeAnalysis(img, FringeOptions{Carrier: [2]float64{0, 0.25}, Bandwidth: 0.2})
+		offset := f.Phase[0][0] - phase(0, 0)
+		for i := range img {
+			for j := range img[i] {
+				c.Expect(f.Phase[i][j]-offset, gospec.IsWithin(0.05), phase(i, j))
+				c.Expect(f.Modulation[i][j], gospec.IsWithin(0.05), 1.0)
+			}
+		}
+	})
+}
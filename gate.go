@@ -0,0 +1,116 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// GateOptions configures a SpectralGate.
+type GateOptions struct {
+	// Threshold is how far, in dB, a bin's power must rise above the noise
+	// profile for the gate to open.
+	Threshold float64
+	// Reduction is how far, in dB, a closed gate attenuates its bin.  Zero
+	// silences closed bins entirely.
+	Reduction float64
+	// Attack and Release are the time constants, in frames, with which a
+	// bin's gain rises as its gate opens and falls as it closes.  Zero
+	// switches the gain immediately.
+	Attack, Release float64
+}
+
+// A SpectralGate is a noise gate applied bin by bin to successive STFT
+// frames: bins whose power stays near the noise profile are attenuated and
+// bins that rise above it are passed, with each bin's gain smoothed over
+// time so that the gate doesn't chatter.  Frames are gated in place without
+// allocating.  A SpectralGate is not safe for concurrent use.
+type SpectralGate struct {
+	threshold []float64
+	floor     float64
+	attack    float64
+	release   float64
+	gain      []float64
+}
+
+// NoiseProfile returns the mean power of each bin of spectra, such as the
+// STFT of a stretch of a recording holding only noise.
+func NoiseProfile(spectra [][]complex128) []float64 {
+	if len(spectra) == 0 {
+		return nil
+	}
+	profile := make([]float64, len(spectra[0]))
+	for _, s := range spectra {
+		for k, v := range s {
+			profile[k] += real(v)*real(v) + imag(v)*imag(v)
+		}
+	}
+	ScaleReal(profile, 1/float64(len(spectra)))
+	return profile
+}
+
+// smoothing returns the coefficient of a one pole smoother with a time
+// constant of tc frames.
+func smoothing(tc float64) float64 {
+	if tc <= 0 {
+		return 0
+	}
+	return math.Exp(-1 / tc)
+}
+
+// NewSpectralGate returns a gate for frames with one bin per element of
+// noise, the power of the noise in each bin.  Every bin starts closed.
+func NewSpectralGate(noise []float64, opts GateOptions) *SpectralGate {
+	if opts.Reduction < 0 {
+		panic(fmt.Sprint("NewSpectralGate needs a non-negative reduction, got ", opts.Reduction))
+	}
+	g := &SpectralGate{attack: smoothing(opts.Attack), release: smoothing(opts.Release)}
+	if opts.Reduction > 0 {
+		g.floor = math.Pow(10, -opts.Reduction/20)
+	}
+	scale := math.Pow(10, opts.Threshold/10)
+	g.threshold = make([]float64, len(noise))
+	for k, p := range noise {
+		g.threshold[k] = p * scale
+	}
+	g.gain = make([]float64, len(noise))
+	g.Reset()
+	return g
+}
+
+// Reset closes every bin, as at the start of a new signal.
+func (g *SpectralGate) Reset() {
+	for k := range g.gain {
+		g.gain[k] = g.floor
+	}
+}
+
+// Gains returns the gain each bin had on the last frame gated.  It is
+// owned by the gate and changes with each frame.
+func (g *SpectralGate) Gains() []float64 {
+	return g.gain
+}
+
+// Process gates frame in place.
+func (g *SpectralGate) Process(frame []complex128) {
+	if len(frame) != len(g.gain) {
+		panic(fmt.Sprint("SpectralGate frame has ", len(frame), " bins, expected ", len(g.gain)))
+	}
+	for k, v := range frame {
+		target, coef := g.floor, g.release
+		if real(v)*real(v)+imag(v)*imag(v) > g.threshold[k] {
+			target = 1
+		}
+		if target > g.gain[k] {
+			coef = g.attack
+		}
+		g.gain[k] = target + (g.gain[k]-target)*coef
+		frame[k] = v * complex(g.gain[k], 0)
+	}
+}
+
+// ProcessFrames gates each of spectra in place, in order.
+func (g *SpectralGate) ProcessFrames(spectra [][]complex128) {
+	for _, s := range spectra {
+		g.Process(s)
+	}
+}
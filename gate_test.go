@@ -0,0 +1,71 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func SpectralGateSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(1))
+	noise := make([]float64, 4096)
+	for i := range noise {
+		noise[i] = 0.01 * rng.NormFloat64()
+	}
+	s := NewSTFT(128, 32, Hann)
+	profile := NoiseProfile(s.Transform(noise))
+
+	c.Specify("Noise is attenuated and tones pass.", func() {
+		x := make([]float64, len(noise))
+		for i := range x {
+			x[i] = noise[len(noise)-1-i] + math.Sin(2*math.Pi*16*float64(i)/128)
+		}
+		spectra := s.Transform(x)
+		g := NewSpectralGate(profile, GateOptions{Threshold: 6, Reduction: 40, Attack: 1, Release: 4})
+		// Check the gains away from the zero padded frames at the ends.
+		g.ProcessFrames(spectra[:len(spectra)/2])
+		gains := append([]float64(nil), g.Gains()...)
+		g.ProcessFrames(spectra[len(spectra)/2:])
+		c.Expect(gains[16], gospec.IsWithin(1e-6), 1.0)
+		mean := 0.0
+		for _, v := range gains[32:] {
+			mean += v
+		}
+		c.Expect(mean/float64(len(gains)-32) < 0.1, gospec.IsTrue)
+		y := s.Inverse(spectra, len(x))
+		residual := 0.0
+		for i := 512; i < len(x)-512; i++ {
+			e := y[i] - math.Sin(2*math.Pi*16*float64(i)/128)
+			residual += e * e
+		}
+		// Most of the noise power is gone.
+		c.Expect(residual/float64(len(x)-1024) < 0.25*0.01*0.01, gospec.IsTrue)
+	})
+
+	c.Specify("Gains follow their attack and release time constants.", func() {
+		g := NewSpectralGate([]float64{1}, GateOptions{Reduction: 20, Attack: 2, Release: 10})
+		c.Expect(g.Gains()[0], gospec.IsWithin(1e-12), 0.1)
+		frame := []complex128{10}
+		g.Process(frame)
+		want := 1 - 0.9*math.Exp(-0.5)
+		c.Expect(g.Gains()[0], gospec.IsWithin(1e-12), want)
+		c.Expect(real(frame[0]), gospec.IsWithin(1e-12), 10*want)
+		frame[0] = 0.5
+		g.Process(frame)
+		c.Expect(g.Gains()[0], gospec.IsWithin(1e-12), 0.1+(want-0.1)*math.Exp(-0.1))
+		g.Reset()
+		c.Expect(g.Gains()[0], gospec.IsWithin(1e-12), 0.1)
+	})
+}
+
+// SpectralGateAllocsSpec is registered with its own runner, so that no
+// other spec allocates while it counts.
+func SpectralGateAllocsSpec(c gospec.Context) {
+	c.Specify("Gating doesn't allocate.", func() {
+		g := NewSpectralGate([]float64{1, 1, 1}, GateOptions{Threshold: 6, Attack: 1, Release: 4})
+		frame := []complex128{10, 0.5, 1}
+		allocs := testing.AllocsPerRun(10, func() { g.Process(frame) })
+		c.Expect(allocs, gospec.Equals, 0.0)
+	})
+}
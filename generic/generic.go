@@ -0,0 +1,153 @@
+// Package generic is a precision agnostic front end to packages fftw and
+// fftw32, so that the same code can transform float64 or float32 data by
+// changing a type parameter, as in
+//
+//	func spectrum[T generic.Float, C generic.Complex](x []T) []C {
+//		out := make([]C, len(x)/2+1)
+//		generic.PlanDftR2C1d[T](x, out, fftw.Estimate).Execute()
+//		return out
+//	}
+//
+// Plans are parameterized by their real type, and their complex arrays
+// must be of the matching complex type, complex128 for float64 and
+// complex64 for float32, which is checked when they are planned.
+package generic
+
+import (
+	"fmt"
+
+	"github.com/runningwild/go-fftw"
+	"github.com/runningwild/go-fftw/fftw32"
+)
+
+// Float is the real type of a transform.
+type Float interface {
+	float32 | float64
+}
+
+// Complex is the complex type of a transform.
+type Complex interface {
+	complex64 | complex128
+}
+
+// executor is what Plans of either precision have in common.
+type executor interface {
+	Execute()
+	Geometry() fftw.Geometry
+}
+
+// A Plan is a plan for a transform of precision T.
+type Plan[T Float] struct {
+	p executor
+}
+
+func (p *Plan[T]) Execute() {
+	p.p.Execute()
+}
+
+// Geometry returns the shape of the transform p was planned for.
+func (p *Plan[T]) Geometry() fftw.Geometry {
+	return p.p.Geometry()
+}
+
+// single reports whether T and C are float32 and complex64, and panics if
+// they are of different precisions.
+func single[T Float, C Complex]() bool {
+	var t T
+	var c C
+	_, t32 := any(t).(float32)
+	_, c64 := any(c).(complex64)
+	if t32 != c64 {
+		panic(fmt.Sprintf("Plans of %T need arrays of the matching complex type, got %T", t, c))
+	}
+	return t32
+}
+
+// Alloc1d returns a zeroed array of n elements allocated by fftw or fftwf,
+// which must be freed with Free1d.
+func Alloc1d[C Complex](n int) []C {
+	var c C
+	switch any(c).(type) {
+	case complex64:
+		return any(fftw32.Alloc1d(n)).([]C)
+	}
+	return any(fftw.Alloc1d(n)).([]C)
+}
+
+func Free1d[C Complex](x []C) {
+	switch x := any(x).(type) {
+	case []complex64:
+		fftw32.Free1d(x)
+	case []complex128:
+		fftw.Free1d(x)
+	}
+}
+
+func PlanDft1d[T Float, C Complex](in, out []C, dir fftw.Direction, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDft1d(any(in).([]complex64), any(out).([]complex64), dir, flag)}
+	}
+	return &Plan[T]{fftw.PlanDft1d(any(in).([]complex128), any(out).([]complex128), dir, flag)}
+}
+
+// PlanDft2d plans a transform of the contiguous n0 x n1 arrays in and out.
+func PlanDft2d[T Float, C Complex](in, out [][]C, dir fftw.Direction, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDft2d(any(in).([][]complex64), any(out).([][]complex64), dir, flag)}
+	}
+	return &Plan[T]{fftw.PlanDft2d(any(in).([][]complex128), any(out).([][]complex128), dir, flag)}
+}
+
+// PlanDft3d plans a transform of the contiguous n0 x n1 x n2 arrays in and
+// out.
+func PlanDft3d[T Float, C Complex](in, out [][][]C, dir fftw.Direction, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDft3d(any(in).([][][]complex64), any(out).([][][]complex64), dir, flag)}
+	}
+	return &Plan[T]{fftw.PlanDft3d(any(in).([][][]complex128), any(out).([][][]complex128), dir, flag)}
+}
+
+// PlanDftNd plans a transform of any rank on the row-major arrays in and
+// out, whose dimensions are dims.
+func PlanDftNd[T Float, C Complex](dims []int, in, out []C, dir fftw.Direction, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDftNd(dims, any(in).([]complex64), any(out).([]complex64), dir, flag)}
+	}
+	return &Plan[T]{fftw.PlanDftNd(dims, any(in).([]complex128), any(out).([]complex128), dir, flag)}
+}
+
+func PlanDftR2C1d[T Float, C Complex](in []T, out []C, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDftR2C1d(any(in).([]float32), any(out).([]complex64), flag)}
+	}
+	return &Plan[T]{fftw.PlanDftR2C1d(any(in).([]float64), any(out).([]complex128), flag)}
+}
+
+// PlanDftC2R1d plans the inverse of PlanDftR2C1d, which destroys its input
+// unless flag includes PreserveInput.
+func PlanDftC2R1d[T Float, C Complex](in []C, out []T, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDftC2R1d(any(in).([]complex64), any(out).([]float32), flag)}
+	}
+	return &Plan[T]{fftw.PlanDftC2R1d(any(in).([]complex128), any(out).([]float64), flag)}
+}
+
+// PlanDftR2CNd plans the transform of the real row-major array in, of
+// dimensions dims, into the non-redundant half of its spectrum, whose last
+// dimension is n/2+1 long.
+func PlanDftR2CNd[T Float, C Complex](dims []int, in []T, out []C, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDftR2CNd(dims, any(in).([]float32), any(out).([]complex64), flag)}
+	}
+	spec := fftw.PlanSpec{Kind: fftw.R2C, Dims: dims, Dir: fftw.Forward, Flag: flag}
+	return &Plan[T]{spec.PlanDftR2C(any(in).([]float64), any(out).([]complex128))}
+}
+
+// PlanDftC2RNd plans the inverse of PlanDftR2CNd, which destroys its input.
+func PlanDftC2RNd[T Float, C Complex](dims []int, in []C, out []T, flag fftw.Flag) *Plan[T] {
+	if single[T, C]() {
+		return &Plan[T]{fftw32.PlanDftC2RNd(dims, any(in).([]complex64), any(out).([]float32), flag)}
+	}
+	spec := fftw.PlanSpec{Kind: fftw.C2R, Dims: dims, Dir: fftw.Backward, Flag: flag}
+	return &Plan[T]{spec.PlanDftC2R(any(in).([]complex128), any(out).([]float64))}
+}
@@ -0,0 +1,67 @@
+package generic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	"github.com/runningwild/go-fftw"
+)
+
+// roundTrip is written once and run in both precisions.
+func roundTrip[T Float, C Complex](x []T) ([]C, []T) {
+	spectrum := make([]C, len(x)/2+1)
+	back := make([]T, len(x))
+	PlanDftR2C1d[T](x, spectrum, fftw.Estimate).Execute()
+	PlanDftC2R1d[T](append([]C(nil), spectrum...), back, fftw.Estimate).Execute()
+	for i := range back {
+		back[i] /= T(len(x))
+	}
+	return spectrum, back
+}
+
+func GenericSpec(c gospec.Context) {
+	c.Specify("The same code transforms in both precisions.", func() {
+		x64 := []float64{1, 2, 0, -1, 3, 5, 2, 0}
+		x32 := make([]float32, len(x64))
+		for i, v := range x64 {
+			x32[i] = float32(v)
+		}
+		s64, b64 := roundTrip[float64, complex128](x64)
+		s32, b32 := roundTrip[float32, complex64](x32)
+		for k := range s64 {
+			c.Expect(real(complex128(s32[k])), gospec.IsWithin(1e-5), real(s64[k]))
+			c.Expect(imag(complex128(s32[k])), gospec.IsWithin(1e-5), imag(s64[k]))
+		}
+		for i := range x64 {
+			c.Expect(b64[i], gospec.IsWithin(1e-12), x64[i])
+			c.Expect(float64(b32[i]), gospec.IsWithin(1e-5), x64[i])
+		}
+	})
+
+	c.Specify("Complex plans dispatch on their arrays.", func() {
+		a := Alloc1d[complex64](8)
+		defer Free1d(a)
+		a[1] = 1
+		p := PlanDft1d[float32](a, a, fftw.Forward, fftw.Estimate)
+		p.Execute()
+		c.Expect(float64(real(a[2])), gospec.IsWithin(1e-6), math.Cos(math.Pi/2))
+		c.Expect(float64(imag(a[2])), gospec.IsWithin(1e-6), -1.0)
+		c.Expect(p.Geometry().InPlace, gospec.IsTrue)
+
+		b := make([]complex128, 12)
+		q := PlanDftNd[float64]([]int{3, 4}, b, b, fftw.Forward, fftw.Estimate)
+		c.Expect(q.Geometry().Dims, gospec.ContainsExactly, []int{3, 4})
+	})
+
+	c.Specify("Mismatched precisions are rejected.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanDft1d[float32](make([]complex128, 4), make([]complex128, 4), fftw.Forward, fftw.Estimate)
+	})
+}
+
+func TestAllSpecs(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(GenericSpec)
+	gospec.MainGoTest(r, t)
+}
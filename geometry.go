@@ -0,0 +1,171 @@
+package fftw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+type Kind int
+
+const (
+	C2C Kind = iota
+	R2C
+	C2R
+	R2R
+)
+
+func (k Kind) String() string {
+	switch k {
+	case C2C:
+		return "c2c"
+	case R2C:
+		return "r2c"
+	case C2R:
+		return "c2r"
+	case R2R:
+		return "r2r"
+	}
+	return fmt.Sprint("Kind(", int(k), ")")
+}
+
+// A Geometry describes the shape of a transform: what kind of transform it
+// is, its logical dimensions, its direction, the layout of its arrays and
+// whether it works in place.  Plans with equal geometries compute the same
+// thing, though possibly with different planner flags.  Geometries can't be
+// used as map keys directly, use Key, or Hash and Equal, instead.
+type Geometry struct {
+	Kind    Kind
+	Dims    []int
+	Dir     Direction
+	Layout  Layout
+	InPlace bool
+	// R2R holds the kind of each dimension of an R2R transform.
+	R2R []R2RKind
+	// Batch is the number of transforms a batched plan computes, or zero
+	// for a plan of a single transform.
+	Batch int
+	// In and Out are where the elements of a strided plan's transforms
+	// are, or zero for contiguous arrays.
+	In, Out Strides
+	// Axes lists the dimensions a plan transforms along, or is nil if it
+	// transforms along all of them.
+	Axes []int
+	// IO holds the transform dimensions followed by the loop dimensions of
+	// a guru plan, and is nil for other plans.
+	IO []IODim
+	// Split is true for plans on separate real and imaginary arrays.
+	Split bool
+}
+
+func (g Geometry) Equal(h Geometry) bool {
+	if g.Kind != h.Kind || g.Dir != h.Dir || g.Layout != h.Layout || g.InPlace != h.InPlace || g.Split != h.Split || g.Batch != h.Batch || g.In != h.In || g.Out != h.Out || len(g.Dims) != len(h.Dims) || len(g.R2R) != len(h.R2R) || len(g.Axes) != len(h.Axes) || len(g.IO) != len(h.IO) {
+		return false
+	}
+	for i := range g.Dims {
+		if g.Dims[i] != h.Dims[i] {
+			return false
+		}
+	}
+	for i := range g.R2R {
+		if g.R2R[i] != h.R2R[i] {
+			return false
+		}
+	}
+	for i := range g.Axes {
+		if g.Axes[i] != h.Axes[i] {
+			return false
+		}
+	}
+	for i := range g.IO {
+		if g.IO[i] != h.IO[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a hash of g such that g.Equal(h) implies g.Hash() == h.Hash().
+func (g Geometry) Hash() uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	put := func(v int) {
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		h.Write(b[:])
+	}
+	put(int(g.Kind))
+	put(int(g.Dir))
+	put(int(g.Layout))
+	if g.InPlace {
+		put(1)
+	} else {
+		put(0)
+	}
+	put(len(g.Dims))
+	for _, d := range g.Dims {
+		put(d)
+	}
+	put(len(g.R2R))
+	for _, k := range g.R2R {
+		put(int(k))
+	}
+	put(g.Batch)
+	put(g.In.Stride)
+	put(g.In.Dist)
+	put(g.Out.Stride)
+	put(g.Out.Dist)
+	put(len(g.Axes))
+	for _, a := range g.Axes {
+		put(a)
+	}
+	if g.Split {
+		put(1)
+	} else {
+		put(0)
+	}
+	put(len(g.IO))
+	for _, d := range g.IO {
+		put(d.N)
+		put(d.Is)
+		put(d.Os)
+	}
+	return h.Sum64()
+}
+
+// A GeometryKey is a comparable form of a Geometry, for use as a map key.
+type GeometryKey struct {
+	Kind    Kind
+	Dir     Direction
+	Layout  Layout
+	InPlace bool
+	Split   bool
+	Batch   int
+	In, Out Strides
+	// dims holds the dimensions, eight bytes apiece, followed by the r2r
+	// kinds, one byte apiece, axes holds the axes, one byte apiece, and io
+	// holds the guru dimensions, twenty four bytes apiece.
+	dims, axes, io string
+}
+
+// Key returns a map key for g, such that g.Key() == h.Key() exactly when
+// g.Equal(h).
+func (g Geometry) Key() GeometryKey {
+	b := make([]byte, 8*len(g.Dims), 8*len(g.Dims)+len(g.R2R))
+	for i, d := range g.Dims {
+		binary.LittleEndian.PutUint64(b[8*i:], uint64(d))
+	}
+	for _, k := range g.R2R {
+		b = append(b, byte(k))
+	}
+	axes := make([]byte, len(g.Axes))
+	for i, a := range g.Axes {
+		axes[i] = byte(a)
+	}
+	io := make([]byte, 24*len(g.IO))
+	for i, d := range g.IO {
+		binary.LittleEndian.PutUint64(io[24*i:], uint64(d.N))
+		binary.LittleEndian.PutUint64(io[24*i+8:], uint64(d.Is))
+		binary.LittleEndian.PutUint64(io[24*i+16:], uint64(d.Os))
+	}
+	return GeometryKey{g.Kind, g.Dir, g.Layout, g.InPlace, g.Split, g.Batch, g.In, g.Out, string(b), string(axes), string(io)}
+}
@@ -0,0 +1,62 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func GeometrySpec(c gospec.Context) {
+	a := Alloc2d(8, 4)
+	b := Alloc2d(8, 4)
+	d := Alloc2d(4, 8)
+	p1 := PlanDft2d(a, a, Forward, Estimate)
+	p2 := PlanDft2d(b, b, Forward, Estimate)
+	p3 := PlanDft2d(a, a, Backward, Estimate)
+	p4 := PlanDft2d(d, d, Forward, Estimate)
+	p5 := PlanDft2d(b, a, Forward, Estimate)
+
+	c.Specify("Plans know their geometry.", func() {
+		g := p1.Geometry()
+		c.Expect(g.Kind, gospec.Equals, C2C)
+		c.Expect(len(g.Dims), gospec.Equals, 2)
+		c.Expect(g.Dims[0], gospec.Equals, 8)
+		c.Expect(g.Dims[1], gospec.Equals, 4)
+		c.Expect(g.Dir, gospec.Equals, Forward)
+		c.Expect(g.InPlace, gospec.IsTrue)
+		c.Expect(p5.Geometry().InPlace, gospec.IsFalse)
+	})
+
+	c.Specify("Changing a returned geometry doesn't change the plan's.", func() {
+		p1.Geometry().Dims[0] = 1
+		c.Expect(p1.Geometry().Dims[0], gospec.Equals, 8)
+	})
+
+	c.Specify("Plans of the same shape have equal geometries and hashes.", func() {
+		c.Expect(p1.Geometry().Equal(p2.Geometry()), gospec.IsTrue)
+		c.Expect(p1.Geometry().Hash(), gospec.Equals, p2.Geometry().Hash())
+		c.Expect(p1.Geometry().Key() == p2.Geometry().Key(), gospec.IsTrue)
+	})
+
+	c.Specify("Geometry keys can index a map of plans.", func() {
+		cache := map[GeometryKey]*Plan{p1.Geometry().Key(): p1}
+		c.Expect(cache[p2.Geometry().Key()], gospec.Equals, p1)
+		c.Expect(cache[p5.Geometry().Key()] == nil, gospec.IsTrue)
+	})
+
+	c.Specify("Plans of different shapes have different geometries.", func() {
+		c.Expect(p1.Geometry().Equal(p3.Geometry()), gospec.IsFalse)
+		c.Expect(p1.Geometry().Equal(p4.Geometry()), gospec.IsFalse)
+		c.Expect(p1.Geometry().Hash() == p3.Geometry().Hash(), gospec.IsFalse)
+		c.Expect(p1.Geometry().Hash() == p4.Geometry().Hash(), gospec.IsFalse)
+		c.Expect(p1.Geometry().Key() == p3.Geometry().Key(), gospec.IsFalse)
+		c.Expect(p1.Geometry().Key() == p4.Geometry().Key(), gospec.IsFalse)
+		r := Geometry{Kind: R2C, Dims: []int{8, 4}, Dir: Forward, InPlace: true}
+		c.Expect(p1.Geometry().Equal(r), gospec.IsFalse)
+		c.Expect(p1.Geometry().Key() == r.Key(), gospec.IsFalse)
+	})
+
+	c.Specify("In-place and out-of-place plans have different geometries.", func() {
+		c.Expect(p1.Geometry().Equal(p5.Geometry()), gospec.IsFalse)
+		c.Expect(p1.Geometry().Hash() == p5.Geometry().Hash(), gospec.IsFalse)
+		c.Expect(p1.Geometry().Key() == p5.Geometry().Key(), gospec.IsFalse)
+	})
+}
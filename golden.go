@@ -0,0 +1,149 @@
+package fftw
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// A GoldenCase is the recorded output of one transform of a seeded random
+// input.
+type GoldenCase struct {
+	Name string
+	Kind Kind
+	N    int
+	Seed int64
+	// Hash is an FNV-1a hash of the bits of the output.
+	Hash uint64
+	// Output holds the output, real and imaginary parts interleaved, if it
+	// was kept, so that later outputs can be compared within a tolerance
+	// rather than only bit for bit.
+	Output []float64 `json:",omitempty"`
+}
+
+// A GoldenSet records transform outputs so that later runs, such as after
+// upgrading FFTW or importing different wisdom, can check that they still
+// get the same results.  Record one with RecordGolden, save it with
+// WriteTo, and check against it with ReadGoldenSet and Check.
+type GoldenSet struct {
+	Flag  Flag
+	Cases []GoldenCase
+}
+
+// A GoldenMismatch describes a case whose output is no longer bit for bit
+// the same as was recorded.
+type GoldenMismatch struct {
+	Name string
+	// MaxError is the largest difference from the recorded output, relative
+	// to the largest recorded magnitude, or NaN if the output wasn't kept.
+	MaxError float64
+	// WithinTolerance is set if MaxError is no more than the tolerance
+	// passed to Check.
+	WithinTolerance bool
+}
+
+// goldenOutput computes the output of c from its seeded input.  The input
+// is filled after planning, which may overwrite it unless flag is Estimate.
+func goldenOutput(c GoldenCase, flag Flag) []float64 {
+	rng := rand.New(rand.NewSource(c.Seed))
+	switch c.Kind {
+	case C2C:
+		in := make([]complex128, c.N)
+		out := make([]complex128, c.N)
+		p := PlanDft1d(in, out, Forward, flag)
+		for i := range in {
+			in[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+		}
+		p.Execute()
+		return append([]float64(nil), asReals(out)...)
+	case R2C:
+		in := make([]float64, c.N)
+		out := make([]complex128, c.N/2+1)
+		p := PlanDftR2C1d(in, out, flag)
+		for i := range in {
+			in[i] = rng.NormFloat64()
+		}
+		p.Execute()
+		return append([]float64(nil), asReals(out)...)
+	}
+	panic(fmt.Sprint("Golden outputs can't be recorded for ", c.Kind, " transforms"))
+}
+
+func goldenHash(out []float64) uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	for _, v := range out {
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		h.Write(b[:])
+	}
+	return h.Sum64()
+}
+
+// RecordGolden records the outputs of forward c2c and r2c transforms of
+// each of sizes, planned with flag, on random inputs drawn from seed.  If
+// keep is set the outputs themselves are kept as well as their hashes.
+func RecordGolden(sizes []int, seed int64, flag Flag, keep bool) *GoldenSet {
+	g := &GoldenSet{Flag: flag}
+	for i, n := range sizes {
+		for _, kind := range []Kind{C2C, R2C} {
+			c := GoldenCase{Name: fmt.Sprint(kind, "-", n), Kind: kind, N: n, Seed: seed + int64(i)}
+			out := goldenOutput(c, flag)
+			c.Hash = goldenHash(out)
+			if keep {
+				c.Output = out
+			}
+			g.Cases = append(g.Cases, c)
+		}
+	}
+	return g
+}
+
+// Check recomputes every case in g and returns those whose output has
+// changed at all.
+func (g *GoldenSet) Check(tolerance float64) []GoldenMismatch {
+	var mismatches []GoldenMismatch
+	for _, c := range g.Cases {
+		out := goldenOutput(c, g.Flag)
+		if goldenHash(out) == c.Hash {
+			continue
+		}
+		m := GoldenMismatch{Name: c.Name, MaxError: math.NaN()}
+		if len(c.Output) == len(out) {
+			scale, err := 0.0, 0.0
+			for i := range out {
+				scale = math.Max(scale, math.Abs(c.Output[i]))
+				err = math.Max(err, math.Abs(out[i]-c.Output[i]))
+			}
+			if scale > 0 {
+				err /= scale
+			}
+			m.MaxError = err
+			m.WithinTolerance = err <= tolerance
+		}
+		mismatches = append(mismatches, m)
+	}
+	return mismatches
+}
+
+// WriteTo writes g as JSON.
+func (g *GoldenSet) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadGoldenSet reads a GoldenSet written by WriteTo.
+func ReadGoldenSet(r io.Reader) (*GoldenSet, error) {
+	g := new(GoldenSet)
+	if err := json.NewDecoder(r).Decode(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
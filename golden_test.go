@@ -0,0 +1,60 @@
+package fftw
+
+import (
+	"bytes"
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+)
+
+func GoldenSpec(c gospec.Context) {
+	c.Specify("Recorded outputs check out after a round trip through JSON.", func() {
+		g := RecordGolden([]int{8, 15}, 42, Estimate, true)
+		c.Expect(len(g.Cases), gospec.Equals, 4)
+		var buf bytes.Buffer
+		_, err := g.WriteTo(&buf)
+		c.Expect(err, gospec.IsNil)
+		h, err := ReadGoldenSet(&buf)
+		c.Expect(err, gospec.IsNil)
+		c.Expect(len(h.Check(0)), gospec.Equals, 0)
+		c.Expect(h.Cases[1].Name, gospec.Equals, "r2c-8")
+	})
+
+	c.Specify("Changed outputs are reported with their error.", func() {
+		g := RecordGolden([]int{8}, 1, Estimate, true)
+		g.Cases[0].Output[3] += 1e-12
+		g.Cases[0].Hash++
+		g.Cases[1].Output[0] += 1
+		g.Cases[1].Hash++
+		m := g.Check(1e-9)
+		c.Expect(len(m), gospec.Equals, 2)
+		c.Expect(m[0].Name, gospec.Equals, "c2c-8")
+		c.Expect(m[0].WithinTolerance, gospec.IsTrue)
+		c.Expect(m[0].MaxError > 0, gospec.IsTrue)
+		c.Expect(m[1].WithinTolerance, gospec.IsFalse)
+
+		bare := RecordGolden([]int{8}, 1, Estimate, false)
+		bare.Cases[0].Hash++
+		m = bare.Check(1)
+		c.Expect(len(m), gospec.Equals, 1)
+		c.Expect(math.IsNaN(m[0].MaxError), gospec.IsTrue)
+		c.Expect(m[0].WithinTolerance, gospec.IsFalse)
+	})
+
+	c.Specify("Outputs recorded with Measure are of the seeded input.", func() {
+		g := RecordGolden([]int{8}, 7, Measure, true)
+		rng := rand.New(rand.NewSource(7))
+		in := make([]complex128, 8)
+		for i := range in {
+			in[i] = complex(rng.NormFloat64(), rng.NormFloat64())
+		}
+		out := make([]complex128, 8)
+		PlanDft1d(in, out, Forward, Estimate).Execute()
+		want := asReals(out)
+		c.Expect(g.Cases[0].Name, gospec.Equals, "c2c-8")
+		for i, v := range g.Cases[0].Output {
+			c.Expect(v, gospec.IsWithin(1e-9), want[i])
+		}
+		c.Expect(len(g.Check(0)), gospec.Equals, 0)
+	})
+}
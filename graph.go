@@ -0,0 +1,216 @@
+package fftw
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// A Frame is the block of data flowing through a Graph.  Its buffers belong
+// to the graph and are reused for every block, so nodes work on them in
+// place and sinks must copy anything they keep.
+type Frame struct {
+	// Index counts the blocks read from the source, from zero.
+	Index int
+	// Samples holds the block's samples, of which the first Valid came from
+	// the source and the rest are zero padding.
+	Samples []float64
+	Valid   int
+	// Spectrum holds the real spectrum of Samples once an FFT node has run.
+	Spectrum []complex128
+}
+
+// A Source fills blocks with samples, returning how many it wrote, which
+// is less than len(block) only for the last block and 0 once it is empty.
+type Source interface {
+	Read(block []float64) int
+}
+
+// A SizedSource is a Source that knows how many samples it holds, which
+// lets a Graph report the fraction of its run that is done.
+type SizedSource interface {
+	Source
+	Len() int
+}
+
+// A Node processes each frame in a Graph.
+type Node interface {
+	Process(f *Frame)
+}
+
+// A Sink receives each frame that has passed through a Graph.
+type Sink interface {
+	Consume(f *Frame)
+}
+
+// NodeFunc and SinkFunc adapt ordinary functions to Nodes and Sinks.
+type NodeFunc func(f *Frame)
+type SinkFunc func(f *Frame)
+
+func (n NodeFunc) Process(f *Frame) { n(f) }
+func (s SinkFunc) Consume(f *Frame) { s(f) }
+
+type sliceSource struct {
+	x   []float64
+	pos int
+}
+
+func (s *sliceSource) Read(block []float64) int {
+	n := copy(block, s.x[s.pos:])
+	s.pos += n
+	return n
+}
+
+func (s *sliceSource) Len() int {
+	return len(s.x)
+}
+
+// SliceSource returns a SizedSource that reads the samples of x.
+func SliceSource(x []float64) SizedSource {
+	return &sliceSource{x: x}
+}
+
+// A SampleCollector is a Sink that appends the valid samples of each frame
+// to Samples.
+type SampleCollector struct {
+	Samples []float64
+}
+
+func (s *SampleCollector) Consume(f *Frame) {
+	s.Samples = append(s.Samples, f.Samples[:f.Valid]...)
+}
+
+// A Graph runs blocks of a fixed size from a source through a chain of
+// nodes and into its sinks, reusing one set of buffers and plans for every
+// block, as in
+//
+//	NewGraph(512, SliceSource(x)).Window(Hann).FFT().Spectrum(f).IFFT().To(sink).Run()
+//
+// Graphs are not safe for concurrent use.
+type Graph struct {
+	source Source
+	nodes  []Node
+	sinks  []Sink
+	frame  Frame
+	// start is the number of blocks done before a restored checkpoint.
+	start           int
+	checkpointPath  string
+	checkpointEvery int
+}
+
+// NewGraph returns a graph that reads blocks of size samples from source.
+func NewGraph(size int, source Source) *Graph {
+	if size < 1 {
+		panic(fmt.Sprint("NewGraph needs a positive block size, got ", size))
+	}
+	g := &Graph{source: source}
+	g.frame.Samples = make([]float64, size)
+	g.frame.Spectrum = make([]complex128, size/2+1)
+	return g
+}
+
+// Then adds n to the end of g's chain of nodes.
+func (g *Graph) Then(n Node) *Graph {
+	g.nodes = append(g.nodes, n)
+	return g
+}
+
+// To adds s to g's sinks, which see each frame in the order they were added.
+func (g *Graph) To(s Sink) *Graph {
+	g.sinks = append(g.sinks, s)
+	return g
+}
+
+// Window adds a node that tapers each block's samples with w.
+func (g *Graph) Window(w Window) *Graph {
+	taper := Window1d(len(g.frame.Samples), w)
+	return g.Then(NodeFunc(func(f *Frame) {
+		for i := range f.Samples {
+			f.Samples[i] *= taper[i]
+		}
+	}))
+}
+
+// Samples adds a node that calls fn on each block's samples.
+func (g *Graph) Samples(fn func(x []float64)) *Graph {
+	return g.Then(NodeFunc(func(f *Frame) { fn(f.Samples) }))
+}
+
+// Spectrum adds a node that calls fn on each block's spectrum.
+func (g *Graph) Spectrum(fn func(s []complex128)) *Graph {
+	return g.Then(NodeFunc(func(f *Frame) { fn(f.Spectrum) }))
+}
+
+// FFT adds a node that computes the spectrum of each block's samples.
+func (g *Graph) FFT() *Graph {
+	p := PlanDftR2C1d(g.frame.Samples, g.frame.Spectrum, Estimate)
+	return g.Then(NodeFunc(func(f *Frame) { p.Execute() }))
+}
+
+// IFFT adds a node that replaces each block's samples with the normalized
+// inverse transform of its spectrum, which is left unchanged.
+func (g *Graph) IFFT() *Graph {
+	p := PlanDftC2R1d(g.frame.Spectrum, g.frame.Samples, Estimate|PreserveInput)
+	scale := 1 / float64(len(g.frame.Samples))
+	return g.Then(NodeFunc(func(f *Frame) {
+		p.Execute()
+		ScaleReal(f.Samples, scale)
+	}))
+}
+
+// Run reads blocks until the source is empty, passing each through the
+// nodes and on to the sinks, and returns the number of blocks.
+func (g *Graph) Run() int {
+	n, _ := g.RunContext(context.Background(), nil)
+	return n
+}
+
+// RunContext is Run for long jobs, reporting progress to progress, if it is
+// not nil, after each block.  If ctx is cancelled it stops before the next
+// block, returning the number of blocks done and ctx's error.  The count of
+// blocks includes any done before a restored checkpoint.
+func (g *Graph) RunContext(ctx context.Context, progress ProgressFunc) (int, error) {
+	total := 0
+	if s, ok := g.source.(SizedSource); ok {
+		size := len(g.frame.Samples)
+		total = (s.Len() + size - 1) / size
+	}
+	tracker := newProgressTracker(progress, total)
+	tracker.base = g.start
+	f := &g.frame
+	for f.Index = g.start; ; f.Index++ {
+		if err := ctx.Err(); err != nil {
+			if g.checkpointPath != "" {
+				if cerr := g.saveCheckpoint(f.Index); cerr != nil {
+					return f.Index, cerr
+				}
+			}
+			return f.Index, err
+		}
+		f.Valid = g.source.Read(f.Samples)
+		if f.Valid == 0 {
+			g.start = 0
+			if g.checkpointPath != "" {
+				if err := os.Remove(g.checkpointPath); err != nil && !os.IsNotExist(err) {
+					return f.Index, err
+				}
+			}
+			return f.Index, nil
+		}
+		for i := f.Valid; i < len(f.Samples); i++ {
+			f.Samples[i] = 0
+		}
+		for _, n := range g.nodes {
+			n.Process(f)
+		}
+		for _, s := range g.sinks {
+			s.Consume(f)
+		}
+		tracker.report(f.Index + 1)
+		if g.checkpointPath != "" && (f.Index+1)%g.checkpointEvery == 0 {
+			if err := g.saveCheckpoint(f.Index + 1); err != nil {
+				return f.Index + 1, err
+			}
+		}
+	}
+}
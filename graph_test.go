@@ -0,0 +1,89 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"testing"
+)
+
+// countingSource produces blocks of ones forever.
+type countingSource struct {
+	blocks, max int
+}
+
+func (s *countingSource) Read(block []float64) int {
+	if s.blocks == s.max {
+		return 0
+	}
+	s.blocks++
+	for i := range block {
+		block[i] = 1
+	}
+	return len(block)
+}
+
+func GraphSpec(c gospec.Context) {
+	x := make([]float64, 100)
+	for i := range x {
+		x[i] = math.Sin(float64(i)) + float64(i%5)
+	}
+
+	c.Specify("Blocks flow through the nodes into the sinks.", func() {
+		var out SampleCollector
+		var indices []int
+		n := NewGraph(32, SliceSource(x)).
+			FFT().
+			Spectrum(func(s []complex128) {
+				for k := 8; k < len(s); k++ {
+					s[k] = 0
+				}
+			}).
+			IFFT().
+			To(&out).
+			To(SinkFunc(func(f *Frame) { indices = append(indices, f.Index) })).
+			Run()
+		c.Expect(n, gospec.Equals, 4)
+		c.Expect(indices, gospec.ContainsInOrder, []int{0, 1, 2, 3})
+		c.Expect(len(out.Samples), gospec.Equals, len(x))
+
+		// Each block matches filtering it on its own.
+		for b := 0; b < 4; b++ {
+			block := make([]float64, 32)
+			copy(block, x[32*b:])
+			F := make([]complex128, 17)
+			PlanDftR2C1d(block, F, Estimate).Execute()
+			for k := 8; k < len(F); k++ {
+				F[k] = 0
+			}
+			PlanDftC2R1d(F, block, Estimate).Execute()
+			for i := 0; i < 32 && 32*b+i < len(x); i++ {
+				c.Expect(out.Samples[32*b+i], gospec.IsWithin(1e-9), block[i]/32)
+			}
+		}
+	})
+
+	c.Specify("Windows taper each block.", func() {
+		var out SampleCollector
+		NewGraph(8, &countingSource{max: 2}).Window(Hann).To(&out).Run()
+		taper := Window1d(8, Hann)
+		for i, v := range out.Samples {
+			c.Expect(v, gospec.Equals, taper[i%8])
+		}
+	})
+}
+
+// GraphAllocsSpec counts allocations, so it has a runner of its own, away
+// from other specs allocating concurrently.
+func GraphAllocsSpec(c gospec.Context) {
+	c.Specify("Running a graph doesn't allocate.", func() {
+		src := &countingSource{max: 10}
+		sum := 0.0
+		g := NewGraph(16, src).Window(Hann).FFT().IFFT().
+			Samples(func(x []float64) { sum += x[3] })
+		allocs := testing.AllocsPerRun(5, func() {
+			src.blocks = 0
+			g.Run()
+		})
+		c.Expect(allocs, gospec.Equals, 0.0)
+	})
+}
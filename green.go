@@ -0,0 +1,176 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A KernelFunc returns the Fourier transform of a Green's function at the
+// angular wavevector k, in radians per unit length along each dimension.
+type KernelFunc func(k []float64) complex128
+
+func squaredNorm(k []float64) float64 {
+	k2 := 0.0
+	for _, v := range k {
+		k2 += v * v
+	}
+	return k2
+}
+
+// PoissonKernel is the Green's function of the Laplacian, -1/|k|², so that
+// the solution u of ∇²u = f is the one with zero mean; the mean of f, which
+// has no periodic solution, is dropped.
+func PoissonKernel(k []float64) complex128 {
+	k2 := squaredNorm(k)
+	if k2 == 0 {
+		return 0
+	}
+	return complex(-1/k2, 0)
+}
+
+// HelmholtzKernel returns the Green's function of ∇² - kappa², the screened
+// Poisson or modified Helmholtz operator, -1/(|k|² + kappa²).  A zero kappa
+// gives PoissonKernel.
+func HelmholtzKernel(kappa float64) KernelFunc {
+	if kappa == 0 {
+		return PoissonKernel
+	}
+	return func(k []float64) complex128 {
+		return complex(-1/(squaredNorm(k)+kappa*kappa), 0)
+	}
+}
+
+// BiharmonicKernel is the Green's function of the biharmonic operator,
+// 1/|k|⁴, which like PoissonKernel drops the mean of f.
+func BiharmonicKernel(k []float64) complex128 {
+	k2 := squaredNorm(k)
+	if k2 == 0 {
+		return 0
+	}
+	return complex(1/(k2*k2), 0)
+}
+
+// A GreenSolver convolves periodic real fields of fixed dimensions with a
+// Green's function, solving the linear, constant coefficient problem it is
+// the Green's function of.  The transformed kernel is computed once, and
+// plans are cached for each batch size solved, so a solver is cheap to
+// reuse.  GreenSolvers are not safe for concurrent use.
+type GreenSolver struct {
+	dims       []int
+	size, half int
+	// kernel holds the transformed kernel over the half spectrum, including
+	// the normalization of the inverse transform.
+	kernel  []complex128
+	batches map[int]*greenBatch
+}
+
+// A greenBatch holds the buffers and plans for solving howmany fields at
+// once.
+type greenBatch struct {
+	fields   []float64
+	spectra  []complex128
+	forward  *Plan
+	backward *Plan
+}
+
+func newGreenSolver(name string, dims []int) *GreenSolver {
+	_, size, half := manyDims(name, dims, 1)
+	return &GreenSolver{dims: append([]int(nil), dims...), size: size, half: half, batches: make(map[int]*greenBatch)}
+}
+
+// NewGreenSolver returns a solver for fields of dimensions dims, sampled
+// spacing apart along each dimension, whose Green's function has the
+// Fourier transform kernel.
+func NewGreenSolver(dims []int, spacing []float64, kernel KernelFunc) *GreenSolver {
+	s := newGreenSolver("NewGreenSolver", dims)
+	if len(spacing) != len(dims) {
+		panic(fmt.Sprint("NewGreenSolver needs a spacing for each of dimensions ", dims, ", got ", spacing))
+	}
+	s.kernel = make([]complex128, s.half)
+	k := make([]float64, len(dims))
+	scale := complex(1/float64(s.size), 0)
+	forEachMode(dims, func(i int, m []int, weight int) {
+		for d, md := range m {
+			k[d] = 2 * math.Pi * float64(md) / (float64(dims[d]) * spacing[d])
+		}
+		s.kernel[i] = kernel(k) * scale
+	})
+	return s
+}
+
+// NewGreenSolverReal returns a solver for fields of dimensions dims whose
+// Green's function is the row-major periodic array g of the same
+// dimensions, with its origin at index 0, so that solving f gives the
+// circular convolution u[x] = Σ g[x-y] f[y].  Multiply g by the volume of
+// a cell to approximate a continuous convolution.
+func NewGreenSolverReal(dims []int, g []float64) *GreenSolver {
+	s := newGreenSolver("NewGreenSolverReal", dims)
+	if len(g) != s.size {
+		panic(fmt.Sprint("NewGreenSolverReal needs a kernel of length ", s.size, " for dimensions ", dims, ", got ", len(g)))
+	}
+	s.kernel = realSpectrum(dims, append([]float64(nil), g...))
+	Scale(s.kernel, 1/float64(s.size))
+	return s
+}
+
+// NewPoissonSolver returns a solver for ∇²u = f on fields of dimensions
+// dims sampled spacing apart.
+func NewPoissonSolver(dims []int, spacing []float64) *GreenSolver {
+	return NewGreenSolver(dims, spacing, PoissonKernel)
+}
+
+// NewHelmholtzSolver returns a solver for (∇² - kappa²)u = f on fields of
+// dimensions dims sampled spacing apart.
+func NewHelmholtzSolver(dims []int, spacing []float64, kappa float64) *GreenSolver {
+	return NewGreenSolver(dims, spacing, HelmholtzKernel(kappa))
+}
+
+// NewBiharmonicSolver returns a solver for ∇⁴u = f on fields of dimensions
+// dims sampled spacing apart.
+func NewBiharmonicSolver(dims []int, spacing []float64) *GreenSolver {
+	return NewGreenSolver(dims, spacing, BiharmonicKernel)
+}
+
+func (s *GreenSolver) batch(howmany int) *greenBatch {
+	if b, ok := s.batches[howmany]; ok {
+		return b
+	}
+	b := &greenBatch{
+		fields:  make([]float64, s.size*howmany),
+		spectra: make([]complex128, s.half*howmany),
+	}
+	b.forward = PlanManyDftR2C(s.dims, howmany, b.fields, b.spectra, Estimate)
+	b.backward = PlanManyDftC2R(s.dims, howmany, b.spectra, b.fields, Estimate)
+	s.batches[howmany] = b
+	return b
+}
+
+// Solve returns the convolution of the row-major field f with the solver's
+// Green's function.
+func (s *GreenSolver) Solve(f []float64) []float64 {
+	return s.SolveBatch([][]float64{f})[0]
+}
+
+// SolveBatch solves each of fs, transforming them all at once.
+func (s *GreenSolver) SolveBatch(fs [][]float64) [][]float64 {
+	if len(fs) == 0 {
+		return nil
+	}
+	b := s.batch(len(fs))
+	for i, f := range fs {
+		if len(f) != s.size {
+			panic(fmt.Sprint("Solving fields of dimensions ", s.dims, " needs arrays of length ", s.size, ", got ", len(f)))
+		}
+		copy(b.fields[i*s.size:], f)
+	}
+	b.forward.Execute()
+	for i := range b.spectra {
+		b.spectra[i] *= s.kernel[i%s.half]
+	}
+	b.backward.Execute()
+	us := make([][]float64, len(fs))
+	for i := range us {
+		us[i] = append([]float64(nil), b.fields[i*s.size:(i+1)*s.size]...)
+	}
+	return us
+}
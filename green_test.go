@@ -0,0 +1,107 @@
+package fftw
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func GreenSpec(c gospec.Context) {
+	c.Specify("The Poisson solver inverts the Laplacian.", func() {
+		dims := []int{16, 12}
+		h := []float64{0.5, 0.25}
+		a := 2 * math.Pi / (16 * 0.5)
+		b := 2 * 2 * math.Pi / (12 * 0.25)
+		f := make([]float64, 16*12)
+		want := make([]float64, len(f))
+		for i := 0; i < 16; i++ {
+			for j := 0; j < 12; j++ {
+				u := math.Sin(a*float64(i)*h[0]) * math.Cos(b*float64(j)*h[1])
+				want[i*12+j] = u
+				f[i*12+j] = -(a*a + b*b) * u
+			}
+		}
+		u := NewPoissonSolver(dims, h).Solve(f)
+		for i := range u {
+			c.Expect(u[i], gospec.IsWithin(1e-12), want[i])
+		}
+	})
+
+	c.Specify("The Helmholtz and biharmonic solvers invert their operators.", func() {
+		dims := []int{32}
+		h := []float64{0.1}
+		a := 3 * 2 * math.Pi / (32 * 0.1)
+		f := make([]float64, 32)
+		want := make([]float64, 32)
+		for i := range want {
+			want[i] = math.Cos(a * float64(i) * h[0])
+		}
+		kappa := 1.5
+		for i := range f {
+			f[i] = -(a*a + kappa*kappa) * want[i]
+		}
+		u := NewHelmholtzSolver(dims, h, kappa).Solve(f)
+		for i := range u {
+			c.Expect(u[i], gospec.IsWithin(1e-12), want[i])
+		}
+		for i := range f {
+			f[i] = a * a * a * a * want[i]
+		}
+		u = NewBiharmonicSolver(dims, h).Solve(f)
+		for i := range u {
+			c.Expect(u[i], gospec.IsWithin(1e-12), want[i])
+		}
+	})
+
+	c.Specify("Screening makes the mean solvable.", func() {
+		u := NewHelmholtzSolver([]int{4}, []float64{1}, 2).Solve([]float64{1, 1, 1, 1})
+		for _, v := range u {
+			c.Expect(v, gospec.IsWithin(1e-12), -0.25)
+		}
+	})
+
+	c.Specify("Real space kernels give circular convolutions.", func() {
+		rng := rand.New(rand.NewSource(2))
+		dims := []int{5, 4}
+		g := make([]float64, 20)
+		f := make([]float64, 20)
+		for i := range g {
+			g[i] = rng.NormFloat64()
+			f[i] = rng.NormFloat64()
+		}
+		u := NewGreenSolverReal(dims, g).Solve(f)
+		for x0 := 0; x0 < 5; x0++ {
+			for x1 := 0; x1 < 4; x1++ {
+				want := 0.0
+				for y0 := 0; y0 < 5; y0++ {
+					for y1 := 0; y1 < 4; y1++ {
+						want += g[mod(x0-y0, 5)*4+mod(x1-y1, 4)] * f[y0*4+y1]
+					}
+				}
+				c.Expect(u[x0*4+x1], gospec.IsWithin(1e-10), want)
+			}
+		}
+	})
+
+	c.Specify("Batches solve as single fields do.", func() {
+		rng := rand.New(rand.NewSource(3))
+		s := NewGreenSolver([]int{8}, []float64{1}, func(k []float64) complex128 {
+			return complex(math.Exp(-k[0]*k[0]), 0)
+		})
+		fs := make([][]float64, 3)
+		for i := range fs {
+			fs[i] = make([]float64, 8)
+			for j := range fs[i] {
+				fs[i][j] = rng.NormFloat64()
+			}
+		}
+		us := s.SolveBatch(fs)
+		for i, f := range fs {
+			u := s.Solve(f)
+			for j := range u {
+				c.Expect(us[i][j], gospec.IsWithin(1e-12), u[j])
+			}
+		}
+	})
+}
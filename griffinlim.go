@@ -0,0 +1,67 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// A GriffinLim reconstructs a signal from the magnitude of its STFT, by
+// alternately taking the inverse STFT of the current estimate and replacing
+// the magnitudes of that signal's STFT with the target ones.
+type GriffinLim struct {
+	s       *STFT
+	mag     [][]float64
+	length  int
+	spectra [][]complex128
+}
+
+// GriffinLim returns an iterator that reconstructs the length sample signal
+// whose STFT has the magnitudes mag, starting from zero phase.
+func (s *STFT) GriffinLim(mag [][]float64, length int) *GriffinLim {
+	if len(mag) != s.frames(length) {
+		panic(fmt.Sprint("GriffinLim needs ", s.frames(length), " frames for ", length, " samples, got ", len(mag)))
+	}
+	g := &GriffinLim{s: s, mag: mag, length: length}
+	g.spectra = make([][]complex128, len(mag))
+	for t := range mag {
+		if len(mag[t]) != s.n/2+1 {
+			panic(fmt.Sprint("GriffinLim needs ", s.n/2+1, " bins per frame, got ", len(mag[t])))
+		}
+		g.spectra[t] = make([]complex128, len(mag[t]))
+		for k, m := range mag[t] {
+			g.spectra[t][k] = complex(m, 0)
+		}
+	}
+	return g
+}
+
+// Step runs one iteration and returns the spectral convergence of the
+// estimate it started from, the relative distance between the magnitudes
+// of that signal's STFT and the target ones.
+func (g *GriffinLim) Step() float64 {
+	spectra := g.s.Transform(g.Signal())
+	var diff, norm float64
+	for t := range spectra {
+		for k, v := range spectra[t] {
+			m := g.mag[t][k]
+			a := cmplx.Abs(v)
+			diff += (a - m) * (a - m)
+			norm += m * m
+			if a > 0 {
+				g.spectra[t][k] = v * complex(m/a, 0)
+			} else {
+				g.spectra[t][k] = complex(m, 0)
+			}
+		}
+	}
+	if norm == 0 {
+		return 0
+	}
+	return math.Sqrt(diff / norm)
+}
+
+// Signal returns the current estimate of the signal.
+func (g *GriffinLim) Signal() []float64 {
+	return g.s.Inverse(g.spectra, g.length)
+}
@@ -0,0 +1,35 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func GriffinLimSpec(c gospec.Context) {
+	x := make([]float64, 1024)
+	for i := range x {
+		f := 0.02 + 0.1*float64(i)/float64(len(x))
+		x[i] = math.Sin(2 * math.Pi * f * float64(i))
+	}
+	s := NewSTFT(128, 32, Hann)
+	spectra := s.Transform(x)
+	mag := make([][]float64, len(spectra))
+	for t := range spectra {
+		mag[t] = make([]float64, len(spectra[t]))
+		for k, v := range spectra[t] {
+			mag[t][k] = cmplx.Abs(v)
+		}
+	}
+
+	c.Specify("Griffin-Lim converges towards a consistent spectrogram.", func() {
+		g := s.GriffinLim(mag, len(x))
+		first := g.Step()
+		last := first
+		for i := 0; i < 25; i++ {
+			last = g.Step()
+		}
+		c.Expect(last < first/2, gospec.IsTrue)
+		c.Expect(len(g.Signal()), gospec.Equals, len(x))
+	})
+}
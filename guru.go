@@ -0,0 +1,134 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// An IODim describes one dimension of a guru plan: N elements, Is elements
+// apart in the input array and Os elements apart in the output array.
+// Strides count elements of the array's own type, so for the real side of
+// an r2c or c2r transform they count float64s.  Guru plans are made with
+// fftw's 64 bit interface, so sizes and strides may exceed 2^31.
+type IODim struct {
+	N, Is, Os int
+}
+
+// guruPlan holds the dimensions of a guru plan in fftw's form.
+type guruPlan struct {
+	dims, loops []C.fftw_iodim64
+	geom        Geometry
+}
+
+// newGuruPlan checks the dimensions and loops of a guru plan against
+// arrays of inLen and outLen elements, where the last transform dimension
+// has inLast and outLast elements in the arrays, and converts them for fftw.
+func newGuruPlan(name string, kind Kind, dims, howmany []IODim, inLen, outLen int, inLast, outLast func(n int) int) guruPlan {
+	if len(dims) == 0 {
+		panic(fmt.Sprint(name, " needs at least one transform dimension"))
+	}
+	var g guruPlan
+	g.geom = Geometry{Kind: kind, IO: append(append([]IODim(nil), dims...), howmany...)}
+	inEnd, outEnd := 0, 0
+	for i, d := range g.geom.IO {
+		if d.N < 1 || d.Is < 0 || d.Os < 0 {
+			panic(fmt.Sprint(name, " needs positive sizes and non-negative strides, got ", d))
+		}
+		in, out := d.N, d.N
+		if i == len(dims)-1 {
+			in, out = inLast(d.N), outLast(d.N)
+		}
+		inEnd += (in - 1) * d.Is
+		outEnd += (out - 1) * d.Os
+		c := C.fftw_iodim64{n: C.ptrdiff_t(d.N), is: C.ptrdiff_t(d.Is), os: C.ptrdiff_t(d.Os)}
+		if i < len(dims) {
+			g.dims = append(g.dims, c)
+			g.geom.Dims = append(g.geom.Dims, d.N)
+		} else {
+			g.loops = append(g.loops, c)
+			if g.geom.Batch == 0 {
+				g.geom.Batch = 1
+			}
+			g.geom.Batch *= d.N
+		}
+	}
+	if inEnd >= inLen || outEnd >= outLen {
+		panic(fmt.Sprint(name, " reaches elements ", inEnd, " and ", outEnd, " of arrays of lengths ", inLen, " and ", outLen))
+	}
+	// fftw wants a valid pointer even for an empty loop.
+	g.loops = append(g.loops, C.fftw_iodim64{})
+	return g
+}
+
+func fullLen(n int) int { return n }
+func halfLen(n int) int { return n/2 + 1 }
+
+// PlanGuruDft plans complex transforms with fftw's guru interface, which
+// describes the transform and the loops around it as lists of dimensions,
+// each with its own strides, so that plans can run over any nested loops,
+// strides and batches of the arrays in and out.  With dims
+// []IODim{{N: 4, Is: 1, Os: 1}} and howmany []IODim{{N: 3, Is: 4, Os: 4}},
+// for example, it plans three length 4 transforms, one after another.
+func PlanGuruDft(dims, howmany []IODim, in, out []complex128, dir Direction, flag Flag) *Plan {
+	g := newGuruPlan("PlanGuruDft", C2C, dims, howmany, len(in), len(out), fullLen, fullLen)
+	g.geom.Dir = dir
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_dft(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, g.geom, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanGuruDftR2C plans real-to-complex transforms with the guru interface.
+// The last transform dimension's N is the real length, and the output has
+// N/2+1 elements along it.
+func PlanGuruDftR2C(dims, howmany []IODim, in []float64, out []complex128, flag Flag) *Plan {
+	g := newGuruPlan("PlanGuruDftR2C", R2C, dims, howmany, len(in), len(out), fullLen, halfLen)
+	g.geom.Dir = Forward
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_dft_r2c(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, g.geom, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanGuruDftC2R plans complex-to-real transforms with the guru interface,
+// the inverse of PlanGuruDftR2C.  Like every complex-to-real transform it
+// overwrites its input unless flag includes PreserveInput, which fftw only
+// supports for one dimensional transforms.
+func PlanGuruDftC2R(dims, howmany []IODim, in []complex128, out []float64, flag Flag) *Plan {
+	g := newGuruPlan("PlanGuruDftC2R", C2R, dims, howmany, len(in), len(out), halfLen, fullLen)
+	g.geom.Dir = Backward
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_dft_c2r(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_in, fftw_out, planFlags(flag))
+	planner.Unlock()
+	if p == nil {
+		panic(fmt.Sprint("fftw could not plan complex-to-real transforms of dimensions ", dims, " with flags ", flag))
+	}
+	return newPlan(p, g.geom, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanGuruR2R plans real-to-real transforms with the guru interface, with
+// one kind per transform dimension.
+func PlanGuruR2R(dims, howmany []IODim, in, out []float64, kinds []R2RKind, flag Flag) *Plan {
+	g := newGuruPlan("PlanGuruR2R", R2R, dims, howmany, len(in), len(out), fullLen, fullLen)
+	checkR2RDims(g.geom.Dims, kinds)
+	g.geom.R2R = append([]R2RKind(nil), kinds...)
+	k := make([]C.fftw_r2r_kind, len(kinds))
+	for i, kind := range kinds {
+		k[i] = C.fftw_r2r_kind(kind)
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_r2r(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_in, fftw_out, &k[0], planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, g.geom, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
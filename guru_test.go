@@ -0,0 +1,69 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func GuruSpec(c gospec.Context) {
+	c.Specify("Guru plans can transform the columns of a matrix.", func() {
+		// A 4x3 row-major matrix, transformed down each of its 3 columns.
+		in := make([]complex128, 12)
+		for i := range in {
+			in[i] = complex(float64(i*5%7), float64(i%2))
+		}
+		out := make([]complex128, 12)
+		p := PlanGuruDft([]IODim{{N: 4, Is: 3, Os: 3}}, []IODim{{N: 3, Is: 1, Os: 1}}, in, out, Forward, Estimate)
+		p.Execute()
+		for j := 0; j < 3; j++ {
+			col := []complex128{in[j], in[3+j], in[6+j], in[9+j]}
+			want := make([]complex128, 4)
+			PlanDft1d(col, want, Forward, Estimate).Execute()
+			for i := range want {
+				c.Expect(cmplx.Abs(out[3*i+j]-want[i]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		g := p.Geometry()
+		c.Expect(g.Dims, gospec.ContainsExactly, []int{4})
+		c.Expect(g.Batch, gospec.Equals, 3)
+		c.Expect(len(g.IO), gospec.Equals, 2)
+	})
+
+	c.Specify("Guru real transforms can transpose their output.", func() {
+		// Two rows of 6 samples, with the spectra written column by column.
+		in := make([]float64, 12)
+		for i := range in {
+			in[i] = float64(i*i%5) - 2
+		}
+		spectra := make([]complex128, 8)
+		PlanGuruDftR2C([]IODim{{N: 6, Is: 1, Os: 2}}, []IODim{{N: 2, Is: 6, Os: 1}}, in, spectra, Estimate).Execute()
+		for r := 0; r < 2; r++ {
+			want := make([]complex128, 4)
+			PlanDftR2C1d(append([]float64(nil), in[6*r:6*r+6]...), want, Estimate).Execute()
+			for k := range want {
+				c.Expect(cmplx.Abs(spectra[2*k+r]-want[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		back := make([]float64, 12)
+		PlanGuruDftC2R([]IODim{{N: 6, Is: 2, Os: 1}}, []IODim{{N: 2, Is: 1, Os: 6}}, spectra, back, Estimate).Execute()
+		for i := range in {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-9), in[i])
+		}
+	})
+
+	c.Specify("Guru real-to-real plans match the basic interface.", func() {
+		in := []float64{1, 4, -2, 3, 0, 5}
+		out := make([]float64, 6)
+		PlanGuruR2R([]IODim{{N: 6, Is: 1, Os: 1}}, nil, in, out, []R2RKind{REDFT10}, Estimate).Execute()
+		want := make([]float64, 6)
+		R2R1d(in, want, REDFT10, Estimate)
+		for i := range want {
+			c.Expect(out[i], gospec.IsWithin(1e-9), want[i])
+		}
+	})
+
+	c.Specify("Dimensions that run off the end of an array are refused.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanGuruDft([]IODim{{N: 4, Is: 2, Os: 1}}, nil, make([]complex128, 4), make([]complex128, 4), Forward, Estimate)
+	})
+}
@@ -0,0 +1,49 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// HalfComplexToComplex unpacks the output of an R2HC transform of n reals,
+// which holds the real parts r[0], ..., r[n/2] followed by the imaginary
+// parts i[(n+1)/2-1], ..., i[1] in reverse order, into the n/2+1 bins of a
+// real-to-complex transform.  R2HC works in place, PlanR2R1d(x, x, R2HC,
+// flag), so it needs no second array the way PlanDftR2C1d does.
+func HalfComplexToComplex(hc []float64) []complex128 {
+	n := len(hc)
+	out := make([]complex128, n/2+1)
+	if n == 0 {
+		return out[:0]
+	}
+	out[0] = complex(hc[0], 0)
+	for k := 1; k < (n+1)/2; k++ {
+		out[k] = complex(hc[k], hc[n-k])
+	}
+	if n%2 == 0 {
+		out[n/2] = complex(hc[n/2], 0)
+	}
+	return out
+}
+
+// ComplexToHalfComplex packs the n/2+1 bins of the spectrum of n reals into
+// the halfcomplex layout HC2R takes.  The imaginary parts of the DC bin, and
+// of the Nyquist bin when n is even, are dropped, as they are zero for the
+// spectrum of a real signal.
+func ComplexToHalfComplex(c []complex128, n int) []float64 {
+	if len(c) != n/2+1 {
+		panic(fmt.Sprint("The spectrum of ", n, " reals has ", n/2+1, " bins, got ", len(c)))
+	}
+	hc := make([]float64, n)
+	if n == 0 {
+		return hc
+	}
+	hc[0] = real(c[0])
+	for k := 1; k < (n+1)/2; k++ {
+		hc[k] = real(c[k])
+		hc[n-k] = imag(c[k])
+	}
+	if n%2 == 0 {
+		hc[n/2] = real(c[n/2])
+	}
+	return hc
+}
@@ -0,0 +1,41 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func HalfComplexSpec(c gospec.Context) {
+	c.Specify("In-place R2HC output unpacks to the R2C spectrum.", func() {
+		for _, n := range []int{1, 2, 7, 8} {
+			x := make([]float64, n)
+			for i := range x {
+				x[i] = float64(i*i%7) - 2
+			}
+			F := make([]complex128, n/2+1)
+			PlanDftR2C1d(append([]float64(nil), x...), F, Estimate).Execute()
+
+			hc := append([]float64(nil), x...)
+			R2R1d(hc, hc, R2HC, Estimate)
+			got := HalfComplexToComplex(hc)
+			c.Expect(len(got), gospec.Equals, len(F))
+			for k := range F {
+				c.Expect(real(got[k]), gospec.IsWithin(1e-9), real(F[k]))
+				c.Expect(imag(got[k]), gospec.IsWithin(1e-9), imag(F[k]))
+			}
+
+			packed := ComplexToHalfComplex(F, n)
+			for i := range packed {
+				c.Expect(packed[i], gospec.IsWithin(1e-9), hc[i])
+			}
+			R2R1d(packed, packed, HC2R, Estimate)
+			for i := range x {
+				c.Expect(packed[i]/float64(n), gospec.IsWithin(1e-9), x[i])
+			}
+		}
+	})
+
+	c.Specify("Empty spectra convert to empty arrays.", func() {
+		c.Expect(len(HalfComplexToComplex(nil)), gospec.Equals, 0)
+		c.Expect(len(ComplexToHalfComplex(make([]complex128, 1), 0)), gospec.Equals, 0)
+	})
+}
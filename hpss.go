@@ -0,0 +1,72 @@
+package fftw
+
+import (
+	"fmt"
+	"math/cmplx"
+	"sort"
+)
+
+// HPSS separates x into its harmonic and percussive parts by median
+// filtering the magnitude of its STFT.  Harmonic sounds are steady in time,
+// so they survive a median of width harmonic along each bin; percussive
+// sounds are broadband, so they survive a median of width percussive along
+// each frame.  The two filtered magnitudes become soft masks, which sum to
+// one, so harmonic and percussive add back up to x.
+func (s *STFT) HPSS(x []float64, harmonic, percussive int) ([]float64, []float64) {
+	if harmonic < 1 || percussive < 1 {
+		panic(fmt.Sprint("HPSS needs positive filter lengths, got ", harmonic, " and ", percussive))
+	}
+	spectra := s.Transform(x)
+	frames := len(spectra)
+	bins := s.n/2 + 1
+	mag := make([][]float64, frames)
+	for t := range mag {
+		mag[t] = make([]float64, bins)
+		for k, v := range spectra[t] {
+			mag[t][k] = cmplx.Abs(v)
+		}
+	}
+
+	h := make([][]complex128, frames)
+	p := make([][]complex128, frames)
+	window := make([]float64, 0, harmonic+percussive)
+	for t := range spectra {
+		h[t] = make([]complex128, bins)
+		p[t] = make([]complex128, bins)
+		for k, v := range spectra[t] {
+			window = window[:0]
+			for i := t - harmonic/2; i <= t+harmonic/2; i++ {
+				if i >= 0 && i < frames {
+					window = append(window, mag[i][k])
+				}
+			}
+			mh := median(window)
+			window = window[:0]
+			for i := k - percussive/2; i <= k+percussive/2; i++ {
+				if i >= 0 && i < bins {
+					window = append(window, mag[t][i])
+				}
+			}
+			mp := median(window)
+			// Wiener-like masks on the filtered power.
+			mh, mp = mh*mh, mp*mp
+			m := 0.5
+			if mh+mp > 0 {
+				m = mh / (mh + mp)
+			}
+			h[t][k] = v * complex(m, 0)
+			p[t][k] = v * complex(1-m, 0)
+		}
+	}
+	return s.Inverse(h, len(x)), s.Inverse(p, len(x))
+}
+
+// median returns the median of v, reordering v in the process.
+func median(v []float64) float64 {
+	sort.Float64s(v)
+	n := len(v)
+	if n%2 == 1 {
+		return v[n/2]
+	}
+	return (v[n/2-1] + v[n/2]) / 2
+}
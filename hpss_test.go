@@ -0,0 +1,39 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func HPSSSpec(c gospec.Context) {
+	x := make([]float64, 2048)
+	tone := make([]float64, len(x))
+	clicks := make([]float64, len(x))
+	for i := range x {
+		tone[i] = math.Sin(2 * math.Pi * 0.05 * float64(i))
+		if i%256 == 128 {
+			clicks[i] = 8
+		}
+		x[i] = tone[i] + clicks[i]
+	}
+	s := NewSTFT(128, 32, Hann)
+	h, p := s.HPSS(x, 17, 17)
+
+	c.Specify("The harmonic and percussive parts add up to the signal.", func() {
+		for i := range x {
+			c.Expect(h[i]+p[i], gospec.IsWithin(1e-9), x[i])
+		}
+	})
+
+	c.Specify("Tones go to the harmonic part and clicks to the percussive part.", func() {
+		var eh, ep, et, ec float64
+		for i := range x {
+			eh += (h[i] - tone[i]) * (h[i] - tone[i])
+			ep += (p[i] - clicks[i]) * (p[i] - clicks[i])
+			et += tone[i] * tone[i]
+			ec += clicks[i] * clicks[i]
+		}
+		c.Expect(eh/et < 0.1, gospec.IsTrue)
+		c.Expect(ep/ec < 0.2, gospec.IsTrue)
+	})
+}
@@ -0,0 +1,192 @@
+package fftw
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ColorSpace selects the planes an image is split into for filtering.
+type ColorSpace int
+
+const (
+	// RGB splits an image into its red, green and blue levels, from 0 to 1.
+	RGB ColorSpace = iota
+	// YCbCr splits an image into luma, from 0 to 1, and blue and red
+	// difference chroma, from -0.5 to 0.5, with the full range BT.601
+	// coefficients JPEG uses.
+	YCbCr
+	// Lab splits an image into CIE L*, from 0 to 100, and a* and b*, taking
+	// its colours to be sRGB with a D65 white point.
+	Lab
+)
+
+// ImagePlanes holds an image split into three planes of levels in a colour
+// space, plus its alpha, so that each plane can be filtered in the
+// frequency domain separately.  Filtering only the luma of YCbCr or the L*
+// of Lab sharpens or smooths an image without shifting its colours, which
+// is what most image filters want.
+type ImagePlanes struct {
+	Space  ColorSpace
+	Planes [3][][]float64
+	Alpha  [][]float64
+	rect   image.Rectangle
+}
+
+// NewImagePlanes splits img into planes in the colour space space.
+func NewImagePlanes(img image.Image, space ColorSpace) *ImagePlanes {
+	b := img.Bounds()
+	p := &ImagePlanes{Space: space, rect: b}
+	h, w := b.Dy(), b.Dx()
+	for i := range p.Planes {
+		p.Planes[i] = alloc2dReal(h, w)
+	}
+	p.Alpha = alloc2dReal(h, w)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA64Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA64)
+			rgb := [3]float64{float64(c.R) / 0xffff, float64(c.G) / 0xffff, float64(c.B) / 0xffff}
+			v := fromRGB(space, rgb)
+			for i := range v {
+				p.Planes[i][y][x] = v[i]
+			}
+			p.Alpha[y][x] = float64(c.A) / 0xffff
+		}
+	}
+	return p
+}
+
+// Filter transforms the plane with index plane, calls fn on its spectrum,
+// which is laid out as PlanDft2d lays it out and which fn may change, and
+// replaces the plane with the real part of the inverse transform.
+func (p *ImagePlanes) Filter(plane int, fn func(spectrum [][]complex128)) {
+	if plane < 0 || plane >= len(p.Planes) {
+		panic(fmt.Sprint("Image planes are numbered 0 to 2, got ", plane))
+	}
+	levels := p.Planes[plane]
+	h, w := p.rect.Dy(), p.rect.Dx()
+	if h == 0 || w == 0 {
+		return
+	}
+	a := Alloc2d(h, w)
+	defer Free2d(a)
+	for y := range levels {
+		for x, v := range levels[y] {
+			a[y][x] = complex(v, 0)
+		}
+	}
+	PlanDft2d(a, a, Forward, Estimate).Execute()
+	fn(a)
+	PlanDft2d(a, a, Backward, Estimate).Execute()
+	scale := 1 / float64(h*w)
+	for y := range levels {
+		for x := range levels[y] {
+			levels[y][x] = real(a[y][x]) * scale
+		}
+	}
+}
+
+// Image joins the planes back into an image, clamping colours that
+// filtering has taken out of the RGB gamut.
+func (p *ImagePlanes) Image() *image.NRGBA64 {
+	img := image.NewNRGBA64(p.rect)
+	b := p.rect
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			rgb := toRGB(p.Space, [3]float64{p.Planes[0][y][x], p.Planes[1][y][x], p.Planes[2][y][x]})
+			q := func(v float64) uint16 {
+				return uint16(math.Floor(clamp01(v)*0xffff + 0.5))
+			}
+			img.SetNRGBA64(b.Min.X+x, b.Min.Y+y, color.NRGBA64{q(rgb[0]), q(rgb[1]), q(rgb[2]), q(p.Alpha[y][x])})
+		}
+	}
+	return img
+}
+
+// FilterLuma filters the luma of img with fn, as ImagePlanes.Filter does,
+// leaving its chroma alone.
+func FilterLuma(img image.Image, fn func(spectrum [][]complex128)) *image.NRGBA64 {
+	p := NewImagePlanes(img, YCbCr)
+	p.Filter(0, fn)
+	return p.Image()
+}
+
+// The D65 white point, in XYZ.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+func fromRGB(space ColorSpace, c [3]float64) [3]float64 {
+	r, g, b := c[0], c[1], c[2]
+	switch space {
+	case RGB:
+		return c
+	case YCbCr:
+		return [3]float64{
+			0.299*r + 0.587*g + 0.114*b,
+			-0.168736*r - 0.331264*g + 0.5*b,
+			0.5*r - 0.418688*g - 0.081312*b,
+		}
+	case Lab:
+		r, g, b = srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+		x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / whiteX
+		y := (0.2126729*r + 0.7151522*g + 0.0721750*b) / whiteY
+		z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / whiteZ
+		fx, fy, fz := labF(x), labF(y), labF(z)
+		return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+	}
+	panic(fmt.Sprint("Unknown ColorSpace ", int(space)))
+}
+
+func toRGB(space ColorSpace, c [3]float64) [3]float64 {
+	switch space {
+	case RGB:
+		return c
+	case YCbCr:
+		y, cb, cr := c[0], c[1], c[2]
+		return [3]float64{y + 1.402*cr, y - 0.344136*cb - 0.714136*cr, y + 1.772*cb}
+	case Lab:
+		fy := (c[0] + 16) / 116
+		fx := fy + c[1]/500
+		fz := fy - c[2]/200
+		x, y, z := labFInv(fx)*whiteX, labFInv(fy)*whiteY, labFInv(fz)*whiteZ
+		r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+		g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+		b := 0.0556434*x - 0.2040259*y + 1.0572252*z
+		return [3]float64{linearToSRGB(r), linearToSRGB(g), linearToSRGB(b)}
+	}
+	panic(fmt.Sprint("Unknown ColorSpace ", int(space)))
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+func labF(t float64) float64 {
+	const d = 6.0 / 29
+	if t > d*d*d {
+		return math.Cbrt(t)
+	}
+	return t/(3*d*d) + 4.0/29
+}
+
+func labFInv(t float64) float64 {
+	const d = 6.0 / 29
+	if t > d {
+		return t * t * t
+	}
+	return 3 * d * d * (t - 4.0/29)
+}
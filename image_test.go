@@ -0,0 +1,91 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"image"
+	"image/color"
+	"math"
+)
+
+func ImagePlanesSpec(c gospec.Context) {
+	img := image.NewNRGBA(image.Rect(2, 3, 10, 9))
+	for y := 3; y < 9; y++ {
+		for x := 2; x < 10; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 29), uint8(y * 41), uint8(x * y * 7), uint8(255 - x)})
+		}
+	}
+	same := func(a, b image.Image, tol uint32) {
+		c.Expect(a.Bounds(), gospec.Equals, b.Bounds())
+		for y := a.Bounds().Min.Y; y < a.Bounds().Max.Y; y++ {
+			for x := a.Bounds().Min.X; x < a.Bounds().Max.X; x++ {
+				p := color.NRGBA64Model.Convert(a.At(x, y)).(color.NRGBA64)
+				q := color.NRGBA64Model.Convert(b.At(x, y)).(color.NRGBA64)
+				for _, d := range [][2]uint16{{p.R, q.R}, {p.G, q.G}, {p.B, q.B}, {p.A, q.A}} {
+					diff := int(d[0]) - int(d[1])
+					if diff < 0 {
+						diff = -diff
+					}
+					c.Expect(uint32(diff) <= tol, gospec.IsTrue)
+				}
+			}
+		}
+	}
+
+	c.Specify("Images survive a round trip through each colour space.", func() {
+		for _, space := range []ColorSpace{RGB, YCbCr, Lab} {
+			same(NewImagePlanes(img, space).Image(), img, 0x10)
+		}
+	})
+
+	c.Specify("Levels are in the ranges of each colour space.", func() {
+		white := image.NewGray(image.Rect(0, 0, 1, 1))
+		white.SetGray(0, 0, color.Gray{255})
+		p := NewImagePlanes(white, YCbCr)
+		c.Expect(p.Planes[0][0][0], gospec.IsWithin(1e-9), 1.0)
+		c.Expect(p.Planes[1][0][0], gospec.IsWithin(1e-6), 0.0)
+		p = NewImagePlanes(white, Lab)
+		c.Expect(p.Planes[0][0][0], gospec.IsWithin(1e-3), 100.0)
+		c.Expect(p.Planes[1][0][0], gospec.IsWithin(1e-3), 0.0)
+		c.Expect(p.Planes[2][0][0], gospec.IsWithin(1e-3), 0.0)
+	})
+
+	c.Specify("An identity filter leaves an image unchanged.", func() {
+		p := NewImagePlanes(img, Lab)
+		p.Filter(0, func([][]complex128) {})
+		same(p.Image(), img, 0x10)
+	})
+
+	c.Specify("Filtering luma leaves chroma alone.", func() {
+		// Keep only the mean luma.
+		out := FilterLuma(img, func(s [][]complex128) {
+			for y := range s {
+				for x := range s[y] {
+					if x != 0 || y != 0 {
+						s[y][x] = 0
+					}
+				}
+			}
+		})
+		before := NewImagePlanes(img, YCbCr)
+		after := NewImagePlanes(out, YCbCr)
+		mean := 0.0
+		for y := range before.Planes[0] {
+			for _, v := range before.Planes[0][y] {
+				mean += v
+			}
+		}
+		mean /= 48
+		for y := range after.Planes[0] {
+			for x, v := range after.Planes[0][y] {
+				rgb := toRGB(YCbCr, [3]float64{mean, before.Planes[1][y][x], before.Planes[2][y][x]})
+				if math.Max(rgb[0], math.Max(rgb[1], rgb[2])) > 1 || math.Min(rgb[0], math.Min(rgb[1], rgb[2])) < 0 {
+					// Clamped out of gamut.
+					continue
+				}
+				c.Expect(v, gospec.IsWithin(1e-3), mean)
+				c.Expect(after.Planes[1][y][x], gospec.IsWithin(1e-3), before.Planes[1][y][x])
+				c.Expect(after.Planes[2][y][x], gospec.IsWithin(1e-3), before.Planes[2][y][x])
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// PlanDftInPlace1d plans a transform that overwrites data with its
+// transform, so that only one copy of the data is ever needed.  fftw does
+// complex transforms in place natively; this is the same as passing data as
+// both the input and output of PlanDft1d.
+func PlanDftInPlace1d(data []complex128, dir Direction, flag Flag) *Plan {
+	return PlanDft1d(data, data, dir, flag)
+}
+
+// PlanDftInPlace2d is the two dimensional PlanDftInPlace1d, for data from
+// Alloc2d.
+func PlanDftInPlace2d(data [][]complex128, dir Direction, flag Flag) *Plan {
+	return PlanDft2d(data, data, dir, flag)
+}
+
+// PlanDftInPlace3d is the three dimensional PlanDftInPlace1d, for data from
+// Alloc3d.
+func PlanDftInPlace3d(data [][][]complex128, dir Direction, flag Flag) *Plan {
+	return PlanDft3d(data, data, dir, flag)
+}
+
+// RealView returns the first n reals stored in data, which is where the
+// input of an in-place real-to-complex transform of n reals goes, and where
+// the output of the complex-to-real transform comes out.
+func RealView(data []complex128, n int) []float64 {
+	if len(data) != n/2+1 {
+		panic(fmt.Sprint("In-place real transforms of ", n, " reals need ", n/2+1, " complex elements, got ", len(data)))
+	}
+	return asReals(data)[:n]
+}
+
+// PlanDftR2CInPlace1d plans a real-to-complex transform of the n reals in
+// RealView(data, n) that overwrites them with their n/2+1 bin spectrum in
+// data.  The spectrum takes one or two reals more than the input, which is
+// the padding data has beyond the input.
+func PlanDftR2CInPlace1d(data []complex128, n int, flag Flag) *Plan {
+	return PlanDftR2C1d(RealView(data, n), data, flag)
+}
+
+// PlanDftC2RInPlace1d plans the inverse of PlanDftR2CInPlace1d, leaving the
+// n reals in RealView(data, n).
+func PlanDftC2RInPlace1d(data []complex128, n int, flag Flag) *Plan {
+	return PlanDftC2R1d(data, RealView(data, n), flag)
+}
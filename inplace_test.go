@@ -0,0 +1,57 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func InPlaceSpec(c gospec.Context) {
+	c.Specify("In-place complex plans transform their data.", func() {
+		data := Alloc3d(2, 3, 4)
+		want := Alloc3d(2, 3, 4)
+		for i := range data {
+			for j := range data[i] {
+				for k := range data[i][j] {
+					data[i][j][k] = complex(float64(i+j*k), float64(k-i))
+				}
+			}
+		}
+		p := PlanDftInPlace3d(data, Forward, Estimate)
+		c.Expect(p.Geometry().InPlace, gospec.IsTrue)
+		Dft3d(data, want, Forward, Estimate)
+		p.Execute()
+		for i := range data {
+			for j := range data[i] {
+				for k := range data[i][j] {
+					c.Expect(real(data[i][j][k]), gospec.IsWithin(1e-9), real(want[i][j][k]))
+					c.Expect(imag(data[i][j][k]), gospec.IsWithin(1e-9), imag(want[i][j][k]))
+				}
+			}
+		}
+	})
+
+	c.Specify("In-place real plans transform their data and back.", func() {
+		for _, n := range []int{7, 8} {
+			data := Alloc1d(n/2 + 1)
+			x := RealView(data, n)
+			for i := range x {
+				x[i] = float64(i*3%5) - 1
+			}
+			orig := append([]float64(nil), x...)
+			want := make([]complex128, n/2+1)
+			PlanDftR2C1d(append([]float64(nil), x...), want, Estimate).Execute()
+
+			forward := PlanDftR2CInPlace1d(data, n, Estimate)
+			backward := PlanDftC2RInPlace1d(data, n, Estimate)
+			c.Expect(forward.Geometry().InPlace, gospec.IsTrue)
+			forward.Execute()
+			for k := range want {
+				c.Expect(real(data[k]), gospec.IsWithin(1e-9), real(want[k]))
+				c.Expect(imag(data[k]), gospec.IsWithin(1e-9), imag(want[k]))
+			}
+			backward.Execute()
+			for i := range orig {
+				c.Expect(x[i]/float64(n), gospec.IsWithin(1e-9), orig[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// InterpolateFFT returns x resampled at factor times its sample rate by
+// band-limited interpolation: the spectrum of x is zero padded and inverse
+// transformed.  Sample i*factor of the result equals x[i], and x is treated
+// as one period of a periodic signal.  For even len(x) the Nyquist bin is
+// split evenly between the positive and negative frequencies so that the
+// result stays real and symmetric.
+func InterpolateFFT(x []float64, factor int) []float64 {
+	if factor < 1 {
+		panic(fmt.Sprint("InterpolateFFT needs a factor of at least 1, got ", factor))
+	}
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	m := n * factor
+
+	signal := scratch.reals(n)
+	defer scratch.releaseReals(signal)
+	copy(signal, x)
+	F_signal := scratch.complexes(n/2 + 1)
+	defer scratch.releaseComplexes(F_signal)
+	PlanDftR2C1d(signal, F_signal, Estimate).Execute()
+
+	F_padded := scratch.complexes(m/2 + 1)
+	defer scratch.releaseComplexes(F_padded)
+	copy(F_padded, F_signal)
+	if n%2 == 0 && factor > 1 {
+		F_padded[n/2] /= 2
+	}
+	padded := make([]float64, m)
+	PlanDftC2R1d(F_padded, padded, Estimate).Execute()
+	ScaleReal(padded, 1/float64(n))
+	return padded
+}
@@ -0,0 +1,40 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func InterpolateFFTSpec(c gospec.Context) {
+	for _, n := range []int{16, 15} {
+		f := func(t float64) float64 {
+			return math.Cos(2*math.Pi*3*t) + 0.5*math.Sin(2*math.Pi*5*t+1)
+		}
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = f(float64(i) / float64(n))
+		}
+		y := InterpolateFFT(x, 4)
+		c.Specify("Interpolation keeps the original samples.", func() {
+			c.Expect(len(y), gospec.Equals, 4*n)
+			for i := range x {
+				c.Expect(y[4*i], gospec.IsWithin(1e-9), x[i])
+			}
+		})
+		c.Specify("Interpolation of a band-limited signal is exact.", func() {
+			for i := range y {
+				c.Expect(y[i], gospec.IsWithin(1e-9), f(float64(i)/float64(len(y))))
+			}
+		})
+	}
+
+	c.Specify("The Nyquist bin is split between both halves of the spectrum.", func() {
+		// An alternating signal is a cosine at exactly the Nyquist frequency.
+		x := []float64{1, -1, 1, -1}
+		y := InterpolateFFT(x, 2)
+		expected := []float64{1, 0, -1, 0, 1, 0, -1, 0}
+		for i := range y {
+			c.Expect(y[i], gospec.IsWithin(1e-9), expected[i])
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"math"
+)
+
+// fitsInt reports whether n fits in a C int, as the sizes and strides of
+// fftw's basic and advanced interfaces must.
+func fitsInt(n int) bool {
+	return n >= math.MinInt32 && n <= math.MaxInt32
+}
+
+// cInt converts n to a C int for fftw's basic and advanced interfaces,
+// panicking rather than silently overflowing.  Transforms that big need the
+// guru interface, which the one dimensional planners switch to by
+// themselves.
+func cInt(n int) C.int {
+	if !fitsInt(n) {
+		panic(fmt.Sprint("Size or stride ", n, " is too large for fftw's int interfaces, use PlanGuruDft and friends"))
+	}
+	return C.int(n)
+}
+
+// planLarge1d gives a one dimensional plan made with the guru interface,
+// for a length beyond the basic interface, the geometry the basic interface
+// would have given it.
+func planLarge1d(p *Plan) *Plan {
+	p.geom.IO = nil
+	p.geom.Batch = 0
+	return p
+}
@@ -0,0 +1,31 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func LargeSpec(c gospec.Context) {
+	c.Specify("Sizes beyond a C int are refused by the int interfaces.", func() {
+		c.Expect(fitsInt(math.MaxInt32), gospec.IsTrue)
+		c.Expect(fitsInt(math.MaxInt32+1), gospec.IsFalse)
+		c.Expect(int(cInt(1<<20)), gospec.Equals, 1<<20)
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		cInt(1 << 31)
+	})
+
+	c.Specify("Large one dimensional plans look like ordinary ones.", func() {
+		in := []complex128{1, 2, 3, 4i}
+		out := make([]complex128, 4)
+		p := planLarge1d(PlanGuruDft([]IODim{{4, 1, 1}}, nil, in, out, Forward, Estimate))
+		p.Execute()
+		want := make([]complex128, 4)
+		q := PlanDft1d(in, want, Forward, Estimate)
+		q.Execute()
+		for k := range want {
+			c.Expect(cmplx.Abs(out[k]-want[k]), gospec.IsWithin(1e-9), 0.0)
+		}
+		c.Expect(p.Geometry().Equal(q.Geometry()), gospec.IsTrue)
+	})
+}
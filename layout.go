@@ -0,0 +1,77 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Layout describes how a multi-dimensional array is laid out in a flat slice.
+type Layout int
+
+const (
+	// RowMajor is the C layout used by Alloc2d and Alloc3d, the last index
+	// varies fastest.
+	RowMajor Layout = iota
+	// ColumnMajor is the Fortran/Matlab layout, the first index varies fastest.
+	ColumnMajor
+)
+
+// strides returns the stride of each dimension of an array with dimensions
+// n laid out as l.
+func (l Layout) strides(n []int) []int {
+	s := make([]int, len(n))
+	stride := 1
+	if l == ColumnMajor {
+		for i := range n {
+			s[i] = stride
+			stride *= n[i]
+		}
+	} else {
+		for i := len(n) - 1; i >= 0; i-- {
+			s[i] = stride
+			stride *= n[i]
+		}
+	}
+	return s
+}
+
+// planDftLayout plans a transform of the n[0] x n[1] x ... arrays in and out,
+// stored in the flat slices in the given layout.  The output has the same
+// layout as the input, so no transposes are needed anywhere.
+func planDftLayout(in, out []complex128, n []int, layout Layout, dir Direction, flag Flag) *Plan {
+	size := 1
+	for _, v := range n {
+		size *= v
+	}
+	if len(in) < size || len(out) < size {
+		panic(fmt.Sprint("Arrays of length ", len(in), " and ", len(out), " are too short for dimensions ", n))
+	}
+	strides := layout.strides(n)
+	dims := make([]C.fftw_iodim64, len(n))
+	for i := range dims {
+		dims[i].n = C.ptrdiff_t(n[i])
+		dims[i].is = C.ptrdiff_t(strides[i])
+		dims[i].os = C.ptrdiff_t(strides[i])
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_dft(C.int(len(dims)), &dims[0], 0, nil, fftw_in, fftw_out, C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: append([]int(nil), n...), Dir: dir, Layout: layout}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDft2dLayout is like PlanDft2d but works on an n0 x n1 array stored in a
+// flat slice with the given layout, such as a column-major matrix from BLAS.
+func PlanDft2dLayout(in, out []complex128, n0, n1 int, layout Layout, dir Direction, flag Flag) *Plan {
+	return planDftLayout(in, out, []int{n0, n1}, layout, dir, flag)
+}
+
+// PlanDft3dLayout is like PlanDft3d but works on an n0 x n1 x n2 array stored
+// in a flat slice with the given layout.
+func PlanDft3dLayout(in, out []complex128, n0, n1, n2 int, layout Layout, dir Direction, flag Flag) *Plan {
+	return planDftLayout(in, out, []int{n0, n1, n2}, layout, dir, flag)
+}
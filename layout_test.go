@@ -0,0 +1,65 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func LayoutSpec(c gospec.Context) {
+	n0, n1, n2 := 6, 4, 3
+	rows := Alloc2d(n0, n1)
+	cols := Alloc1d(n0 * n1)
+	for i := 0; i < n0; i++ {
+		for j := 0; j < n1; j++ {
+			v := complex(float64(i*j+i), float64(j-i))
+			rows[i][j] = v
+			cols[i+j*n0] = v
+		}
+	}
+	Dft2d(rows, rows, Forward, Estimate)
+	PlanDft2dLayout(cols, cols, n0, n1, ColumnMajor, Forward, Estimate).Execute()
+	c.Specify("Column-major 2d transforms match row-major ones.", func() {
+		for i := 0; i < n0; i++ {
+			for j := 0; j < n1; j++ {
+				c.Expect(cmplx.Abs(cols[i+j*n0]-rows[i][j]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	rows3 := Alloc3d(n0, n1, n2)
+	flat := Alloc1d(n0 * n1 * n2)
+	cols3 := Alloc1d(n0 * n1 * n2)
+	for i := 0; i < n0; i++ {
+		for j := 0; j < n1; j++ {
+			for k := 0; k < n2; k++ {
+				v := complex(float64(i+2*j-k), float64(i*k))
+				rows3[i][j][k] = v
+				flat[(i*n1+j)*n2+k] = v
+				cols3[i+(j+k*n1)*n0] = v
+			}
+		}
+	}
+	Dft3d(rows3, rows3, Forward, Estimate)
+	PlanDft3dLayout(flat, flat, n0, n1, n2, RowMajor, Forward, Estimate).Execute()
+	PlanDft3dLayout(cols3, cols3, n0, n1, n2, ColumnMajor, Forward, Estimate).Execute()
+	c.Specify("3d transforms work in either layout.", func() {
+		for i := 0; i < n0; i++ {
+			for j := 0; j < n1; j++ {
+				for k := 0; k < n2; k++ {
+					c.Expect(cmplx.Abs(flat[(i*n1+j)*n2+k]-rows3[i][j][k]), gospec.IsWithin(1e-9), 0.0)
+					c.Expect(cmplx.Abs(cols3[i+(j+k*n1)*n0]-rows3[i][j][k]), gospec.IsWithin(1e-9), 0.0)
+				}
+			}
+		}
+	})
+
+	c.Specify("Plans in different layouts have different geometries.", func() {
+		a := Alloc1d(n0 * n1)
+		row := PlanDft2dLayout(a, a, n0, n1, RowMajor, Forward, Estimate).Geometry()
+		col := PlanDft2dLayout(a, a, n0, n1, ColumnMajor, Forward, Estimate).Geometry()
+		c.Expect(row.Equal(col), gospec.IsFalse)
+		c.Expect(row.Hash() == col.Hash(), gospec.IsFalse)
+		c.Expect(row.Key() == col.Key(), gospec.IsFalse)
+		c.Expect(row.Equal(PlanDft2d(rows, rows, Forward, Estimate).Geometry()), gospec.IsTrue)
+	})
+}
@@ -0,0 +1,144 @@
+package fftw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A ChunkedArray is a lazily evaluated signal, too large to hold in memory,
+// stored as a sequence of equally sized chunks.  Operations on it, such as
+// Window and FFT, only record what to do and return a new array; the work is
+// done a chunk at a time, with the buffers and plans of a single Graph,
+// when Chunk, Each or Collect asks for the result.
+type ChunkedArray struct {
+	size, chunks int
+	load         func(i int, chunk []float64) int
+	ops          []func(g *Graph) *Graph
+}
+
+// NewChunkedArray returns an array of the given number of chunks of size
+// samples each.  load fills chunk with the samples of chunk i and returns
+// how many it filled, which is less than size only for a short last chunk.
+func NewChunkedArray(size, chunks int, load func(i int, chunk []float64) int) *ChunkedArray {
+	if size < 1 || chunks < 0 {
+		panic(fmt.Sprint("NewChunkedArray needs a positive chunk size and a chunk count, got ", size, " and ", chunks))
+	}
+	return &ChunkedArray{size: size, chunks: chunks, load: load}
+}
+
+// ChunkedReaderAt returns an array of chunks of size samples read on demand
+// from the n little-endian float64s in r, such as a file of raw samples.
+func ChunkedReaderAt(r io.ReaderAt, n int64, size int) *ChunkedArray {
+	chunks := int((n + int64(size) - 1) / int64(size))
+	buf := make([]byte, 8*size)
+	return NewChunkedArray(size, chunks, func(i int, chunk []float64) int {
+		valid := size
+		if rest := n - int64(i)*int64(size); rest < int64(size) {
+			valid = int(rest)
+		}
+		if _, err := r.ReadAt(buf[:8*valid], 8*int64(i)*int64(size)); err != nil && err != io.EOF {
+			panic(fmt.Sprint("Could not read chunk ", i, ": ", err))
+		}
+		for j := 0; j < valid; j++ {
+			chunk[j] = math.Float64frombits(binary.LittleEndian.Uint64(buf[8*j:]))
+		}
+		return valid
+	})
+}
+
+// Chunks returns the number of chunks in a.
+func (a *ChunkedArray) Chunks() int {
+	return a.chunks
+}
+
+// then returns a copy of a with op recorded after its other operations.
+func (a *ChunkedArray) then(op func(g *Graph) *Graph) *ChunkedArray {
+	b := *a
+	b.ops = append(append([]func(g *Graph) *Graph(nil), a.ops...), op)
+	return &b
+}
+
+// Window, Samples, FFT, Spectrum and IFFT record the Graph operations of
+// the same names, to be applied to every chunk.
+func (a *ChunkedArray) Window(w Window) *ChunkedArray {
+	return a.then(func(g *Graph) *Graph { return g.Window(w) })
+}
+
+func (a *ChunkedArray) Samples(fn func(x []float64)) *ChunkedArray {
+	return a.then(func(g *Graph) *Graph { return g.Samples(fn) })
+}
+
+func (a *ChunkedArray) FFT() *ChunkedArray {
+	return a.then((*Graph).FFT)
+}
+
+func (a *ChunkedArray) Spectrum(fn func(s []complex128)) *ChunkedArray {
+	return a.then(func(g *Graph) *Graph { return g.Spectrum(fn) })
+}
+
+func (a *ChunkedArray) IFFT() *ChunkedArray {
+	return a.then((*Graph).IFFT)
+}
+
+// chunkSource reads the chunks of an array from next up to end.
+type chunkSource struct {
+	a         *ChunkedArray
+	next, end int
+}
+
+func (s *chunkSource) Read(block []float64) int {
+	if s.next >= s.end {
+		return 0
+	}
+	for i := range block {
+		block[i] = 0
+	}
+	n := s.a.load(s.next, block)
+	s.next++
+	return n
+}
+
+func (s *chunkSource) Len() int {
+	return (s.end - s.next) * s.a.size
+}
+
+// graph returns a graph that computes chunks first to end of a.
+func (a *ChunkedArray) graph(first, end int) *Graph {
+	g := NewGraph(a.size, &chunkSource{a, first, end})
+	for _, op := range a.ops {
+		g = op(g)
+	}
+	return g
+}
+
+// Chunk computes chunk i of a, returning a frame that belongs to the
+// caller.
+func (a *ChunkedArray) Chunk(i int) *Frame {
+	if i < 0 || i >= a.chunks {
+		panic(fmt.Sprint("Chunk ", i, " is out of range for an array of ", a.chunks, " chunks"))
+	}
+	f := new(Frame)
+	a.graph(i, i+1).To(SinkFunc(func(g *Frame) {
+		f.Index = i
+		f.Valid = g.Valid
+		f.Samples = append([]float64(nil), g.Samples...)
+		f.Spectrum = append([]complex128(nil), g.Spectrum...)
+	})).Run()
+	return f
+}
+
+// Each computes every chunk of a in order, passing each to fn.  The frame
+// is reused for every chunk, so fn must copy anything it keeps.
+func (a *ChunkedArray) Each(fn func(f *Frame)) {
+	a.graph(0, a.chunks).To(SinkFunc(fn)).Run()
+}
+
+// Collect computes every chunk of a and returns their valid samples joined
+// together, for results small enough to hold in memory.
+func (a *ChunkedArray) Collect() []float64 {
+	var out SampleCollector
+	a.graph(0, a.chunks).To(&out).Run()
+	return out.Samples
+}
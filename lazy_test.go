@@ -0,0 +1,60 @@
+package fftw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func ChunkedArraySpec(c gospec.Context) {
+	x := make([]float64, 90)
+	for i := range x {
+		x[i] = float64(i*7%13) - 6
+	}
+	var file bytes.Buffer
+	binary.Write(&file, binary.LittleEndian, x)
+	r := bytes.NewReader(file.Bytes())
+
+	c.Specify("Chunks are read lazily from a file.", func() {
+		loads := 0
+		base := NewChunkedArray(32, 3, func(i int, chunk []float64) int {
+			loads++
+			return copy(chunk, x[32*i:])
+		})
+		lowpass := base.FFT().Spectrum(func(s []complex128) {
+			for k := 4; k < len(s); k++ {
+				s[k] = 0
+			}
+		}).IFFT()
+		c.Expect(loads, gospec.Equals, 0)
+		f := lowpass.Chunk(1)
+		c.Expect(loads, gospec.Equals, 1)
+		c.Expect(f.Valid, gospec.Equals, 32)
+
+		want := NewGraph(32, SliceSource(x[32:64])).FFT().Spectrum(func(s []complex128) {
+			for k := 4; k < len(s); k++ {
+				s[k] = 0
+			}
+		}).IFFT()
+		var out SampleCollector
+		want.To(&out).Run()
+		for i := range out.Samples {
+			c.Expect(f.Samples[i], gospec.IsWithin(1e-9), out.Samples[i])
+		}
+
+		// Recording an operation leaves the original array alone.
+		c.Expect(base.Collect(), gospec.ContainsInOrder, x)
+	})
+
+	c.Specify("Arrays over a reader match the samples in it.", func() {
+		a := ChunkedReaderAt(r, int64(len(x)), 25)
+		c.Expect(a.Chunks(), gospec.Equals, 4)
+		c.Expect(a.Collect(), gospec.ContainsInOrder, x)
+		valid := []int{}
+		a.Window(Hann).Each(func(f *Frame) { valid = append(valid, f.Valid) })
+		c.Expect(valid, gospec.ContainsInOrder, []int{25, 25, 25, 15})
+		last := a.Chunk(3)
+		c.Expect(last.Samples[:15], gospec.ContainsInOrder, x[75:])
+		c.Expect(last.Samples[15], gospec.Equals, 0.0)
+	})
+}
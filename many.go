@@ -0,0 +1,92 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// manyDims checks the dimensions and count of a batched plan, returning
+// them for fftw along with the number of elements in each real and half
+// complex array of the batch.
+func manyDims(name string, dims []int, howmany int) (n []C.int, size, half int) {
+	if len(dims) == 0 || howmany < 1 {
+		panic(fmt.Sprint(name, " needs at least one dimension and one transform, got ", dims, " and ", howmany))
+	}
+	size = 1
+	n = make([]C.int, len(dims))
+	for i, d := range dims {
+		if d < 1 {
+			panic(fmt.Sprint(name, " needs positive dimensions, got ", dims))
+		}
+		size *= d
+		n[i] = cInt(d)
+	}
+	half = size / dims[len(dims)-1] * (dims[len(dims)-1]/2 + 1)
+	return n, size, half
+}
+
+// PlanManyDft plans howmany complex transforms of dimensions dims at once,
+// the i'th taking the row-major array at in[i*size:] to out[i*size:], where
+// size is the product of dims.  A batched plan runs faster than the same
+// number of separate plans.
+func PlanManyDft(dims []int, howmany int, in, out []complex128, dir Direction, flag Flag) *Plan {
+	n, size, _ := manyDims("PlanManyDft", dims, howmany)
+	if len(in) != size*howmany || len(out) != size*howmany {
+		panic(fmt.Sprint(howmany, " transforms of dimensions ", dims, " need arrays of length ", size*howmany, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft(C.int(len(n)), &n[0], cInt(howmany),
+		fftw_in, nil, 1, cInt(size),
+		fftw_out, nil, 1, cInt(size),
+		C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: append([]int(nil), dims...), Dir: dir, Batch: howmany}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanManyDftR2C plans howmany real-to-complex transforms of dimensions dims
+// at once, the i'th taking the real array at in[i*size:] to the half complex
+// array at out[i*half:], where size is the product of dims and half the same
+// with the last dimension d replaced by d/2+1.
+func PlanManyDftR2C(dims []int, howmany int, in []float64, out []complex128, flag Flag) *Plan {
+	n, size, half := manyDims("PlanManyDftR2C", dims, howmany)
+	if len(in) != size*howmany || len(out) != half*howmany {
+		panic(fmt.Sprint(howmany, " transforms of dimensions ", dims, " need arrays of length ", size*howmany, " and ", half*howmany, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft_r2c(C.int(len(n)), &n[0], cInt(howmany),
+		fftw_in, nil, 1, cInt(size),
+		fftw_out, nil, 1, cInt(half),
+		planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2C, Dims: append([]int(nil), dims...), Dir: Forward, Batch: howmany}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanManyDftC2R plans howmany complex-to-real transforms of dimensions dims
+// at once, the inverse of PlanManyDftR2C.  Like every complex-to-real
+// transform it overwrites its input unless flag includes PreserveInput,
+// which fftw only supports for one dimensional transforms.
+func PlanManyDftC2R(dims []int, howmany int, in []complex128, out []float64, flag Flag) *Plan {
+	n, size, half := manyDims("PlanManyDftC2R", dims, howmany)
+	if len(in) != half*howmany || len(out) != size*howmany {
+		panic(fmt.Sprint(howmany, " transforms of dimensions ", dims, " need arrays of length ", half*howmany, " and ", size*howmany, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft_c2r(C.int(len(n)), &n[0], cInt(howmany),
+		fftw_in, nil, 1, cInt(half),
+		fftw_out, nil, 1, cInt(size),
+		planFlags(flag))
+	planner.Unlock()
+	if p == nil {
+		panic(fmt.Sprint("fftw could not plan ", howmany, " complex-to-real transforms of dimensions ", dims, " with flags ", flag))
+	}
+	return newPlan(p, Geometry{Kind: C2R, Dims: append([]int(nil), dims...), Dir: Backward, Batch: howmany}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
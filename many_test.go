@@ -0,0 +1,56 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func PlanManySpec(c gospec.Context) {
+	c.Specify("Batched complex transforms match separate transforms.", func() {
+		in := make([]complex128, 3*8)
+		for i := range in {
+			in[i] = complex(float64(i%5), float64(i%3)-1)
+		}
+		out := make([]complex128, len(in))
+		p := PlanManyDft([]int{2, 4}, 3, in, out, Forward, Estimate)
+		c.Expect(p.Geometry().Batch, gospec.Equals, 3)
+		p.Execute()
+		for b := 0; b < 3; b++ {
+			want := make([]complex128, 8)
+			DftNd([]int{2, 4}, in[b*8:(b+1)*8], want, Forward, Estimate)
+			for i := range want {
+				c.Expect(cmplx.Abs(out[b*8+i]-want[i]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	c.Specify("Batched real transforms round trip.", func() {
+		in := make([]float64, 4*6)
+		for i := range in {
+			in[i] = float64(i*i%7) - 3
+		}
+		spectra := make([]complex128, 4*4)
+		PlanManyDftR2C([]int{6}, 4, in, spectra, Estimate).Execute()
+		for b := 0; b < 4; b++ {
+			want := make([]complex128, 4)
+			PlanDftR2C1d(append([]float64(nil), in[b*6:(b+1)*6]...), want, Estimate).Execute()
+			for k := range want {
+				c.Expect(cmplx.Abs(spectra[b*4+k]-want[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		back := make([]float64, len(in))
+		PlanManyDftC2R([]int{6}, 4, spectra, back, Estimate).Execute()
+		for i := range in {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-9), in[i])
+		}
+	})
+
+	c.Specify("Batched plans have different geometries from single plans.", func() {
+		in := make([]float64, 2*8)
+		out := make([]complex128, 2*5)
+		batch := PlanManyDftR2C([]int{8}, 2, in, out, Estimate).Geometry()
+		single := PlanDftR2C1d(in[:8], out[:5], Estimate).Geometry()
+		c.Expect(batch.Equal(single), gospec.IsFalse)
+		c.Expect(batch.Key() == single.Key(), gospec.IsFalse)
+	})
+}
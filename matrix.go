@@ -0,0 +1,46 @@
+package fftw
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// Twiddle returns the twiddle factor exp(dir*2*pi*i*k/n).  Twiddle(n, j*k,
+// dir) is the weight input j contributes to output k of an n point
+// transform, entry (k, j) of DftMatrix(n, dir).
+func Twiddle(n, k int, dir Direction) complex128 {
+	// Reduce k first so that large j*k products don't lose precision.
+	k %= n
+	if k < 0 {
+		k += n
+	}
+	theta := float64(dir) * 2 * math.Pi * float64(k) / float64(n)
+	return complex(math.Cos(theta), math.Sin(theta))
+}
+
+// DftMatrix returns the n x n matrix F for which F*x is the (unnormalized)
+// transform of x in direction dir, the same thing Dft1d computes.
+func DftMatrix(n int, dir Direction) *mat.CDense {
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	return DftMatrixRows(n, rows, dir)
+}
+
+// DftMatrixRows returns the len(rows) x n matrix made of the given rows of
+// DftMatrix(n, dir).  Multiplying by it computes only the requested outputs,
+// which is useful for pruned transforms.
+func DftMatrixRows(n int, rows []int, dir Direction) *mat.CDense {
+	if n < 1 || len(rows) < 1 {
+		panic(fmt.Sprint("DftMatrixRows needs at least one row and column, got ", len(rows), " x ", n))
+	}
+	data := make([]complex128, len(rows)*n)
+	for i, k := range rows {
+		for j := 0; j < n; j++ {
+			data[i*n+j] = Twiddle(n, j*k%n, dir)
+		}
+	}
+	return mat.NewCDense(len(rows), n, data)
+}
@@ -0,0 +1,52 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func DftMatrixSpec(c gospec.Context) {
+	signal := Alloc1d(12)
+	for i := range signal {
+		signal[i] = complex(float64(i*i%7), float64(3-i))
+	}
+	input := make([]complex128, len(signal))
+	copy(input, signal)
+	Dft1d(signal, signal, Forward, Estimate)
+
+	c.Specify("Multiplying by the DFT matrix matches Dft1d.", func() {
+		F := DftMatrix(len(input), Forward)
+		r, cols := F.Dims()
+		c.Expect(r, gospec.Equals, len(input))
+		c.Expect(cols, gospec.Equals, len(input))
+		for k := 0; k < r; k++ {
+			var sum complex128
+			for j := 0; j < cols; j++ {
+				sum += F.At(k, j) * input[j]
+			}
+			c.Expect(cmplx.Abs(sum-signal[k]), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("Selected rows of the DFT matrix compute selected outputs.", func() {
+		rows := []int{0, 5, 11}
+		F := DftMatrixRows(len(input), rows, Forward)
+		for i, k := range rows {
+			var sum complex128
+			for j := range input {
+				sum += F.At(i, j) * input[j]
+			}
+			c.Expect(cmplx.Abs(sum-signal[k]), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("Forward and backward DFT matrices are conjugates.", func() {
+		F := DftMatrix(5, Forward)
+		B := DftMatrix(5, Backward)
+		for i := 0; i < 5; i++ {
+			for j := 0; j < 5; j++ {
+				c.Expect(cmplx.Abs(F.At(i, j)-cmplx.Conj(B.At(i, j))), gospec.IsWithin(1e-12), 0.0)
+			}
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package fftw
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// A TransformCache memoizes transforms by a hash of their input, so that
+// tools that re-run a pipeline on unchanged data don't recompute its
+// transforms.  It holds at most a fixed number of spectra, evicting the
+// least recently used.  It is safe for concurrent use.
+type TransformCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[memoKey]*list.Element
+	lru     *list.List
+	hits    int
+	misses  int
+}
+
+// memoKey identifies a transform by its kind and a SHA-256 hash of its
+// input, which makes collisions between different inputs vanishingly
+// unlikely.
+type memoKey struct {
+	kind Kind
+	dir  Direction
+	n    int
+	hash [sha256.Size]byte
+}
+
+type memoEntry struct {
+	key      memoKey
+	spectrum []complex128
+}
+
+// NewTransformCache returns a cache of up to max spectra.
+func NewTransformCache(max int) *TransformCache {
+	if max < 1 {
+		panic(fmt.Sprint("NewTransformCache needs room for at least one spectrum, got ", max))
+	}
+	return &TransformCache{max: max, entries: make(map[memoKey]*list.Element), lru: list.New()}
+}
+
+func hashReals(x []float64) [sha256.Size]byte {
+	h := sha256.New()
+	var b [8]byte
+	for _, v := range x {
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		h.Write(b[:])
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// lookup returns a copy of the cached spectrum for key, computing it with
+// compute if it isn't cached.
+func (c *TransformCache) lookup(key memoKey, compute func() []complex128) []complex128 {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.hits++
+		s := append([]complex128(nil), e.Value.(*memoEntry).spectrum...)
+		c.mu.Unlock()
+		return s
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Transform without the lock, so that other lookups aren't held up.
+	s := compute()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.lru.PushFront(&memoEntry{key, append([]complex128(nil), s...)})
+		if c.lru.Len() > c.max {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.entries, oldest.Value.(*memoEntry).key)
+		}
+	}
+	return s
+}
+
+// Spectrum returns the real-to-complex transform of x, from the cache if x
+// has been transformed before.
+func (c *TransformCache) Spectrum(x []float64) []complex128 {
+	key := memoKey{kind: R2C, dir: Forward, n: len(x), hash: hashReals(x)}
+	return c.lookup(key, func() []complex128 {
+		out := make([]complex128, len(x)/2+1)
+		if len(x) > 0 {
+			in := append([]float64(nil), x...)
+			PlanDftR2C1d(in, out, Estimate).Execute()
+		}
+		return out
+	})
+}
+
+// Dft returns the complex transform of x in the direction dir, from the
+// cache if x has been transformed that way before.
+func (c *TransformCache) Dft(x []complex128, dir Direction) []complex128 {
+	key := memoKey{kind: C2C, dir: dir, n: len(x), hash: hashReals(asReals(x))}
+	return c.lookup(key, func() []complex128 {
+		out := make([]complex128, len(x))
+		if len(x) > 0 {
+			PlanDft1d(x, out, dir, Estimate).Execute()
+		}
+		return out
+	})
+}
+
+// Len returns the number of spectra in the cache.
+func (c *TransformCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Stats returns the number of lookups that were and weren't in the cache.
+func (c *TransformCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
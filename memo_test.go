@@ -0,0 +1,50 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func TransformCacheSpec(c gospec.Context) {
+	x := []float64{1, 2, 3, 4, 0, -1}
+	cache := NewTransformCache(2)
+
+	c.Specify("Repeated transforms come from the cache.", func() {
+		a := cache.Spectrum(x)
+		b := cache.Spectrum(append([]float64(nil), x...))
+		hits, misses := cache.Stats()
+		c.Expect(hits, gospec.Equals, 1)
+		c.Expect(misses, gospec.Equals, 1)
+		c.Expect(b, gospec.ContainsInOrder, a)
+		c.Expect(real(a[0]), gospec.IsWithin(1e-9), 9.0)
+
+		// Changing a returned spectrum doesn't change the cache.
+		a[0] = 100
+		c.Expect(real(cache.Spectrum(x)[0]), gospec.IsWithin(1e-9), 9.0)
+	})
+
+	c.Specify("Different inputs and directions are cached separately.", func() {
+		y := append([]float64(nil), x...)
+		y[5] = -2
+		c.Expect(real(cache.Spectrum(y)[0]), gospec.IsWithin(1e-9), 8.0)
+		z := []complex128{1, 1i, -1, -1i}
+		f := cache.Dft(z, Forward)
+		g := cache.Dft(z, Backward)
+		c.Expect(real(f[1]), gospec.IsWithin(1e-9), 4.0)
+		c.Expect(real(f[3]), gospec.IsWithin(1e-9), 0.0)
+		c.Expect(real(g[3]), gospec.IsWithin(1e-9), 4.0)
+		_, misses := cache.Stats()
+		c.Expect(misses, gospec.Equals, 3)
+	})
+
+	c.Specify("The least recently used spectra are evicted.", func() {
+		cache.Spectrum(x)
+		cache.Spectrum([]float64{1})
+		cache.Spectrum(x)
+		cache.Spectrum([]float64{2})
+		c.Expect(cache.Len(), gospec.Equals, 2)
+		cache.Spectrum(x)
+		hits, misses := cache.Stats()
+		c.Expect(hits, gospec.Equals, 2)
+		c.Expect(misses, gospec.Equals, 3)
+	})
+}
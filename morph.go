@@ -0,0 +1,77 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// MorphMode selects how MorphSpectra interpolates between two spectra.
+type MorphMode int
+
+const (
+	// MorphComplex interpolates the complex values linearly, which is a
+	// plain cross-fade: components the spectra share in opposite phase
+	// cancel part way through.
+	MorphComplex MorphMode = iota
+	// MorphPolar interpolates magnitudes linearly and phases along the
+	// shorter arc between them, so that each bin keeps its level through
+	// the morph.
+	MorphPolar
+)
+
+// MorphSpectra sets out to the spectrum t of the way from a to b, t
+// running from 0 for a to 1 for b.
+func MorphSpectra(a, b []complex128, t float64, mode MorphMode, out []complex128) {
+	if len(a) != len(b) || len(out) != len(a) {
+		panic(fmt.Sprint("MorphSpectra needs spectra of the same length, got ", len(a), ", ", len(b), " and ", len(out)))
+	}
+	switch mode {
+	case MorphComplex:
+		for k := range out {
+			out[k] = a[k] + (b[k]-a[k])*complex(t, 0)
+		}
+	case MorphPolar:
+		for k := range out {
+			ra, pa := cmplx.Polar(a[k])
+			rb, pb := cmplx.Polar(b[k])
+			d := math.Remainder(pb-pa, 2*math.Pi)
+			out[k] = cmplx.Rect(ra+(rb-ra)*t, pa+d*t)
+		}
+	default:
+		panic(fmt.Sprint("Unknown MorphMode ", int(mode)))
+	}
+}
+
+// Morph returns a signal that turns from x into y, by morphing the STFT
+// frames of the two and taking the inverse STFT.  amount gives how far
+// through the morph to be at each point of the signal, from 0 at the start
+// to 1 at the end; a nil amount morphs linearly from x to y.  The shorter
+// signal is zero padded to the length of the longer.
+func (s *STFT) Morph(x, y []float64, amount func(r float64) float64, mode MorphMode) []float64 {
+	n := len(x)
+	if len(y) > n {
+		n = len(y)
+	}
+	if amount == nil {
+		amount = func(r float64) float64 { return r }
+	}
+	pad := func(v []float64) []float64 {
+		if len(v) == n {
+			return v
+		}
+		p := make([]float64, n)
+		copy(p, v)
+		return p
+	}
+	a := s.Transform(pad(x))
+	b := s.Transform(pad(y))
+	for t := range a {
+		r := 0.0
+		if len(a) > 1 {
+			r = float64(t) / float64(len(a)-1)
+		}
+		MorphSpectra(a[t], b[t], amount(r), mode, a[t])
+	}
+	return s.Inverse(a, n)
+}
@@ -0,0 +1,61 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func MorphSpec(c gospec.Context) {
+	c.Specify("Spectra are interpolated as complex values or in polar form.", func() {
+		a := []complex128{1, 2, -1}
+		b := []complex128{1i, 4, 1}
+		out := make([]complex128, 3)
+		MorphSpectra(a, b, 0.5, MorphComplex, out)
+		c.Expect(out, gospec.ContainsInOrder, []complex128{0.5 + 0.5i, 3, 0})
+		MorphSpectra(a, b, 0.5, MorphPolar, out)
+		c.Expect(cmplx.Abs(out[0]-cmplx.Rect(1, math.Pi/4)), gospec.IsWithin(1e-12), 0.0)
+		c.Expect(cmplx.Abs(out[1]-3), gospec.IsWithin(1e-12), 0.0)
+		// Opposite phases keep their level rather than cancelling.
+		c.Expect(cmplx.Abs(out[2]), gospec.IsWithin(1e-12), 1.0)
+		MorphSpectra(a, b, 1, MorphPolar, out)
+		for k := range b {
+			c.Expect(cmplx.Abs(out[k]-b[k]), gospec.IsWithin(1e-12), 0.0)
+		}
+	})
+
+	s := NewSTFT(64, 16, Hann)
+	x := make([]float64, 1024)
+	y := make([]float64, 900)
+	for i := range x {
+		x[i] = math.Sin(2 * math.Pi * 4 * float64(i) / 64)
+	}
+	for i := range y {
+		y[i] = math.Sin(2 * math.Pi * 12 * float64(i) / 64)
+	}
+
+	c.Specify("Morphing a signal into itself gives it back.", func() {
+		for _, mode := range []MorphMode{MorphComplex, MorphPolar} {
+			z := s.Morph(x, x, nil, mode)
+			for i := range x {
+				c.Expect(z[i], gospec.IsWithin(1e-9), x[i])
+			}
+		}
+	})
+
+	c.Specify("A morph starts as one signal and ends as the other.", func() {
+		z := s.Morph(x, y, func(r float64) float64 {
+			if r < 0.5 {
+				return 0
+			}
+			return 1
+		}, MorphPolar)
+		c.Expect(len(z), gospec.Equals, len(x))
+		for i := 64; i < 400; i++ {
+			c.Expect(z[i], gospec.IsWithin(1e-9), x[i])
+		}
+		for i := 600; i < 850; i++ {
+			c.Expect(z[i], gospec.IsWithin(1e-9), y[i])
+		}
+	})
+}
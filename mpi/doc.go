@@ -0,0 +1,12 @@
+// Package mpi binds fftw's MPI transforms, which distribute 2d and 3d
+// grids too large for one node over the processes of MPI_COMM_WORLD.
+//
+// Each process holds a slab of the grid: a run of planes of its first
+// dimension, as LocalSize reports.  Plans are collective, so every process
+// must make the same plans in the same order and execute them together.
+//
+// The package needs fftw built with --enable-mpi and an MPI implementation,
+// and is only built with the mpi build tag, as in
+//
+//	CC=mpicc go build -tags mpi
+package mpi
@@ -0,0 +1,287 @@
+//go:build mpi
+
+package mpi
+
+// #cgo pkg-config: fftw3
+// #cgo LDFLAGS: -lfftw3_mpi
+// #include <stddef.h>
+// #include <mpi.h>
+// #include <fftw3-mpi.h>
+//
+// // Plans are made over MPI_COMM_WORLD, which can't be named from Go
+// // portably since MPI implementations disagree on the type of MPI_Comm.
+//
+// static int init_mpi(int *started) {
+//   int ok;
+//   MPI_Initialized(&ok);
+//   *started = !ok;
+//   if (!ok && MPI_Init(NULL, NULL) != MPI_SUCCESS) {
+//     return 0;
+//   }
+//   fftw_mpi_init();
+//   return 1;
+// }
+//
+// static int world_rank(void) {
+//   int r;
+//   MPI_Comm_rank(MPI_COMM_WORLD, &r);
+//   return r;
+// }
+//
+// static int world_size(void) {
+//   int n;
+//   MPI_Comm_size(MPI_COMM_WORLD, &n);
+//   return n;
+// }
+//
+// static ptrdiff_t local_size(int rnk, const ptrdiff_t *n, ptrdiff_t block0, ptrdiff_t block1, ptrdiff_t *n0, ptrdiff_t *start0, ptrdiff_t *n1, ptrdiff_t *start1) {
+//   return fftw_mpi_local_size_many_transposed(rnk, n, 1, block0, block1, MPI_COMM_WORLD, n0, start0, n1, start1);
+// }
+//
+// static fftw_plan plan_dft(int rnk, const ptrdiff_t *n, ptrdiff_t block0, ptrdiff_t block1, fftw_complex *in, fftw_complex *out, int sign, unsigned flags) {
+//   return fftw_mpi_plan_many_dft(rnk, n, 1, block0, block1, in, out, MPI_COMM_WORLD, sign, flags);
+// }
+//
+// static fftw_plan plan_dft_r2c(int rnk, const ptrdiff_t *n, ptrdiff_t block0, ptrdiff_t block1, double *in, fftw_complex *out, unsigned flags) {
+//   return fftw_mpi_plan_many_dft_r2c(rnk, n, 1, block0, block1, in, out, MPI_COMM_WORLD, flags);
+// }
+//
+// static fftw_plan plan_dft_c2r(int rnk, const ptrdiff_t *n, ptrdiff_t block0, ptrdiff_t block1, fftw_complex *in, double *out, unsigned flags) {
+//   return fftw_mpi_plan_many_dft_c2r(rnk, n, 1, block0, block1, in, out, MPI_COMM_WORLD, flags);
+// }
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/runningwild/go-fftw"
+)
+
+// started records whether Init started MPI, and so whether Cleanup should
+// finalize it.
+var started C.int
+
+// Init initializes MPI, unless the program already has, and fftw's MPI
+// support.  It must be called by every process before anything else in
+// the package.
+func Init() error {
+	if C.init_mpi(&started) == 0 {
+		return fmt.Errorf("MPI_Init failed")
+	}
+	return nil
+}
+
+// Cleanup frees fftw's MPI state, and finalizes MPI if Init started it.
+// Plans must not be executed afterwards.
+func Cleanup() {
+	C.fftw_mpi_cleanup()
+	if started != 0 {
+		C.MPI_Finalize()
+		started = 0
+	}
+}
+
+// Rank returns the rank of this process in MPI_COMM_WORLD.
+func Rank() int {
+	return int(C.world_rank())
+}
+
+// Size returns the number of processes in MPI_COMM_WORLD.
+func Size() int {
+	return int(C.world_size())
+}
+
+// Options selects how a grid is split between the processes.
+type Options struct {
+	// Block is the number of planes of the first dimension in each
+	// process's slab, the last process taking what is left.  0 lets fftw
+	// split the planes as evenly as it can.
+	Block int
+	// TransposedBlock is Block for the second dimension, over which
+	// transposed arrays are split.
+	TransposedBlock int
+	// TransposedOut leaves the output with its first two dimensions
+	// swapped, and split over the second dimension of the grid, which
+	// saves the final global transpose.  Transforming such output back with
+	// TransposedIn restores the usual layout, so a convolution needs no
+	// global transposes at all beyond fftw's internal one.
+	TransposedOut bool
+	// TransposedIn takes input laid out as TransposedOut leaves it.
+	TransposedIn bool
+}
+
+func (o Options) flags(flag fftw.Flag) C.uint {
+	f := C.uint(fftw.PlannerFlags(flag))
+	if o.TransposedOut {
+		f |= C.FFTW_MPI_TRANSPOSED_OUT
+	}
+	if o.TransposedIn {
+		f |= C.FFTW_MPI_TRANSPOSED_IN
+	}
+	return f
+}
+
+func (o Options) blocks() (C.ptrdiff_t, C.ptrdiff_t) {
+	b0, b1 := C.ptrdiff_t(C.FFTW_MPI_DEFAULT_BLOCK), C.ptrdiff_t(C.FFTW_MPI_DEFAULT_BLOCK)
+	if o.Block > 0 {
+		b0 = C.ptrdiff_t(o.Block)
+	}
+	if o.TransposedBlock > 0 {
+		b1 = C.ptrdiff_t(o.TransposedBlock)
+	}
+	return b0, b1
+}
+
+// Local describes the part of a distributed grid held by this process.
+type Local struct {
+	// N0 planes of the first dimension, from Start0, are held here.
+	N0, Start0 int
+	// N1 planes of the second dimension, from Start1, are held here by
+	// arrays transposed with TransposedOut or TransposedIn.
+	N1, Start1 int
+	// Alloc is the number of complex elements the local arrays need,
+	// which can be more than the slab itself since fftw uses them as
+	// scratch space.
+	Alloc int
+}
+
+func cDims(name string, dims []int) []C.ptrdiff_t {
+	if len(dims) < 2 {
+		panic(fmt.Sprint(name, " needs at least two dimensions, got ", dims))
+	}
+	n := make([]C.ptrdiff_t, len(dims))
+	for i, d := range dims {
+		if d < 1 {
+			panic(fmt.Sprint(name, " needs positive dimensions, got ", dims))
+		}
+		n[i] = C.ptrdiff_t(d)
+	}
+	return n
+}
+
+func localSize(n []C.ptrdiff_t, opts Options) Local {
+	var n0, start0, n1, start1 C.ptrdiff_t
+	b0, b1 := opts.blocks()
+	alloc := C.local_size(C.int(len(n)), &n[0], b0, b1, &n0, &start0, &n1, &start1)
+	return Local{int(n0), int(start0), int(n1), int(start1), int(alloc)}
+}
+
+// LocalSize returns this process's part of a complex grid of dimensions
+// dims split as opts says.  The local arrays are laid out in row-major
+// order, starting at plane Start0 of the grid.
+func LocalSize(dims []int, opts Options) Local {
+	return localSize(cDims("LocalSize", dims), opts)
+}
+
+// LocalSizeR2C returns this process's part of the real grid of dimensions
+// dims and of its spectrum, whose last dimension is halved as usual.  The
+// local real array has its last dimension padded to 2*(n/2+1), even for
+// out of place transforms, and needs 2*Alloc elements.
+func LocalSizeR2C(dims []int, opts Options) Local {
+	n := cDims("LocalSizeR2C", dims)
+	n[len(n)-1] = n[len(n)-1]/2 + 1
+	return localSize(n, opts)
+}
+
+// A Plan is a distributed transform.  Execute must be called by every
+// process at once.
+type Plan struct {
+	fftw_p C.fftw_plan
+	// The local arrays, kept alive for as long as the plan can use them.
+	in, out unsafe.Pointer
+}
+
+func destroyPlan(p *Plan) {
+	C.fftw_destroy_plan(p.fftw_p)
+}
+
+func newPlan(name string, fftw_p C.fftw_plan, in, out unsafe.Pointer) *Plan {
+	if fftw_p == nil {
+		panic(fmt.Sprint("fftw could not make the plan for ", name))
+	}
+	p := &Plan{fftw_p: fftw_p, in: in, out: out}
+	runtime.SetFinalizer(p, destroyPlan)
+	return p
+}
+
+// Execute executes p on this process's part of its arrays.
+func (p *Plan) Execute() {
+	C.fftw_execute(p.fftw_p)
+	runtime.KeepAlive(p)
+}
+
+func planDft(name string, dims []int, in, out []complex128, dir fftw.Direction, flag fftw.Flag, opts Options) *Plan {
+	n := cDims(name, dims)
+	if want := localSize(n, opts).Alloc; len(in) < want || len(out) < want {
+		panic(fmt.Sprint(name, " needs local arrays of ", want, " elements, got ", len(in), " and ", len(out)))
+	}
+	b0, b1 := opts.blocks()
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	p := C.plan_dft(C.int(len(n)), &n[0], b0, b1, fftw_in, fftw_out, C.int(dir), opts.flags(flag))
+	return newPlan(name, p, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDft2d plans the distributed complex transform of an n0 x n1 grid,
+// of which in and out hold this process's part, as LocalSize says.
+func PlanDft2d(n0, n1 int, in, out []complex128, dir fftw.Direction, flag fftw.Flag, opts Options) *Plan {
+	return planDft("PlanDft2d", []int{n0, n1}, in, out, dir, flag, opts)
+}
+
+// PlanDft3d plans the distributed complex transform of an n0 x n1 x n2
+// grid, as PlanDft2d does.
+func PlanDft3d(n0, n1, n2 int, in, out []complex128, dir fftw.Direction, flag fftw.Flag, opts Options) *Plan {
+	return planDft("PlanDft3d", []int{n0, n1, n2}, in, out, dir, flag, opts)
+}
+
+func checkR2C(name string, dims []int, real, complex int, opts Options) []C.ptrdiff_t {
+	n := cDims(name, dims)
+	half := append([]C.ptrdiff_t(nil), n...)
+	half[len(half)-1] = half[len(half)-1]/2 + 1
+	if want := localSize(half, opts).Alloc; real < 2*want || complex < want {
+		panic(fmt.Sprint(name, " needs local arrays of ", 2*want, " reals and ", want, " complex elements, got ", real, " and ", complex))
+	}
+	return n
+}
+
+func planDftR2C(name string, dims []int, in []float64, out []complex128, flag fftw.Flag, opts Options) *Plan {
+	n := checkR2C(name, dims, len(in), len(out), opts)
+	b0, b1 := opts.blocks()
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	p := C.plan_dft_r2c(C.int(len(n)), &n[0], b0, b1, fftw_in, fftw_out, opts.flags(flag))
+	return newPlan(name, p, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func planDftC2R(name string, dims []int, in []complex128, out []float64, flag fftw.Flag, opts Options) *Plan {
+	n := checkR2C(name, dims, len(out), len(in), opts)
+	b0, b1 := opts.blocks()
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	p := C.plan_dft_c2r(C.int(len(n)), &n[0], b0, b1, fftw_in, fftw_out, opts.flags(flag))
+	return newPlan(name, p, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftR2C2d plans the distributed transform of a real n0 x n1 grid, of
+// which in and out hold this process's part, as LocalSizeR2C says.
+func PlanDftR2C2d(n0, n1 int, in []float64, out []complex128, flag fftw.Flag, opts Options) *Plan {
+	return planDftR2C("PlanDftR2C2d", []int{n0, n1}, in, out, flag, opts)
+}
+
+// PlanDftR2C3d plans the distributed transform of a real n0 x n1 x n2
+// grid, as PlanDftR2C2d does.
+func PlanDftR2C3d(n0, n1, n2 int, in []float64, out []complex128, flag fftw.Flag, opts Options) *Plan {
+	return planDftR2C("PlanDftR2C3d", []int{n0, n1, n2}, in, out, flag, opts)
+}
+
+// PlanDftC2R2d plans the inverse of PlanDftR2C2d, which destroys its input.
+func PlanDftC2R2d(n0, n1 int, in []complex128, out []float64, flag fftw.Flag, opts Options) *Plan {
+	return planDftC2R("PlanDftC2R2d", []int{n0, n1}, in, out, flag, opts)
+}
+
+// PlanDftC2R3d plans the inverse of PlanDftR2C3d, which destroys its input.
+func PlanDftC2R3d(n0, n1, n2 int, in []complex128, out []float64, flag fftw.Flag, opts Options) *Plan {
+	return planDftC2R("PlanDftC2R3d", []int{n0, n1, n2}, in, out, flag, opts)
+}
@@ -0,0 +1,65 @@
+//go:build mpi
+
+package mpi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/orfjackal/gospec/src/gospec"
+	"github.com/runningwild/go-fftw"
+)
+
+func MPISpec(c gospec.Context) {
+	c.Specify("A distributed 2d transform round trips.", func() {
+		n0, n1 := 8, 6
+		local := LocalSize([]int{n0, n1}, Options{})
+		c.Expect(local.N0 <= n0, gospec.IsTrue)
+		data := make([]complex128, local.Alloc)
+		for i := 0; i < local.N0; i++ {
+			for j := 0; j < n1; j++ {
+				data[i*n1+j] = complex(float64(local.Start0+i), float64(j))
+			}
+		}
+		PlanDft2d(n0, n1, data, data, fftw.Forward, fftw.Estimate, Options{}).Execute()
+		PlanDft2d(n0, n1, data, data, fftw.Backward, fftw.Estimate, Options{}).Execute()
+		for i := 0; i < local.N0; i++ {
+			for j := 0; j < n1; j++ {
+				v := data[i*n1+j] / complex(float64(n0*n1), 0)
+				c.Expect(real(v), gospec.IsWithin(1e-9), float64(local.Start0+i))
+				c.Expect(imag(v), gospec.IsWithin(1e-9), float64(j))
+			}
+		}
+	})
+
+	c.Specify("Transposed output transforms back with transposed input.", func() {
+		n0, n1, n2 := 4, 6, 5
+		local := LocalSizeR2C([]int{n0, n1, n2}, Options{TransposedOut: true})
+		padded := 2 * (n2/2 + 1)
+		in := make([]float64, 2*local.Alloc)
+		for i := 0; i < local.N0*n1; i++ {
+			for k := 0; k < n2; k++ {
+				in[i*padded+k] = math.Sin(float64(i + k))
+			}
+		}
+		want := append([]float64(nil), in...)
+		out := make([]complex128, local.Alloc)
+		PlanDftR2C3d(n0, n1, n2, in, out, fftw.Estimate, Options{TransposedOut: true}).Execute()
+		PlanDftC2R3d(n0, n1, n2, out, in, fftw.Estimate, Options{TransposedIn: true}).Execute()
+		for i := 0; i < local.N0*n1; i++ {
+			for k := 0; k < n2; k++ {
+				c.Expect(in[i*padded+k]/float64(n0*n1*n2), gospec.IsWithin(1e-9), want[i*padded+k])
+			}
+		}
+	})
+}
+
+func TestMPI(t *testing.T) {
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer Cleanup()
+	r := gospec.NewRunner()
+	r.AddSpec(MPISpec)
+	gospec.MainGoTest(r, t)
+}
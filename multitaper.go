@@ -0,0 +1,152 @@
+package fftw
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/lapack/gonum"
+	"math"
+)
+
+// DPSS returns the first k discrete prolate spheroidal (Slepian) sequences
+// of length n with time-half-bandwidth product nw, along with their
+// concentrations, the fraction of each taper's energy that lies inside the
+// band [-nw/n, nw/n].  The tapers have unit energy and are ordered by
+// decreasing concentration; only about 2*nw of them are well concentrated.
+func DPSS(n int, nw float64, k int) ([][]float64, []float64) {
+	tapers := dpssTapers(n, nw, k)
+	// The concentration is v'Av, where A[i][j] is
+	// sin(2*pi*w*(i-j))/(pi*(i-j)), a function of i-j alone, so it is a sum
+	// over the lags of the autocorrelation of v.
+	w := nw / float64(n)
+	a := make([]float64, n)
+	a[0] = 2 * w
+	for d := 1; d < n; d++ {
+		a[d] = math.Sin(2*math.Pi*w*float64(d)) / (math.Pi * float64(d))
+	}
+	concentrations := make([]float64, k)
+	for t, v := range tapers {
+		r := Autocorrelation(v, n-1)
+		c := a[0] * r[0]
+		for d := 1; d < n; d++ {
+			c += 2 * a[d] * r[d]
+		}
+		// Autocorrelation divides by n.
+		concentrations[t] = c * float64(n)
+	}
+	return tapers, concentrations
+}
+
+// dpssTapers returns the tapers of DPSS without their concentrations.
+func dpssTapers(n int, nw float64, k int) [][]float64 {
+	if k < 1 || k > n {
+		panic(fmt.Sprint("DPSS needs between 1 and ", n, " tapers, got ", k))
+	}
+	w := nw / float64(n)
+	// The tapers are the eigenvectors of a symmetric tridiagonal matrix
+	// that commutes with the concentration problem, which is much cheaper
+	// to solve.
+	diag := make([]float64, n)
+	off := make([]float64, n-1)
+	for i := range diag {
+		x := (float64(n-1) - 2*float64(i)) / 2
+		diag[i] = x * x * math.Cos(2*math.Pi*w)
+	}
+	for i := range off {
+		off[i] = float64((i+1)*(n-i-1)) / 2
+	}
+	eig := append([]float64(nil), diag...)
+	var impl gonum.Implementation
+	if !impl.Dsterf(n, eig, append([]float64(nil), off...)) {
+		panic("DPSS could not find the eigenvalues of the tridiagonal matrix")
+	}
+
+	tapers := make([][]float64, k)
+	for t := range tapers {
+		// Inverse iteration, using the eigenvalues in decreasing order.
+		lambda := eig[n-1-t]
+		shift := lambda + 1e-10*math.Max(math.Abs(lambda), 1)
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = 1 + 0.1*math.Sin(float64(i+t))
+		}
+		for iter := 0; iter < 3; iter++ {
+			d := make([]float64, n)
+			for i := range d {
+				d[i] = diag[i] - shift
+			}
+			if !impl.Dgtsv(n, 1, append([]float64(nil), off...), d, append([]float64(nil), off...), v, 1) {
+				panic("DPSS inverse iteration failed")
+			}
+			// Keep the tapers orthogonal to each other.
+			for _, u := range tapers[:t] {
+				dot := 0.0
+				for i := range u {
+					dot += u[i] * v[i]
+				}
+				for i := range u {
+					v[i] -= dot * u[i]
+				}
+			}
+			norm := 0.0
+			for _, x := range v {
+				norm += x * x
+			}
+			norm = math.Sqrt(norm)
+			for i := range v {
+				v[i] /= norm
+			}
+		}
+		// Symmetric tapers should sum to a positive value and antisymmetric
+		// ones should start with a positive lobe.
+		sum := 0.0
+		if t%2 == 0 {
+			for _, x := range v {
+				sum += x
+			}
+		} else {
+			for _, x := range v[:n/2] {
+				sum += x
+			}
+		}
+		if sum < 0 {
+			ScaleReal(v, -1)
+		}
+		tapers[t] = v
+	}
+	return tapers
+}
+
+// MultitaperPSD estimates the one-sided power spectral density of x with
+// Thomson's multitaper method, averaging the periodograms of x tapered by
+// each of the first k DPSS tapers with time-half-bandwidth product nw.  The
+// PSD is scaled so that integrating it over frequency gives the mean square
+// of x.  This has far less variance than a single periodogram, at the cost of
+// smoothing the spectrum over a band of width 2*nw bins.
+func MultitaperPSD(x []float64, nw float64, k int, sampleRate float64) ([]float64, []float64) {
+	n := len(x)
+	if n == 0 {
+		panic("MultitaperPSD needs a non-empty signal")
+	}
+	tapers := dpssTapers(n, nw, k)
+	frames := make([][]float64, k)
+	for t, taper := range tapers {
+		frames[t] = make([]float64, n)
+		for i := range x {
+			frames[t][i] = x[i] * taper[i]
+		}
+	}
+	spectra := DftFrames(frames)
+
+	freqs := rfftFreqs(n, sampleRate)
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+	psd := make([]float64, len(freqs))
+	for _, s := range spectra {
+		for i, v := range s {
+			psd[i] += real(v)*real(v) + imag(v)*imag(v)
+		}
+	}
+	ScaleReal(psd, 1/(float64(k)*sampleRate))
+	OneSided(psd, n, PreserveEdges)
+	return freqs, psd
+}
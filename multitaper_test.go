@@ -0,0 +1,98 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+)
+
+func MultitaperSpec(c gospec.Context) {
+	c.Specify("DPSS tapers are orthonormal and well concentrated.", func() {
+		tapers, conc := DPSS(128, 4, 7)
+		c.Expect(len(tapers), gospec.Equals, 7)
+		for i := range tapers {
+			for j := range tapers {
+				dot := 0.0
+				for k := range tapers[i] {
+					dot += tapers[i][k] * tapers[j][k]
+				}
+				if i == j {
+					c.Expect(dot, gospec.IsWithin(1e-9), 1.0)
+				} else {
+					c.Expect(dot, gospec.IsWithin(1e-9), 0.0)
+				}
+			}
+			c.Expect(conc[i] > 0.9, gospec.IsTrue)
+			if i > 0 {
+				c.Expect(conc[i] <= conc[i-1]+1e-12, gospec.IsTrue)
+			}
+		}
+	})
+
+	c.Specify("DPSS concentrations are the energy of each taper in the band.", func() {
+		n, nw := 48, 3.0
+		w := nw / float64(n)
+		tapers, conc := DPSS(n, nw, 5)
+		for t, v := range tapers {
+			want := 0.0
+			for i := range v {
+				for j := range v {
+					a := 2 * w
+					if d := float64(i - j); d != 0 {
+						a = math.Sin(2*math.Pi*w*d) / (math.Pi * d)
+					}
+					want += v[i] * a * v[j]
+				}
+			}
+			c.Expect(conc[t], gospec.IsWithin(1e-9), want)
+		}
+	})
+
+	c.Specify("DPSS tapers alternate between symmetric and antisymmetric.", func() {
+		tapers, _ := DPSS(64, 3, 4)
+		for t, v := range tapers {
+			sign := 1.0
+			if t%2 == 1 {
+				sign = -1
+			}
+			for i := range v {
+				c.Expect(v[i], gospec.IsWithin(1e-9), sign*v[len(v)-1-i])
+			}
+		}
+		c.Expect(tapers[0][32] > 0, gospec.IsTrue)
+		c.Expect(tapers[1][10] > 0, gospec.IsTrue)
+	})
+
+	c.Specify("The multitaper PSD of white noise is flat at the right level.", func() {
+		rng := rand.New(rand.NewSource(3))
+		x := make([]float64, 1024)
+		for i := range x {
+			x[i] = 2 * rng.NormFloat64()
+		}
+		freqs, psd := MultitaperPSD(x, 4, 7, 100)
+		c.Expect(len(freqs), gospec.Equals, 513)
+		c.Expect(freqs[512], gospec.IsWithin(1e-9), 50.0)
+		// A variance of 4 spread over 50Hz.
+		mean := 0.0
+		for _, v := range psd[1:512] {
+			mean += v
+		}
+		mean /= 511
+		c.Expect(mean, gospec.IsWithin(0.01), 0.08)
+	})
+
+	c.Specify("The multitaper PSD finds a sinusoid.", func() {
+		x := make([]float64, 512)
+		for i := range x {
+			x[i] = math.Sin(2 * math.Pi * 64 * float64(i) / 512)
+		}
+		_, psd := MultitaperPSD(x, 3, 5, 0)
+		peak := 0
+		for i := range psd {
+			if psd[i] > psd[peak] {
+				peak = i
+			}
+		}
+		c.Expect(peak, gospec.Equals, 64)
+	})
+}
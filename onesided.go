@@ -0,0 +1,73 @@
+package fftw
+
+import "math/cmplx"
+
+// EdgeMode selects what OneSided does with the DC bin and, for even
+// lengths, the Nyquist bin, which have no mirror image among the negative
+// frequencies.
+type EdgeMode int
+
+const (
+	// PreserveEdges leaves the edge bins as they are, which keeps the
+	// amplitudes and powers of every bin right.  This is what WelchPSD and
+	// MultitaperPSD do.
+	PreserveEdges EdgeMode = iota
+	// HalveEdges halves the edge bins, weighting them as the trapezoidal
+	// rule does, since each covers only half a bin of the single-sided
+	// frequency axis.
+	HalveEdges
+	// ZeroEdges zeroes the edge bins, leaving only the AC content.
+	ZeroEdges
+)
+
+// OneSided folds the negative frequencies of the amplitude or power
+// spectrum a, the first n/2+1 bins of a transform of length n, into the
+// positive ones, in place, by doubling every bin but the edges, which are
+// handled according to edges.
+func OneSided(a []float64, n int, edges EdgeMode) {
+	for k := range a {
+		if k == 0 || (n%2 == 0 && k == n/2) {
+			switch edges {
+			case HalveEdges:
+				a[k] /= 2
+			case ZeroEdges:
+				a[k] = 0
+			}
+		} else {
+			a[k] *= 2
+		}
+	}
+}
+
+// AmplitudeSpectrum returns the single-sided amplitude spectrum of signal,
+// tapered by window, or untapered if window is nil.  The spectrum is scaled
+// by 2/N and divided by the coherent gain of the window, the mean of its
+// samples, so that a sinusoid of amplitude A centred on a bin reads A there,
+// and a constant offset reads its value at DC.
+func AmplitudeSpectrum(signal []float64, window Window) []float64 {
+	n := len(signal)
+	if n == 0 {
+		return nil
+	}
+	x := make([]float64, n)
+	gain := 1.0
+	if window != nil {
+		taper := Window1d(n, window)
+		gain = 0
+		for i, w := range taper {
+			x[i] = signal[i] * w
+			gain += w
+		}
+		gain /= float64(n)
+	} else {
+		copy(x, signal)
+	}
+	F_x := make([]complex128, n/2+1)
+	PlanDftR2C1d(x, F_x, Estimate).Execute()
+	a := make([]float64, len(F_x))
+	for k, v := range F_x {
+		a[k] = cmplx.Abs(v) / (float64(n) * gain)
+	}
+	OneSided(a, n, PreserveEdges)
+	return a
+}
@@ -0,0 +1,46 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func OneSidedSpec(c gospec.Context) {
+	c.Specify("Interior bins are doubled and edges handled by mode.", func() {
+		for _, n := range []int{6, 7} {
+			for mode, edge := range []float64{1, 0.5, 0} {
+				a := []float64{1, 1, 1, 1}
+				OneSided(a, n, EdgeMode(mode))
+				c.Expect(a[0], gospec.Equals, edge)
+				c.Expect(a[1], gospec.Equals, 2.0)
+				c.Expect(a[2], gospec.Equals, 2.0)
+				if n%2 == 0 {
+					c.Expect(a[3], gospec.Equals, edge)
+				} else {
+					c.Expect(a[3], gospec.Equals, 2.0)
+				}
+			}
+		}
+	})
+}
+
+func AmplitudeSpectrumSpec(c gospec.Context) {
+	n := 64
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1.5 + 3*math.Cos(2*math.Pi*5*float64(i)/float64(n)+0.4) + 0.5*math.Cos(math.Pi*float64(i))
+	}
+
+	c.Specify("Untapered amplitudes read the true amplitudes.", func() {
+		a := AmplitudeSpectrum(x, nil)
+		c.Expect(a[0], gospec.IsWithin(1e-9), 1.5)
+		c.Expect(a[5], gospec.IsWithin(1e-9), 3.0)
+		c.Expect(a[n/2], gospec.IsWithin(1e-9), 0.5)
+		c.Expect(a[7], gospec.IsWithin(1e-9), 0.0)
+	})
+
+	c.Specify("Windowed amplitudes are corrected for coherent gain.", func() {
+		a := AmplitudeSpectrum(x, Tukey(0.5))
+		c.Expect(a[5], gospec.IsWithin(0.05), 3.0)
+	})
+}
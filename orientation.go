@@ -0,0 +1,142 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A TileOrientation is the dominant orientation of one tile of an image.
+type TileOrientation struct {
+	// Y and X are the row and column of the tile's top left corner.
+	Y, X int
+	// Angle is the direction of the tile's stripes or ridges, in radians
+	// from 0 to π, measured from the direction of increasing column
+	// towards that of increasing row.
+	Angle float64
+	// Coherence is 1 when all of the tile's energy lies in one
+	// orientation and 0 when it is spread evenly over all of them.
+	Coherence float64
+	// Energy is the tile's spectral energy, apart from its mean, so that
+	// flat tiles, whose angle means nothing, can be told apart.
+	Energy float64
+}
+
+// An OrientationAnalyzer measures the orientation of size x size tiles of
+// images from their windowed spectra, as used for texture and fingerprint
+// analysis.  It reuses one plan and buffer for every tile, so it isn't
+// safe for concurrent use.
+type OrientationAnalyzer struct {
+	size  int
+	taper [][]float64
+	buf   [][]complex128
+	plan  *Plan
+	freqs []float64
+}
+
+// NewOrientationAnalyzer returns an analyzer of size x size tiles, which
+// are tapered with the isotropic window RadialWindow2d(size, size, w) so
+// that the tile's edges don't add energy along the axes.
+func NewOrientationAnalyzer(size int, w Window) *OrientationAnalyzer {
+	if size < 2 {
+		panic(fmt.Sprint("NewOrientationAnalyzer needs tiles of at least 2 x 2, got ", size))
+	}
+	a := &OrientationAnalyzer{size: size, taper: RadialWindow2d(size, size, w)}
+	a.buf = Alloc2d(size, size)
+	a.plan = PlanDft2d(a.buf, a.buf, Forward, Estimate)
+	a.freqs = make([]float64, size)
+	for k := range a.freqs {
+		a.freqs[k] = signedFreq(k, size)
+	}
+	return a
+}
+
+// spectrum leaves the power spectrum of the tile of x at y, x in a.buf.
+func (a *OrientationAnalyzer) spectrum(img [][]float64, y, x int) {
+	mean := 0.0
+	for i := 0; i < a.size; i++ {
+		mean += sum(img[y+i][x : x+a.size])
+	}
+	mean /= float64(a.size * a.size)
+	for i := range a.buf {
+		for j := range a.buf[i] {
+			a.buf[i][j] = complex((img[y+i][x+j]-mean)*a.taper[i][j], 0)
+		}
+	}
+	a.plan.Execute()
+	for i := range a.buf {
+		for j, v := range a.buf[i] {
+			a.buf[i][j] = complex(real(v)*real(v)+imag(v)*imag(v), 0)
+		}
+	}
+}
+
+// Tile returns the orientation of the tile of img whose top left corner is
+// at row y and column x, from the spectral structure tensor: the power
+// weighted covariance of the tile's wave vectors, whose principal axis is
+// the direction across the tile's stripes.
+func (a *OrientationAnalyzer) Tile(img [][]float64, y, x int) TileOrientation {
+	a.spectrum(img, y, x)
+	var jyy, jxx, jxy, energy float64
+	for i, row := range a.buf {
+		for j, v := range row {
+			p, fy, fx := real(v), a.freqs[i], a.freqs[j]
+			jyy += p * fy * fy
+			jxx += p * fx * fx
+			jxy += p * fx * fy
+			energy += p
+		}
+	}
+	t := TileOrientation{Y: y, X: x, Energy: energy}
+	if jxx+jyy == 0 {
+		return t
+	}
+	// The wave vectors point across the stripes, so the stripes run at
+	// right angles to them.
+	t.Angle = math.Mod(0.5*math.Atan2(2*jxy, jxx-jyy)+math.Pi/2, math.Pi)
+	t.Coherence = math.Hypot(jxx-jyy, 2*jxy) / (jxx + jyy)
+	return t
+}
+
+// Spectrum returns the energy of the tile of img at y, x in each of bins
+// equal ranges of stripe angle from 0 to π, angles being measured as in
+// TileOrientation.  The mean and the Nyquist row and column, whose
+// orientation is ambiguous, are left out.
+func (a *OrientationAnalyzer) Spectrum(img [][]float64, y, x, bins int) []float64 {
+	if bins < 1 {
+		panic(fmt.Sprint("Spectrum needs at least one bin, got ", bins))
+	}
+	a.spectrum(img, y, x)
+	e := make([]float64, bins)
+	for i, row := range a.buf {
+		for j, v := range row {
+			if (i == 0 && j == 0) || 2*i == a.size || 2*j == a.size {
+				continue
+			}
+			angle := math.Mod(math.Atan2(a.freqs[i], a.freqs[j])+math.Pi/2+2*math.Pi, math.Pi)
+			b := int(angle / math.Pi * float64(bins))
+			if b == bins {
+				b = 0
+			}
+			e[b] += real(v)
+		}
+	}
+	return e
+}
+
+// Field returns the orientation of every tile of img, tiles starting every
+// step rows and columns, as rows of TileOrientations.  Tiles that would run
+// off the image are left out.
+func (a *OrientationAnalyzer) Field(img [][]float64, step int) [][]TileOrientation {
+	if step < 1 {
+		panic(fmt.Sprint("Field needs a positive step, got ", step))
+	}
+	var field [][]TileOrientation
+	for y := 0; y+a.size <= len(img); y += step {
+		var row []TileOrientation
+		for x := 0; x+a.size <= len(img[y]); x += step {
+			row = append(row, a.Tile(img, y, x))
+		}
+		field = append(field, row)
+	}
+	return field
+}
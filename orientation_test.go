@@ -0,0 +1,62 @@
+package fftw
+
+import (
+	"math"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+// stripes returns an n x n image of stripes whose wave vector points at
+// angle phi with a period of four samples.
+func stripes(n int, phi float64) [][]float64 {
+	img := alloc2dReal(n, n)
+	for i := range img {
+		for j := range img[i] {
+			img[i][j] = 3 + math.Cos(math.Pi/2*(float64(j)*math.Cos(phi)+float64(i)*math.Sin(phi)))
+		}
+	}
+	return img
+}
+
+func OrientationSpec(c gospec.Context) {
+	a := NewOrientationAnalyzer(16, Hann)
+
+	c.Specify("Stripes are found at right angles to their wave vector.", func() {
+		t := a.Tile(stripes(16, 0), 0, 0)
+		c.Expect(t.Angle, gospec.IsWithin(1e-6), math.Pi/2)
+		c.Expect(t.Coherence > 0.9, gospec.IsTrue)
+		t = a.Tile(stripes(16, math.Pi/2), 0, 0)
+		c.Expect(math.Min(t.Angle, math.Pi-t.Angle), gospec.IsWithin(1e-6), 0.0)
+		t = a.Tile(stripes(16, math.Pi/4), 0, 0)
+		c.Expect(t.Angle, gospec.IsWithin(0.05), 3*math.Pi/4)
+		c.Expect(t.Coherence > 0.9, gospec.IsTrue)
+	})
+
+	c.Specify("Flat tiles have no energy or orientation.", func() {
+		img := alloc2dReal(16, 16)
+		t := a.Tile(img, 0, 0)
+		c.Expect(t.Energy, gospec.Equals, 0.0)
+		c.Expect(t.Coherence, gospec.Equals, 0.0)
+	})
+
+	c.Specify("The orientation spectrum peaks at the stripes' angle.", func() {
+		e := a.Spectrum(stripes(16, 0), 0, 0, 8)
+		best := 0
+		for b := range e {
+			if e[b] > e[best] {
+				best = b
+			}
+		}
+		c.Expect(best, gospec.Equals, 4)
+	})
+
+	c.Specify("Fields cover the image in whole tiles.", func() {
+		img := stripes(40, 0)
+		f := a.Field(img, 8)
+		c.Expect(len(f), gospec.Equals, 4)
+		c.Expect(len(f[0]), gospec.Equals, 4)
+		c.Expect(f[3][2].Y, gospec.Equals, 24)
+		c.Expect(f[3][2].X, gospec.Equals, 16)
+		c.Expect(f[3][2].Angle, gospec.IsWithin(1e-6), math.Pi/2)
+	})
+}
@@ -0,0 +1,35 @@
+package fftw
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// DftPair computes the real-to-complex transforms of two real signals of
+// the same length with a single complex transform, by packing a into the
+// real part and b into the imaginary part and separating the spectra using
+// their symmetry.  Like PlanDftR2C1d, each result has len(a)/2+1 elements.
+func DftPair(a, b []float64) ([]complex128, []complex128) {
+	if len(a) != len(b) {
+		panic(fmt.Sprint("DftPair needs signals of the same length, got ", len(a), " and ", len(b)))
+	}
+	n := len(a)
+	if n == 0 {
+		return nil, nil
+	}
+	z := make([]complex128, n)
+	for i := range z {
+		z[i] = complex(a[i], b[i])
+	}
+	Dft1d(z, z, Forward, Estimate)
+
+	A := make([]complex128, n/2+1)
+	B := make([]complex128, n/2+1)
+	for k := range A {
+		zk := z[k]
+		zn := cmplx.Conj(z[(n-k)%n])
+		A[k] = (zk + zn) / 2
+		B[k] = (zk - zn) / complex(0, 2)
+	}
+	return A, B
+}
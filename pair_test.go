@@ -0,0 +1,31 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func DftPairSpec(c gospec.Context) {
+	for _, n := range []int{16, 15} {
+		a := make([]float64, n)
+		b := make([]float64, n)
+		for i := range a {
+			a[i] = math.Cos(float64(i)) + float64(i%3)
+			b[i] = math.Sin(float64(i*i)) - 1
+		}
+		A, B := DftPair(a, b)
+		FA := make([]complex128, n/2+1)
+		FB := make([]complex128, n/2+1)
+		PlanDftR2C1d(a, FA, Estimate).Execute()
+		PlanDftR2C1d(b, FB, Estimate).Execute()
+		c.Specify("DftPair matches two separate real transforms.", func() {
+			c.Expect(len(A), gospec.Equals, len(FA))
+			c.Expect(len(B), gospec.Equals, len(FB))
+			for k := range FA {
+				c.Expect(cmplx.Abs(A[k]-FA[k]), gospec.IsWithin(1e-9), 0.0)
+				c.Expect(cmplx.Abs(B[k]-FB[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// ApplyPhaseResponse returns signal passed through the all-pass filter whose
+// phase response is phase, in radians, at each of the len(signal)/2+1
+// frequencies of a real transform of signal.  The filter is circular, so
+// responses with a long group delay wrap around the ends of the signal.
+//
+// The DC bin, and the Nyquist bin of even length signals, must stay real to
+// keep the result real, so only the real part of their phase factor,
+// cos(phase), is applied, just as CircularShift does.
+func ApplyPhaseResponse(signal, phase []float64) []float64 {
+	n := len(signal)
+	if len(phase) != n/2+1 {
+		panic(fmt.Sprint("ApplyPhaseResponse needs ", n/2+1, " phases for ", n, " samples, got ", len(phase)))
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]float64, n)
+	copy(out, signal)
+	F_out := make([]complex128, n/2+1)
+	PlanDftR2C1d(out, F_out, Estimate).Execute()
+	for k := range F_out {
+		if k == 0 || (n%2 == 0 && k == n/2) {
+			F_out[k] *= complex(math.Cos(phase[k]), 0)
+		} else {
+			F_out[k] *= cmplx.Rect(1, phase[k])
+		}
+	}
+	PlanDftC2R1d(F_out, out, Estimate).Execute()
+	ScaleReal(out, 1/float64(n))
+	return out
+}
@@ -0,0 +1,46 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+)
+
+func ApplyPhaseResponseSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(3))
+	x := make([]float64, 32)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+
+	c.Specify("A linear phase response delays the signal.", func() {
+		phase := make([]float64, len(x)/2+1)
+		for k := range phase {
+			phase[k] = -2 * math.Pi * float64(k) * 3 / float64(len(x))
+		}
+		y := ApplyPhaseResponse(x, phase)
+		for i := range y {
+			c.Expect(y[i], gospec.IsWithin(1e-9), x[(i+len(x)-3)%len(x)])
+		}
+	})
+
+	c.Specify("Dispersion can be undone and preserves energy.", func() {
+		phase := make([]float64, len(x)/2+1)
+		undo := make([]float64, len(phase))
+		for k := 1; k < len(phase)-1; k++ {
+			phase[k] = 0.05 * float64(k*k)
+			undo[k] = -phase[k]
+		}
+		y := ApplyPhaseResponse(x, phase)
+		var ex, ey float64
+		for i := range x {
+			ex += x[i] * x[i]
+			ey += y[i] * y[i]
+		}
+		c.Expect(ey, gospec.IsWithin(1e-9), ex)
+		z := ApplyPhaseResponse(y, undo)
+		for i := range z {
+			c.Expect(z[i], gospec.IsWithin(1e-9), x[i])
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package fftw
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A PipelineConfig describes a chain of processing stages, such as
+//
+//	{"stages": [{"type": "window", "window": "hann"}, {"type": "fft"},
+//	            {"type": "filter", "low": 300, "high": 3400}, {"type": "ifft"}]}
+//
+// or
+//
+//	{"stages": [{"type": "stft", "size": 512, "hop": 128},
+//	            {"type": "mel", "bands": 40}, {"type": "db", "range": 80}]}
+//
+// so that a service can change its processing without being recompiled.
+type PipelineConfig struct {
+	Stages []StageConfig `json:"stages"`
+}
+
+// A StageConfig describes one stage of a pipeline.  Type is one of
+//   - window: tapers the samples with Window, which is hann, tukey or
+//     gaussian, the last two taking Param as their alpha or sigma;
+//   - fft: replaces the samples with their real spectrum;
+//   - filter: zeroes the spectrum outside Low to High Hz, High defaulting
+//     to the Nyquist frequency;
+//   - ifft: replaces the spectrum with the samples it is the spectrum of;
+//   - stft: replaces the samples with the power spectrogram of frames of
+//     Size samples, Hop apart, tapered by Window;
+//   - mel: sums the spectrogram's power into Bands triangular bands evenly
+//     spaced on the mel scale from Low to High Hz;
+//   - db: converts the spectrogram's power to dB, clamping anything more
+//     than Range dB below its loudest level if Range is positive.
+type StageConfig struct {
+	Type   string  `json:"type"`
+	Window string  `json:"window,omitempty"`
+	Param  float64 `json:"param,omitempty"`
+	Low    float64 `json:"low,omitempty"`
+	High   float64 `json:"high,omitempty"`
+	Size   int     `json:"size,omitempty"`
+	Hop    int     `json:"hop,omitempty"`
+	Bands  int     `json:"bands,omitempty"`
+	Range  float64 `json:"range,omitempty"`
+}
+
+// PipelineData is the data a pipeline works on.  Each stage reads and
+// replaces one of Samples, Spectrum and Spectrogram.
+type PipelineData struct {
+	SampleRate float64
+	Samples    []float64
+	// Spectrum is the real spectrum of Length samples.
+	Spectrum []complex128
+	Length   int
+	// Spectrogram holds one row of levels per frame.
+	Spectrogram [][]float64
+	// Freqs holds the frequency of each column of the spectrogram.
+	Freqs []float64
+}
+
+// A Pipeline is a chain of processing stages built from a PipelineConfig.
+type Pipeline struct {
+	stages []func(d *PipelineData)
+}
+
+// The kinds of data that flow between stages.
+const (
+	pipelineSamples = iota
+	pipelineSpectrum
+	pipelineSpectrogram
+)
+
+var pipelineDataNames = []string{"samples", "a spectrum", "a spectrogram"}
+
+// LoadPipeline reads a JSON PipelineConfig from r and builds it.
+func LoadPipeline(r io.Reader) (*Pipeline, error) {
+	var cfg PipelineConfig
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	return NewPipeline(cfg)
+}
+
+// NewPipeline builds the stages of cfg, checking that each stage gets the
+// kind of data it works on.
+func NewPipeline(cfg PipelineConfig) (*Pipeline, error) {
+	p := new(Pipeline)
+	have := pipelineSamples
+	for i, sc := range cfg.Stages {
+		stage, needs, makes, err := newStage(sc)
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %v", i, err)
+		}
+		if needs != have {
+			return nil, fmt.Errorf("stage %d: %s needs %s but gets %s", i, sc.Type, pipelineDataNames[needs], pipelineDataNames[have])
+		}
+		have = makes
+		p.stages = append(p.stages, stage)
+	}
+	return p, nil
+}
+
+func pipelineWindow(sc StageConfig) (Window, error) {
+	switch sc.Window {
+	case "", "hann":
+		return Hann, nil
+	case "tukey":
+		return Tukey(sc.Param), nil
+	case "gaussian":
+		if sc.Param <= 0 {
+			return nil, fmt.Errorf("gaussian window needs a positive param")
+		}
+		return Gaussian(sc.Param), nil
+	}
+	return nil, fmt.Errorf("unknown window %q", sc.Window)
+}
+
+// newStage returns the function that runs the stage described by sc, along
+// with the kinds of data it takes and makes.
+func newStage(sc StageConfig) (func(d *PipelineData), int, int, error) {
+	switch sc.Type {
+	case "window":
+		w, err := pipelineWindow(sc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return func(d *PipelineData) {
+			taper := Window1d(len(d.Samples), w)
+			for i := range d.Samples {
+				d.Samples[i] *= taper[i]
+			}
+		}, pipelineSamples, pipelineSamples, nil
+
+	case "fft":
+		return func(d *PipelineData) {
+			d.Length = len(d.Samples)
+			d.Spectrum = make([]complex128, d.Length/2+1)
+			if d.Length > 0 {
+				PlanDftR2C1d(d.Samples, d.Spectrum, Estimate).Execute()
+			}
+			d.Samples = nil
+		}, pipelineSamples, pipelineSpectrum, nil
+
+	case "filter":
+		if sc.Low < 0 || (sc.High != 0 && sc.High < sc.Low) {
+			return nil, 0, 0, fmt.Errorf("bad filter band %v to %v Hz", sc.Low, sc.High)
+		}
+		return func(d *PipelineData) {
+			high := sc.High
+			if high == 0 {
+				high = math.Inf(1)
+			}
+			for k, f := range rfftFreqs(d.Length, d.SampleRate) {
+				if f < sc.Low || f > high {
+					d.Spectrum[k] = 0
+				}
+			}
+		}, pipelineSpectrum, pipelineSpectrum, nil
+
+	case "ifft":
+		return func(d *PipelineData) {
+			d.Samples = make([]float64, d.Length)
+			if d.Length > 0 {
+				PlanDftC2R1d(d.Spectrum, d.Samples, Estimate).Execute()
+				ScaleReal(d.Samples, 1/float64(d.Length))
+			}
+			d.Spectrum = nil
+		}, pipelineSpectrum, pipelineSamples, nil
+
+	case "stft":
+		if sc.Size < 1 || sc.Hop < 1 {
+			return nil, 0, 0, fmt.Errorf("stft needs a positive size and hop")
+		}
+		w, err := pipelineWindow(sc)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return func(d *PipelineData) {
+			spectra := NewSTFT(sc.Size, sc.Hop, w).Transform(d.Samples)
+			d.Spectrogram = make([][]float64, len(spectra))
+			for t, frame := range spectra {
+				d.Spectrogram[t] = make([]float64, len(frame))
+				for k, v := range frame {
+					d.Spectrogram[t][k] = real(v)*real(v) + imag(v)*imag(v)
+				}
+			}
+			d.Freqs = rfftFreqs(sc.Size, d.SampleRate)
+			d.Samples = nil
+		}, pipelineSamples, pipelineSpectrogram, nil
+
+	case "mel":
+		if sc.Bands < 1 || sc.Low < 0 || (sc.High != 0 && sc.High <= sc.Low) {
+			return nil, 0, 0, fmt.Errorf("mel needs a positive number of bands and a band of frequencies")
+		}
+		return func(d *PipelineData) {
+			high := sc.High
+			if high == 0 {
+				high = d.SampleRate / 2
+			}
+			weights, centers := melWeights(sc.Bands, d.Freqs, sc.Low, high)
+			for t, frame := range d.Spectrogram {
+				bands := make([]float64, sc.Bands)
+				for b, w := range weights {
+					for k, v := range frame {
+						bands[b] += w[k] * v
+					}
+				}
+				d.Spectrogram[t] = bands
+			}
+			d.Freqs = centers
+		}, pipelineSpectrogram, pipelineSpectrogram, nil
+
+	case "db":
+		return func(d *PipelineData) {
+			max := math.Inf(-1)
+			for _, frame := range d.Spectrogram {
+				for k, v := range frame {
+					frame[k] = 10 * math.Log10(v)
+					max = math.Max(max, frame[k])
+				}
+			}
+			if sc.Range > 0 {
+				for _, frame := range d.Spectrogram {
+					for k := range frame {
+						frame[k] = math.Max(frame[k], max-sc.Range)
+					}
+				}
+			}
+		}, pipelineSpectrogram, pipelineSpectrogram, nil
+	}
+	return nil, 0, 0, fmt.Errorf("unknown stage type %q", sc.Type)
+}
+
+func hzToMel(f float64) float64 {
+	return 2595 * math.Log10(1+f/700)
+}
+
+func melToHz(m float64) float64 {
+	return 700 * (math.Pow(10, m/2595) - 1)
+}
+
+// melWeights returns the weight of each of freqs in each of bands
+// triangular filters evenly spaced on the mel scale from low to high, along
+// with the centre frequency of each filter.
+func melWeights(bands int, freqs []float64, low, high float64) ([][]float64, []float64) {
+	edges := make([]float64, bands+2)
+	for i := range edges {
+		edges[i] = melToHz(hzToMel(low) + (hzToMel(high)-hzToMel(low))*float64(i)/float64(bands+1))
+	}
+	weights := make([][]float64, bands)
+	for b := range weights {
+		weights[b] = make([]float64, len(freqs))
+		l, c, h := edges[b], edges[b+1], edges[b+2]
+		for k, f := range freqs {
+			if f > l && f <= c {
+				weights[b][k] = (f - l) / (c - l)
+			} else if f > c && f < h {
+				weights[b][k] = (h - f) / (h - c)
+			}
+		}
+	}
+	return weights, edges[1 : bands+1]
+}
+
+// Run runs the pipeline on a copy of samples taken at sampleRate.
+func (p *Pipeline) Run(samples []float64, sampleRate float64) *PipelineData {
+	d := &PipelineData{SampleRate: sampleRate}
+	d.Samples = append([]float64(nil), samples...)
+	for _, stage := range p.stages {
+		stage(d)
+	}
+	return d
+}
@@ -0,0 +1,72 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"strings"
+)
+
+func PipelineSpec(c gospec.Context) {
+	rate := 1000.0
+	x := make([]float64, 500)
+	for i := range x {
+		t := float64(i) / rate
+		x[i] = math.Sin(2*math.Pi*50*t) + math.Sin(2*math.Pi*300*t)
+	}
+
+	c.Specify("A filtering pipeline removes tones outside its band.", func() {
+		p, err := LoadPipeline(strings.NewReader(`{"stages": [
+			{"type": "fft"}, {"type": "filter", "low": 100}, {"type": "ifft"}]}`))
+		c.Expect(err, gospec.IsNil)
+		d := p.Run(x, rate)
+		c.Expect(len(d.Samples), gospec.Equals, len(x))
+		for i, v := range d.Samples {
+			c.Expect(v, gospec.IsWithin(1e-9), math.Sin(2*math.Pi*300*float64(i)/rate))
+		}
+		c.Expect(x[1], gospec.Equals, math.Sin(2*math.Pi*50/rate)+math.Sin(2*math.Pi*300/rate))
+	})
+
+	c.Specify("A mel spectrogram pipeline finds the loudest band.", func() {
+		p, err := LoadPipeline(strings.NewReader(`{"stages": [
+			{"type": "window", "window": "tukey", "param": 0.2},
+			{"type": "stft", "size": 64, "hop": 32},
+			{"type": "mel", "bands": 8, "low": 20},
+			{"type": "db", "range": 60}]}`))
+		c.Expect(err, gospec.IsNil)
+		d := p.Run(x, rate)
+		c.Expect(len(d.Spectrogram), gospec.Equals, len(x)/32+1)
+		c.Expect(len(d.Freqs), gospec.Equals, 8)
+		frame := d.Spectrogram[len(d.Spectrogram)/2]
+		loudest, min := 0, math.Inf(1)
+		for b, v := range frame {
+			if v > frame[loudest] {
+				loudest = b
+			}
+			min = math.Min(min, v)
+		}
+		// The 300 Hz tone lands in the band centred nearest it.
+		best := 0
+		for b, f := range d.Freqs {
+			if math.Abs(f-300) < math.Abs(d.Freqs[best]-300) {
+				best = b
+			}
+		}
+		c.Expect(loudest, gospec.Equals, best)
+		c.Expect(frame[loudest]-min <= 60, gospec.IsTrue)
+	})
+
+	c.Specify("Pipelines with mismatched or unknown stages are rejected.", func() {
+		for _, bad := range []string{
+			`{"stages": [{"type": "ifft"}]}`,
+			`{"stages": [{"type": "fft"}, {"type": "window"}]}`,
+			`{"stages": [{"type": "stft", "size": 64, "hop": 16}, {"type": "fft"}]}`,
+			`{"stages": [{"type": "stft"}]}`,
+			`{"stages": [{"type": "window", "window": "kaiser"}]}`,
+			`{"stages": [{"type": "wavelet"}]}`,
+			`{"stages": `,
+		} {
+			_, err := LoadPipeline(strings.NewReader(bad))
+			c.Expect(err, gospec.Not(gospec.IsNil))
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// A PlanSpec is a declarative description of a transform, as parsed by
+// ParsePlanSpec, from which plans can be made.
+type PlanSpec struct {
+	Kind   Kind
+	Dims   []int
+	Dir    Direction
+	Flag   Flag
+	Layout Layout
+	// Threads is the number of threads plans are made with, or 0 to use
+	// the number SetThreads last set.
+	Threads int
+}
+
+// effortFlags and optionFlags are the planner flags plan specs name, but
+// for measure, which is 0.
+var effortFlags = map[string]Flag{
+	"estimate":   Estimate,
+	"patient":    Patient,
+	"exhaustive": Exhaustive,
+}
+
+var optionFlags = map[string]Flag{
+	"wisdom_only":     WisdomOnly,
+	"preserve_input":  PreserveInput,
+	"destroy_input":   DestroyInput,
+	"unaligned":       Unaligned,
+	"conserve_memory": ConserveMemory,
+}
+
+// ParsePlanSpec parses a transform description such as
+//
+//	"c2c 1024x768 forward measure threads=8"
+//
+// made of whitespace separated words in any order after the kind, which
+// must come first and is one of c2c, r2c or c2r.  The other words are
+//   - the dimensions, separated by x, which are required;
+//   - forward or backward, which defaults to the only direction r2c and c2r
+//     transforms have, and must be given for c2c transforms;
+//   - one of estimate, measure, patient and exhaustive, defaulting to
+//     estimate, and any of wisdom_only, preserve_input, destroy_input,
+//     unaligned and conserve_memory, the planner flags of the same names;
+//   - rowmajor or colmajor, the layout of c2c arrays, defaulting to rowmajor;
+//   - threads=n.
+func ParsePlanSpec(s string) (PlanSpec, error) {
+	words := strings.Fields(strings.ToLower(s))
+	if len(words) == 0 {
+		return PlanSpec{}, fmt.Errorf("empty plan spec")
+	}
+	var spec PlanSpec
+	switch words[0] {
+	case "c2c":
+		spec.Kind = C2C
+	case "r2c":
+		spec.Kind = R2C
+		spec.Dir = Forward
+	case "c2r":
+		spec.Kind = C2R
+		spec.Dir = Backward
+	default:
+		return PlanSpec{}, fmt.Errorf("plan spec %q must start with c2c, r2c or c2r", s)
+	}
+	dirSet, flagSet, layoutSet := false, false, false
+	for _, w := range words[1:] {
+		switch {
+		case w == "forward" || w == "backward":
+			dir := Forward
+			if w == "backward" {
+				dir = Backward
+			}
+			if dirSet || (spec.Kind != C2C && dir != spec.Dir) {
+				return PlanSpec{}, fmt.Errorf("plan spec %q has a conflicting direction %q", s, w)
+			}
+			spec.Dir, dirSet = dir, true
+		case effortFlags[w] != 0 || w == "measure":
+			if flagSet {
+				return PlanSpec{}, fmt.Errorf("plan spec %q has a conflicting planner effort %q", s, w)
+			}
+			spec.Flag |= effortFlags[w]
+			flagSet = true
+		case optionFlags[w] != 0:
+			spec.Flag |= optionFlags[w]
+		case w == "rowmajor" || w == "colmajor":
+			if layoutSet || spec.Kind != C2C {
+				return PlanSpec{}, fmt.Errorf("plan spec %q can't have layout %q", s, w)
+			}
+			if w == "colmajor" {
+				spec.Layout = ColumnMajor
+			}
+			layoutSet = true
+		case strings.HasPrefix(w, "threads="):
+			n, err := strconv.Atoi(w[len("threads="):])
+			if err != nil || n < 1 {
+				return PlanSpec{}, fmt.Errorf("plan spec %q has a bad thread count %q", s, w)
+			}
+			spec.Threads = n
+		case w[0] >= '0' && w[0] <= '9':
+			if spec.Dims != nil {
+				return PlanSpec{}, fmt.Errorf("plan spec %q has more than one set of dimensions", s)
+			}
+			for _, d := range strings.Split(w, "x") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n < 1 {
+					return PlanSpec{}, fmt.Errorf("plan spec %q has bad dimensions %q", s, w)
+				}
+				spec.Dims = append(spec.Dims, n)
+			}
+		default:
+			return PlanSpec{}, fmt.Errorf("plan spec %q has an unknown word %q", s, w)
+		}
+	}
+	if spec.Dims == nil {
+		return PlanSpec{}, fmt.Errorf("plan spec %q has no dimensions", s)
+	}
+	if spec.Kind == C2C && !dirSet {
+		return PlanSpec{}, fmt.Errorf("plan spec %q needs a direction", s)
+	}
+	if !flagSet {
+		spec.Flag |= Estimate
+	}
+	return spec, nil
+}
+
+// Geometry returns the geometry of the out of place plans made from s.
+func (s PlanSpec) Geometry() Geometry {
+	return Geometry{Kind: s.Kind, Dims: append([]int(nil), s.Dims...), Dir: s.Dir, Layout: s.Layout}
+}
+
+// Size returns the number of elements in the arrays of s, real or complex,
+// taking the halved last dimension of the complex side of r2c and c2r
+// transforms into account.
+func (s PlanSpec) Size() (in, out int) {
+	n := 1
+	for _, d := range s.Dims {
+		n *= d
+	}
+	half := n / s.Dims[len(s.Dims)-1] * (s.Dims[len(s.Dims)-1]/2 + 1)
+	switch s.Kind {
+	case R2C:
+		return n, half
+	case C2R:
+		return half, n
+	}
+	return n, n
+}
+
+func (s PlanSpec) check(kind Kind, in, out int) {
+	if s.Kind != kind {
+		panic(fmt.Sprint("Can't make a ", kind, " plan from a ", s.Kind, " spec"))
+	}
+	if wantIn, wantOut := s.Size(); in < wantIn || out < wantOut {
+		panic(fmt.Sprint("Arrays of length ", in, " and ", out, " are too short for dimensions ", s.Dims))
+	}
+}
+
+// PlanDft plans the c2c transform described by s on the flat arrays in and
+// out, laid out as s says.
+func (s PlanSpec) PlanDft(in, out []complex128) *Plan {
+	s.check(C2C, len(in), len(out))
+	defer withThreads(s.Threads)()
+	return planDftLayout(in, out, s.Dims, s.Layout, s.Dir, s.Flag)
+}
+
+// PlanDftR2C plans the r2c transform described by s on the flat row-major
+// arrays in and out.
+func (s PlanSpec) PlanDftR2C(in []float64, out []complex128) *Plan {
+	s.check(R2C, len(in), len(out))
+	defer withThreads(s.Threads)()
+	n := s.cDims()
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_r2c(C.int(len(n)), &n[0], fftw_in, fftw_out, planFlags(s.Flag))
+	planner.Unlock()
+	return newPlan(p, s.Geometry(), unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftC2R plans the c2r transform described by s on the flat row-major
+// arrays in and out.
+func (s PlanSpec) PlanDftC2R(in []complex128, out []float64) *Plan {
+	s.check(C2R, len(in), len(out))
+	if len(s.Dims) > 1 && s.Flag&PreserveInput != 0 {
+		// fftw can't keep the input of multi-dimensional c2r transforms, so
+		// the plan works on a copy, as PlanDftC2R2d's does.
+		scratch := make([]complex128, len(in))
+		s.Flag &^= PreserveInput
+		p := s.PlanDftC2R(scratch, out)
+		p.prepare = func() { copy(scratch, in) }
+		return p
+	}
+	defer withThreads(s.Threads)()
+	n := s.cDims()
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_dft_c2r(C.int(len(n)), &n[0], fftw_in, fftw_out, planFlags(s.Flag))
+	planner.Unlock()
+	if p == nil {
+		panic(fmt.Sprint("fftw could not plan ", s))
+	}
+	return newPlan(p, s.Geometry(), unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func (s PlanSpec) cDims() []C.int {
+	n := make([]C.int, len(s.Dims))
+	for i, d := range s.Dims {
+		n[i] = cInt(d)
+	}
+	return n
+}
+
+// String returns s in the form ParsePlanSpec parses.
+func (s PlanSpec) String() string {
+	words := []string{s.Kind.String()}
+	dims := make([]string, len(s.Dims))
+	for i, d := range s.Dims {
+		dims[i] = strconv.Itoa(d)
+	}
+	words = append(words, strings.Join(dims, "x"))
+	if s.Dir == Forward {
+		words = append(words, "forward")
+	} else {
+		words = append(words, "backward")
+	}
+	switch {
+	case s.Flag&Estimate != 0:
+		words = append(words, "estimate")
+	case s.Flag&Exhaustive != 0:
+		words = append(words, "exhaustive")
+	case s.Flag&Patient != 0:
+		words = append(words, "patient")
+	default:
+		words = append(words, "measure")
+	}
+	for _, w := range []string{"wisdom_only", "preserve_input", "destroy_input", "unaligned", "conserve_memory"} {
+		if s.Flag&optionFlags[w] != 0 {
+			words = append(words, w)
+		}
+	}
+	if s.Layout == ColumnMajor {
+		words = append(words, "colmajor")
+	}
+	if s.Threads > 0 {
+		words = append(words, "threads="+strconv.Itoa(s.Threads))
+	}
+	return strings.Join(words, " ")
+}
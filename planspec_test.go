@@ -0,0 +1,102 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func PlanSpecSpec(c gospec.Context) {
+	c.Specify("Plan specs are parsed.", func() {
+		s, err := ParsePlanSpec("c2c 1024x768 forward measure threads=8")
+		c.Expect(err, gospec.IsNil)
+		c.Expect(s.Kind, gospec.Equals, C2C)
+		c.Expect(s.Dims, gospec.ContainsInOrder, []int{1024, 768})
+		c.Expect(s.Dir, gospec.Equals, Forward)
+		c.Expect(s.Flag, gospec.Equals, Measure)
+		c.Expect(s.Threads, gospec.Equals, 8)
+		c.Expect(s.String(), gospec.Equals, "c2c 1024x768 forward measure threads=8")
+
+		s, err = ParsePlanSpec("C2R 16 preserve_input")
+		c.Expect(err, gospec.IsNil)
+		c.Expect(s.Dir, gospec.Equals, Backward)
+		c.Expect(s.Flag, gospec.Equals, Estimate|PreserveInput)
+		in, out := s.Size()
+		c.Expect(in, gospec.Equals, 9)
+		c.Expect(out, gospec.Equals, 16)
+
+		s, err = ParsePlanSpec("r2c 16 unaligned patient conserve_memory")
+		c.Expect(err, gospec.IsNil)
+		c.Expect(s.Flag, gospec.Equals, Patient|Unaligned|ConserveMemory)
+		c.Expect(s.String(), gospec.Equals, "r2c 16 forward patient unaligned conserve_memory")
+	})
+
+	c.Specify("Bad plan specs are rejected.", func() {
+		for _, bad := range []string{
+			"",
+			"dct 8 forward",
+			"c2c forward",
+			"c2c 8",
+			"c2c 8x0 forward",
+			"r2c 8 backward",
+			"c2c 8 forward backward",
+			"c2c 8 forward threads=0",
+			"c2c 8 forward quickly",
+			"c2c 8 forward estimate measure",
+			"r2c 8x8 colmajor",
+		} {
+			_, err := ParsePlanSpec(bad)
+			c.Expect(err, gospec.Not(gospec.IsNil))
+		}
+	})
+
+	c.Specify("Plans made from specs compute their transforms.", func() {
+		s, _ := ParsePlanSpec("c2c 4x3 forward colmajor")
+		in := make([]complex128, 12)
+		out := make([]complex128, 12)
+		in[1] = 1
+		p := s.PlanDft(in, out)
+		c.Expect(p.Geometry().Equal(s.Geometry()), gospec.IsTrue)
+		p.Execute()
+		// Element (1, 0) of a column-major array.
+		for j := 0; j < 3; j++ {
+			for i := 0; i < 4; i++ {
+				want := complex(math.Cos(2*math.Pi*float64(i)/4), -math.Sin(2*math.Pi*float64(i)/4))
+				c.Expect(real(out[i+4*j]), gospec.IsWithin(1e-9), real(want))
+				c.Expect(imag(out[i+4*j]), gospec.IsWithin(1e-9), imag(want))
+			}
+		}
+
+		r2c, _ := ParsePlanSpec("r2c 4x6")
+		c2r, _ := ParsePlanSpec("c2r 4x6")
+		x := make([]float64, 24)
+		for i := range x {
+			x[i] = float64(i * i % 7)
+		}
+		y := make([]float64, 24)
+		F := make([]complex128, 16)
+		r2c.PlanDftR2C(x, F).Execute()
+		c2r.PlanDftC2R(F, y).Execute()
+		for i := range x {
+			c.Expect(y[i]/24, gospec.IsWithin(1e-9), x[i])
+		}
+	})
+
+	c.Specify("Multi-dimensional c2r specs can preserve their input.", func() {
+		r2c, _ := ParsePlanSpec("r2c 4x6")
+		c2r, err := ParsePlanSpec("c2r 4x6 preserve_input")
+		c.Expect(err, gospec.IsNil)
+		x := make([]float64, 24)
+		for i := range x {
+			x[i] = float64(i * i % 5)
+		}
+		F := make([]complex128, 16)
+		r2c.PlanDftR2C(x, F).Execute()
+		orig := append([]complex128(nil), F...)
+		y := make([]float64, 24)
+		c2r.PlanDftC2R(F, y).Execute()
+		c.Expect(F, gospec.ContainsInOrder, orig)
+		for i := range x {
+			c.Expect(y[i]/24, gospec.IsWithin(1e-9), x[i])
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A PME computes the reciprocal space part of the Ewald sum of point
+// charges in a periodic orthorhombic box by the smooth particle mesh Ewald
+// method: charges are spread onto a grid with cardinal B-splines, the grid
+// is transformed, multiplied by the influence function and transformed
+// back into a potential, from which the energy and forces follow.  Units
+// are Gaussian, so multiply energies and forces by the Coulomb constant of
+// your unit system.  The real space and self terms are left to the caller.
+// PMEs are not safe for concurrent use.
+type PME struct {
+	dims  [3]int
+	box   [3]float64
+	order int
+	// grid holds the spread charges, and the potential once Solve has run.
+	grid      []float64
+	spectrum  []complex128
+	forward   *Plan
+	backward  *Plan
+	influence []float64
+	w, dw     [3][]float64
+}
+
+// NewPME returns a PME for a box of side lengths box, gridded into dims
+// cells, with Ewald splitting parameter alpha, in inverse length, and
+// B-splines of the given order, which must be at least 3 and no more than
+// the smallest dimension.  Higher orders are more accurate and slower;
+// 4 to 6 are usual.
+func NewPME(dims [3]int, box [3]float64, alpha float64, order int) *PME {
+	for d := range dims {
+		if dims[d] < order || box[d] <= 0 {
+			panic(fmt.Sprint("NewPME needs positive box lengths and at least order cells per side, got ", dims, " cells of ", box, " for order ", order))
+		}
+	}
+	if order < 3 || alpha <= 0 {
+		panic(fmt.Sprint("NewPME needs an order of at least 3 and a positive alpha, got ", order, " and ", alpha))
+	}
+	p := &PME{dims: dims, box: box, order: order}
+	spec := PlanSpec{Kind: R2C, Dims: dims[:], Dir: Forward, Flag: Estimate}
+	n, half := spec.Size()
+	p.grid = make([]float64, n)
+	p.spectrum = make([]complex128, half)
+	p.forward = spec.PlanDftR2C(p.grid, p.spectrum)
+	spec.Kind, spec.Dir = C2R, Backward
+	p.backward = spec.PlanDftC2R(p.spectrum, p.grid)
+	for d := range p.w {
+		p.w[d] = make([]float64, order)
+		p.dw[d] = make([]float64, order)
+	}
+
+	var moduli [3][]float64
+	for d := range moduli {
+		moduli[d] = bsplineModuli(dims[d], order)
+	}
+	volume := box[0] * box[1] * box[2]
+	p.influence = make([]float64, half)
+	forEachMode(dims[:], func(i int, k []int, weight int) {
+		m2 := 0.0
+		b := 1.0
+		for d, kd := range k {
+			m := float64(kd) / box[d]
+			m2 += m * m
+			b *= moduli[d][(kd+dims[d])%dims[d]]
+		}
+		if m2 > 0 {
+			p.influence[i] = b * math.Exp(-math.Pi*math.Pi*m2/(alpha*alpha)) / (math.Pi * volume * m2)
+		}
+	})
+	return p
+}
+
+// bsplineModuli returns |b(m)|² of the Euler exponential spline for each
+// frequency m of an n point grid and B-splines of the given order, zero
+// where it is undefined, at the Nyquist frequency of odd orders.
+func bsplineModuli(n, order int) []float64 {
+	// At the fraction 0, w[j] is M(j).
+	w := make([]float64, order)
+	bsplineWeights(0, w, nil)
+	b := make([]float64, n)
+	for m := range b {
+		var s complex128
+		for k := 0; k < order-1; k++ {
+			theta := 2 * math.Pi * float64(m*k) / float64(n)
+			s += complex(w[k+1]*math.Cos(theta), w[k+1]*math.Sin(theta))
+		}
+		if d := real(s)*real(s) + imag(s)*imag(s); d > 1e-10 {
+			b[m] = 1 / d
+		}
+	}
+	return b
+}
+
+// bsplineWeights sets w[j] to M(t+j), the cardinal B-spline of order
+// len(w) at t+j, for a fraction 0 <= t < 1, and dw[j] to its derivative
+// if dw isn't nil.
+func bsplineWeights(t float64, w, dw []float64) {
+	n := len(w)
+	for j := range w {
+		w[j] = 0
+	}
+	w[0] = 1
+	for k := 2; k <= n; k++ {
+		if k == n && dw != nil {
+			dw[0] = w[0]
+			for j := 1; j < n; j++ {
+				dw[j] = w[j] - w[j-1]
+			}
+		}
+		for j := k - 1; j >= 0; j-- {
+			x := t + float64(j)
+			v := x * w[j]
+			if j > 0 {
+				v += (float64(k) - x) * w[j-1]
+			}
+			w[j] = v / float64(k-1)
+		}
+	}
+}
+
+// Stencil calls fn with each grid point the charge at r touches, its
+// B-spline weight there and the gradient of that weight with respect to r.
+// Spread and Forces are built on it, and it serves equally for spreading
+// other quantities or interpolating the potential.
+func (p *PME) Stencil(r [3]float64, fn func(g [3]int, w float64, grad [3]float64)) {
+	var base [3]int
+	for d := range r {
+		u := r[d] / p.box[d] * float64(p.dims[d])
+		f := math.Floor(u)
+		base[d] = int(f)
+		bsplineWeights(u-f, p.w[d], p.dw[d])
+	}
+	n := p.order
+	var g [3]int
+	for a := 0; a < n; a++ {
+		g[0] = mod(base[0]-a, p.dims[0])
+		for b := 0; b < n; b++ {
+			g[1] = mod(base[1]-b, p.dims[1])
+			for c := 0; c < n; c++ {
+				g[2] = mod(base[2]-c, p.dims[2])
+				w := p.w[0][a] * p.w[1][b] * p.w[2][c]
+				grad := [3]float64{
+					p.dw[0][a] * p.w[1][b] * p.w[2][c] * float64(p.dims[0]) / p.box[0],
+					p.w[0][a] * p.dw[1][b] * p.w[2][c] * float64(p.dims[1]) / p.box[1],
+					p.w[0][a] * p.w[1][b] * p.dw[2][c] * float64(p.dims[2]) / p.box[2],
+				}
+				fn(g, w, grad)
+			}
+		}
+	}
+}
+
+func mod(i, n int) int {
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+func (p *PME) index(g [3]int) int {
+	return (g[0]*p.dims[1]+g[1])*p.dims[2] + g[2]
+}
+
+// Spread clears the grid and spreads the charges q at positions pos onto it.
+func (p *PME) Spread(pos [][3]float64, q []float64) {
+	if len(pos) != len(q) {
+		panic(fmt.Sprint("Spread needs a charge for each of ", len(pos), " positions, got ", len(q)))
+	}
+	for i := range p.grid {
+		p.grid[i] = 0
+	}
+	for i, r := range pos {
+		p.Stencil(r, func(g [3]int, w float64, grad [3]float64) {
+			p.grid[p.index(g)] += q[i] * w
+		})
+	}
+}
+
+// Solve replaces the spread charges with the potential they make and
+// returns the reciprocal space energy.
+func (p *PME) Solve() float64 {
+	p.forward.Execute()
+	energy := 0.0
+	forEachMode(p.dims[:], func(i int, k []int, weight int) {
+		v := p.spectrum[i]
+		energy += float64(weight) * p.influence[i] * (real(v)*real(v) + imag(v)*imag(v))
+		p.spectrum[i] = v * complex(p.influence[i], 0)
+	})
+	p.backward.Execute()
+	return energy / 2
+}
+
+// Forces returns the reciprocal space force on each of the charges q at
+// positions pos, interpolated from the potential Solve left on the grid.
+func (p *PME) Forces(pos [][3]float64, q []float64) [][3]float64 {
+	forces := make([][3]float64, len(pos))
+	for i, r := range pos {
+		p.Stencil(r, func(g [3]int, w float64, grad [3]float64) {
+			phi := p.grid[p.index(g)]
+			for d := range grad {
+				forces[i][d] -= q[i] * grad[d] * phi
+			}
+		})
+	}
+	return forces
+}
+
+// Potential returns a copy of the grid, the potential once Solve has run.
+func (p *PME) Potential() [][][]float64 {
+	return unflatten3d(p.dims[:], append([]float64(nil), p.grid...))
+}
+
+// Energy spreads the charges q at positions pos, solves, and returns the
+// reciprocal space energy and the force on each charge.
+func (p *PME) Energy(pos [][3]float64, q []float64) (float64, [][3]float64) {
+	p.Spread(pos, q)
+	e := p.Solve()
+	return e, p.Forces(pos, q)
+}
@@ -0,0 +1,74 @@
+package fftw
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+// ewaldReciprocal sums the reciprocal space Ewald energy of the charges q
+// at pos over every wavevector with components up to kmax.
+func ewaldReciprocal(box [3]float64, alpha float64, pos [][3]float64, q []float64, kmax int) float64 {
+	volume := box[0] * box[1] * box[2]
+	energy := 0.0
+	for a := -kmax; a <= kmax; a++ {
+		for b := -kmax; b <= kmax; b++ {
+			for c := -kmax; c <= kmax; c++ {
+				if a == 0 && b == 0 && c == 0 {
+					continue
+				}
+				m := [3]float64{float64(a) / box[0], float64(b) / box[1], float64(c) / box[2]}
+				m2 := m[0]*m[0] + m[1]*m[1] + m[2]*m[2]
+				var s complex128
+				for i, r := range pos {
+					s += complex(q[i], 0) * cmplx.Exp(complex(0, 2*math.Pi*(m[0]*r[0]+m[1]*r[1]+m[2]*r[2])))
+				}
+				energy += math.Exp(-math.Pi*math.Pi*m2/(alpha*alpha)) / m2 * real(s*cmplx.Conj(s))
+			}
+		}
+	}
+	return energy / (2 * math.Pi * volume)
+}
+
+func PMESpec(c gospec.Context) {
+	box := [3]float64{8, 9, 10}
+	alpha := 0.35
+	pos := [][3]float64{{1.2, 3.4, 5.6}, {4.1, 0.3, 9.2}, {6.6, 7.7, 2.5}}
+	q := []float64{1, -0.6, -0.4}
+
+	c.Specify("B-spline weights sum to one and their derivatives to zero.", func() {
+		w, dw := make([]float64, 5), make([]float64, 5)
+		bsplineWeights(0.3, w, dw)
+		c.Expect(sum(w), gospec.IsWithin(1e-12), 1.0)
+		c.Expect(sum(dw), gospec.IsWithin(1e-12), 0.0)
+	})
+
+	c.Specify("The reciprocal energy matches the Ewald sum.", func() {
+		p := NewPME([3]int{16, 16, 16}, box, alpha, 6)
+		e, _ := p.Energy(pos, q)
+		want := ewaldReciprocal(box, alpha, pos, q, 8)
+		c.Expect(e, gospec.IsWithin(1e-5*math.Abs(want)), want)
+	})
+
+	c.Specify("Forces are the gradient of the energy.", func() {
+		p := NewPME([3]int{16, 16, 16}, box, alpha, 6)
+		_, forces := p.Energy(pos, q)
+		net := [3]float64{}
+		for _, f := range forces {
+			for d := range f {
+				net[d] += f[d]
+			}
+		}
+		h := 1e-4
+		for d := 0; d < 3; d++ {
+			moved := append([][3]float64(nil), pos...)
+			moved[0][d] += h
+			up, _ := p.Energy(moved, q)
+			moved[0][d] -= 2 * h
+			down, _ := p.Energy(moved, q)
+			c.Expect(forces[0][d], gospec.IsWithin(1e-4), -(up-down)/(2*h))
+			c.Expect(net[d], gospec.IsWithin(1e-3), 0.0)
+		}
+	})
+}
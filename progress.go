@@ -0,0 +1,61 @@
+package fftw
+
+import (
+	"time"
+)
+
+// Progress reports how far a long job has got.
+type Progress struct {
+	// Done units of work out of Total are finished, where Total is 0 if it
+	// isn't known.
+	Done, Total int
+	Elapsed     time.Duration
+	// Remaining estimates the time left from the rate so far, and is 0
+	// until the first unit is done or if Total isn't known.
+	Remaining time.Duration
+}
+
+// A ProgressFunc is called as a job finishes each unit of work.  It is
+// called on the job's goroutine, so it should return quickly.
+type ProgressFunc func(p Progress)
+
+// ProgressChannel returns a ProgressFunc that sends each report to ch,
+// dropping reports that ch isn't ready for rather than stalling the job.
+func ProgressChannel(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// A progressTracker times a job and reports its progress to fn, if fn is
+// not nil.
+type progressTracker struct {
+	fn    ProgressFunc
+	total int
+	start time.Time
+	// base units were done before the job was resumed, and don't count
+	// towards the rate.
+	base int
+}
+
+func newProgressTracker(fn ProgressFunc, total int) progressTracker {
+	t := progressTracker{fn: fn, total: total}
+	if fn != nil {
+		t.start = time.Now()
+	}
+	return t
+}
+
+func (t progressTracker) report(done int) {
+	if t.fn == nil {
+		return
+	}
+	p := Progress{Done: done, Total: t.total, Elapsed: time.Since(t.start)}
+	if done > t.base && t.total > 0 {
+		p.Remaining = time.Duration(float64(p.Elapsed) / float64(done-t.base) * float64(t.total-done))
+	}
+	t.fn(p)
+}
@@ -0,0 +1,68 @@
+package fftw
+
+import (
+	"context"
+	"github.com/orfjackal/gospec/src/gospec"
+	"time"
+)
+
+func ProgressSpec(c gospec.Context) {
+	x := make([]float64, 100)
+	for i := range x {
+		x[i] = float64(i % 7)
+	}
+
+	c.Specify("STFTs report progress and match Transform.", func() {
+		s := NewSTFT(16, 8, Hann)
+		var reports []Progress
+		spectra, err := s.TransformContext(context.Background(), x, func(p Progress) {
+			reports = append(reports, p)
+		})
+		c.Expect(err, gospec.IsNil)
+		want := s.Transform(x)
+		c.Expect(len(spectra), gospec.Equals, len(want))
+		c.Expect(len(reports), gospec.Equals, len(want))
+		for i, p := range reports {
+			c.Expect(p.Done, gospec.Equals, i+1)
+			c.Expect(p.Total, gospec.Equals, len(want))
+		}
+		c.Expect(reports[len(reports)-1].Remaining, gospec.Equals, time.Duration(0))
+		for t := range want {
+			c.Expect(spectra[t], gospec.ContainsInOrder, want[t])
+		}
+	})
+
+	c.Specify("Cancelling an STFT stops it early.", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		spectra, err := NewSTFT(16, 8, Hann).TransformContext(ctx, x, func(p Progress) {
+			if p.Done == 3 {
+				cancel()
+			}
+		})
+		c.Expect(err, gospec.Equals, context.Canceled)
+		c.Expect(len(spectra), gospec.Equals, 3)
+	})
+
+	c.Specify("Graphs report progress over a channel and can be cancelled.", func() {
+		ch := make(chan Progress, 100)
+		n, err := NewGraph(16, SliceSource(x)).RunContext(context.Background(), ProgressChannel(ch))
+		c.Expect(err, gospec.IsNil)
+		c.Expect(n, gospec.Equals, 7)
+		c.Expect(len(ch), gospec.Equals, 7)
+		last := Progress{}
+		for len(ch) > 0 {
+			last = <-ch
+		}
+		c.Expect(last.Done, gospec.Equals, 7)
+		c.Expect(last.Total, gospec.Equals, 7)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		n, err = NewGraph(16, SliceSource(x)).RunContext(ctx, func(p Progress) {
+			if p.Done == 2 {
+				cancel()
+			}
+		})
+		c.Expect(err, gospec.Equals, context.Canceled)
+		c.Expect(n, gospec.Equals, 2)
+	})
+}
@@ -0,0 +1,169 @@
+package fftw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A PSDAccumulator estimates the power spectral density of a signal too
+// long to hold in memory with Welch's method, taking the signal a block at
+// a time.  Its state can be saved with MarshalBinary and restored with
+// UnmarshalBinary, so a monitoring job can restart without losing the
+// segments it has already averaged.
+type PSDAccumulator struct {
+	opts     WelchOptions
+	length   int
+	step     int
+	taper    []float64
+	pending  []float64
+	sum      []float64
+	segments int
+	frame    []float64
+	spectrum []complex128
+	plan     *Plan
+}
+
+// NewPSDAccumulator returns an empty accumulator.  Unlike WelchPSD, the
+// segment length isn't shortened to fit the signal, since the signal's
+// length isn't known in advance.  Segments may overlap but not leave gaps,
+// so opts.Overlap must not be negative.
+func NewPSDAccumulator(opts WelchOptions) *PSDAccumulator {
+	if opts.Overlap < 0 {
+		panic(fmt.Sprint("NewPSDAccumulator needs a non-negative overlap, got ", opts.Overlap))
+	}
+	a := &PSDAccumulator{opts: opts}
+	a.length = opts.SegmentLength
+	if a.length <= 0 {
+		a.length = 256
+	}
+	a.step = a.length - opts.Overlap
+	if a.step < 1 {
+		a.step = 1
+	}
+	w := opts.Window
+	if w == nil {
+		w = Hann
+	}
+	a.taper = Window1d(a.length, w)
+	a.sum = make([]float64, a.length/2+1)
+	a.frame = make([]float64, a.length)
+	a.spectrum = make([]complex128, a.length/2+1)
+	a.plan = PlanDftR2C1d(a.frame, a.spectrum, Estimate)
+	return a
+}
+
+// Write adds the samples x to the signal, averaging in every segment they
+// complete.
+func (a *PSDAccumulator) Write(x []float64) {
+	a.pending = append(a.pending, x...)
+	used := 0
+	for ; used+a.length <= len(a.pending); used += a.step {
+		for i := range a.frame {
+			a.frame[i] = a.pending[used+i] * a.taper[i]
+		}
+		a.plan.Execute()
+		for k, v := range a.spectrum {
+			a.sum[k] += real(v)*real(v) + imag(v)*imag(v)
+		}
+		a.segments++
+	}
+	a.pending = append(a.pending[:0], a.pending[used:]...)
+}
+
+// ReadFrom adds the samples read from r, little-endian float64s, until r
+// reaches EOF.  It returns the number of bytes read, and
+// io.ErrUnexpectedEOF if r ends part way through a sample.
+func (a *PSDAccumulator) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, 8*4096)
+	x := make([]float64, 0, 4096)
+	var total int64
+	have := 0
+	for {
+		n, err := r.Read(buf[have:])
+		total += int64(n)
+		have += n
+		whole := have / 8 * 8
+		x = x[:0]
+		for i := 0; i < whole; i += 8 {
+			x = append(x, math.Float64frombits(binary.LittleEndian.Uint64(buf[i:])))
+		}
+		a.Write(x)
+		have = copy(buf, buf[whole:have])
+		if err == io.EOF {
+			if have > 0 {
+				return total, io.ErrUnexpectedEOF
+			}
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Segments returns the number of segments averaged so far.
+func (a *PSDAccumulator) Segments() int {
+	return a.segments
+}
+
+// PSD returns the frequency of each bin and the power spectral density
+// averaged over every segment so far, scaled as WelchPSD scales it.
+func (a *PSDAccumulator) PSD() ([]float64, []float64) {
+	psd := make([]float64, len(a.sum))
+	if a.segments > 0 {
+		rate := a.opts.SampleRate
+		if rate <= 0 {
+			rate = 1
+		}
+		energy := 0.0
+		for _, w := range a.taper {
+			energy += w * w
+		}
+		for k, s := range a.sum {
+			psd[k] = s / (rate * energy * float64(a.segments))
+		}
+		OneSided(psd, a.length, PreserveEdges)
+	}
+	return a.opts.freqs(a.length), psd
+}
+
+// psdState is the serialized form of a PSDAccumulator.
+type psdState struct {
+	SegmentLength, Step int
+	Segments            int
+	Sum, Pending        []float64
+}
+
+// MarshalBinary returns the accumulator's state: its averages so far and
+// the samples it hasn't yet made a segment of.  The window isn't saved.
+func (a *PSDAccumulator) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(psdState{a.length, a.step, a.segments, a.sum, a.pending})
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary restores state saved by MarshalBinary into a, which must
+// have been made with the same options as the accumulator that saved it.
+func (a *PSDAccumulator) UnmarshalBinary(data []byte) error {
+	var s psdState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return err
+	}
+	if s.SegmentLength != a.length || s.Step != a.step || len(s.Sum) != len(a.sum) {
+		return fmt.Errorf("saved PSD state has segments of %d samples %d apart, expected %d samples %d apart", s.SegmentLength, s.Step, a.length, a.step)
+	}
+	a.segments = s.Segments
+	copy(a.sum, s.Sum)
+	a.pending = append(a.pending[:0], s.Pending...)
+	return nil
+}
+
+// Consume adds the valid samples of a graph's frame, so that an accumulator
+// can be the sink of a checkpointed Graph.
+func (a *PSDAccumulator) Consume(f *Frame) {
+	a.Write(f.Samples[:f.Valid])
+}
@@ -0,0 +1,72 @@
+package fftw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/orfjackal/gospec/src/gospec"
+	"io"
+	"math/rand"
+)
+
+func PSDAccumulatorSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(17))
+	x := make([]float64, 1000)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	opts := WelchOptions{SegmentLength: 64, Overlap: 32, SampleRate: 100}
+	wantFreqs, want := WelchPSD(x, opts)
+
+	c.Specify("Accumulating blocks matches WelchPSD on the whole signal.", func() {
+		a := NewPSDAccumulator(opts)
+		for s := 0; s < len(x); s += 77 {
+			e := s + 77
+			if e > len(x) {
+				e = len(x)
+			}
+			a.Write(x[s:e])
+		}
+		freqs, psd := a.PSD()
+		c.Expect(freqs, gospec.ContainsInOrder, wantFreqs)
+		for k := range psd {
+			c.Expect(psd[k], gospec.IsWithin(1e-12), want[k])
+		}
+	})
+
+	c.Specify("Accumulators reject negative overlaps.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		NewPSDAccumulator(WelchOptions{SegmentLength: 64, Overlap: -8})
+	})
+
+	c.Specify("Accumulators resume from saved state.", func() {
+		a := NewPSDAccumulator(opts)
+		a.Write(x[:500])
+		state, err := a.MarshalBinary()
+		c.Expect(err, gospec.IsNil)
+		b := NewPSDAccumulator(opts)
+		c.Expect(b.UnmarshalBinary(state), gospec.IsNil)
+		b.Write(x[500:])
+		c.Expect(b.Segments(), gospec.Equals, (len(x)-64)/32+1)
+		_, psd := b.PSD()
+		for k := range psd {
+			c.Expect(psd[k], gospec.IsWithin(1e-12), want[k])
+		}
+		other := NewPSDAccumulator(WelchOptions{SegmentLength: 128})
+		c.Expect(other.UnmarshalBinary(state), gospec.Not(gospec.IsNil))
+	})
+
+	c.Specify("Samples can be read from a stream.", func() {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, x)
+		a := NewPSDAccumulator(opts)
+		n, err := a.ReadFrom(&buf)
+		c.Expect(err, gospec.IsNil)
+		c.Expect(n, gospec.Equals, int64(8*len(x)))
+		_, psd := a.PSD()
+		for k := range psd {
+			c.Expect(psd[k], gospec.IsWithin(1e-12), want[k])
+		}
+		_, err = a.ReadFrom(bytes.NewReader([]byte{1, 2, 3}))
+		c.Expect(err, gospec.Equals, io.ErrUnexpectedEOF)
+	})
+}
@@ -0,0 +1,215 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// An R2RKind selects one of fftw's real-to-real transforms.
+type R2RKind int
+
+// The halfcomplex transforms R2HC and HC2R are a real DFT and its inverse,
+// with the spectrum packed into n reals.  DHT is the discrete Hartley
+// transform.  REDFTab are the DCTs and RODFTab the DSTs, where a and b say
+// whether the input and output are shifted by half a sample: REDFT10 is the
+// DCT-II, REDFT01 the DCT-III, and so on.
+var R2HC R2RKind = C.FFTW_R2HC
+var HC2R R2RKind = C.FFTW_HC2R
+var DHT R2RKind = C.FFTW_DHT
+var REDFT00 R2RKind = C.FFTW_REDFT00
+var REDFT01 R2RKind = C.FFTW_REDFT01
+var REDFT10 R2RKind = C.FFTW_REDFT10
+var REDFT11 R2RKind = C.FFTW_REDFT11
+var RODFT00 R2RKind = C.FFTW_RODFT00
+var RODFT01 R2RKind = C.FFTW_RODFT01
+var RODFT10 R2RKind = C.FFTW_RODFT10
+var RODFT11 R2RKind = C.FFTW_RODFT11
+
+// LogicalSize returns the size of the DFT that a transform of kind k on n
+// reals is equivalent to.  Like the other transforms, r2r transforms are
+// unnormalized, so a transform followed by its inverse multiplies the data
+// by LogicalSize.
+func (k R2RKind) LogicalSize(n int) int {
+	switch k {
+	case R2HC, HC2R, DHT:
+		return n
+	case REDFT00:
+		return 2 * (n - 1)
+	case RODFT00:
+		return 2 * (n + 1)
+	}
+	return 2 * n
+}
+
+// Inverse returns the kind whose transform inverts k's, up to LogicalSize.
+func (k R2RKind) Inverse() R2RKind {
+	switch k {
+	case R2HC:
+		return HC2R
+	case HC2R:
+		return R2HC
+	case REDFT01:
+		return REDFT10
+	case REDFT10:
+		return REDFT01
+	case RODFT01:
+		return RODFT10
+	case RODFT10:
+		return RODFT01
+	}
+	// The rest are their own inverses.
+	return k
+}
+
+func R2R1d(in, out []float64, kind R2RKind, flag Flag) {
+	p := PlanR2R1d(in, out, kind, flag)
+	p.Execute()
+}
+
+// PlanR2R1d plans the real-to-real transform of the given kind from in to
+// out, which must have the same length.
+func PlanR2R1d(in, out []float64, kind R2RKind, flag Flag) *Plan {
+	n := len(in)
+	if len(out) != n {
+		panic(fmt.Sprint("A real-to-real transform needs arrays of the same length, got ", n, " and ", len(out)))
+	}
+	checkR2RDims([]int{n}, []R2RKind{kind})
+	if !fitsInt(n) {
+		return planLarge1d(PlanGuruR2R([]IODim{{n, 1, 1}}, nil, in, out, []R2RKind{kind}, flag))
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_r2r_1d(C.int(n), fftw_in, fftw_out, C.fftw_r2r_kind(kind), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2R, Dims: []int{n}, R2R: []R2RKind{kind}}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// checkR2RDims panics unless there is a kind for each of dims, each long
+// enough for its kind.
+func checkR2RDims(dims []int, kinds []R2RKind) {
+	if len(kinds) != len(dims) {
+		panic(fmt.Sprint("A real-to-real transform of dimensions ", dims, " needs ", len(dims), " kinds, got ", len(kinds)))
+	}
+	for i, n := range dims {
+		if n < 1 || (kinds[i] == REDFT00 && n < 2) {
+			panic(fmt.Sprint("Can't plan a real-to-real transform of dimensions ", dims))
+		}
+	}
+}
+
+func R2R2d(in, out [][]float64, kinds []R2RKind, flag Flag) {
+	p := PlanR2R2d(in, out, kinds, flag)
+	p.Execute()
+}
+
+func R2R3d(in, out [][][]float64, kinds []R2RKind, flag Flag) {
+	p := PlanR2R3d(in, out, kinds, flag)
+	p.Execute()
+}
+
+func R2RNd(dims []int, in, out []float64, kinds []R2RKind, flag Flag) {
+	p := PlanR2RNd(dims, in, out, kinds, flag)
+	p.Execute()
+}
+
+// PlanR2R2d plans a real-to-real transform of the contiguous n0 x n1 arrays
+// in and out, whose kind along dimension i is kinds[i], such as a DCT down
+// the columns and a DST along the rows.
+func PlanR2R2d(in, out [][]float64, kinds []R2RKind, flag Flag) *Plan {
+	n0 := len(in)
+	n1 := len(in[0])
+	if len(out) != n0 || len(out[0]) != n1 {
+		panic(fmt.Sprint("A real-to-real transform needs arrays of the same shape, got ", n0, "x", n1, " and ", len(out), "x", len(out[0])))
+	}
+	checkR2RDims([]int{n0, n1}, kinds)
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0][0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0][0]))
+	planner.Lock()
+	p := C.fftw_plan_r2r_2d(cInt(n0), cInt(n1), fftw_in, fftw_out, C.fftw_r2r_kind(kinds[0]), C.fftw_r2r_kind(kinds[1]), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2R, Dims: []int{n0, n1}, R2R: append([]R2RKind(nil), kinds...)}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanR2R3d is the three dimensional PlanR2R2d.
+func PlanR2R3d(in, out [][][]float64, kinds []R2RKind, flag Flag) *Plan {
+	n0 := len(in)
+	n1 := len(in[0])
+	n2 := len(in[0][0])
+	if len(out) != n0 || len(out[0]) != n1 || len(out[0][0]) != n2 {
+		panic(fmt.Sprint("A real-to-real transform needs arrays of the same shape, got ", n0, "x", n1, "x", n2, " and ", len(out), "x", len(out[0]), "x", len(out[0][0])))
+	}
+	checkR2RDims([]int{n0, n1, n2}, kinds)
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0][0][0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0][0][0]))
+	planner.Lock()
+	p := C.fftw_plan_r2r_3d(cInt(n0), cInt(n1), cInt(n2), fftw_in, fftw_out, C.fftw_r2r_kind(kinds[0]), C.fftw_r2r_kind(kinds[1]), C.fftw_r2r_kind(kinds[2]), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2R, Dims: []int{n0, n1, n2}, R2R: append([]R2RKind(nil), kinds...)}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanR2RNd plans a real-to-real transform of any rank on the row-major
+// arrays in and out, whose dimensions are dims.
+func PlanR2RNd(dims []int, in, out []float64, kinds []R2RKind, flag Flag) *Plan {
+	if len(dims) == 0 {
+		panic("PlanR2RNd needs at least one dimension")
+	}
+	checkR2RDims(dims, kinds)
+	size := 1
+	n := make([]C.int, len(dims))
+	k := make([]C.fftw_r2r_kind, len(dims))
+	for i, d := range dims {
+		size *= d
+		n[i] = cInt(d)
+		k[i] = C.fftw_r2r_kind(kinds[i])
+	}
+	if len(in) != size || len(out) != size {
+		panic(fmt.Sprint("Dimensions ", dims, " need arrays of length ", size, ", got ", len(in), " and ", len(out)))
+	}
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_r2r(C.int(len(n)), &n[0], fftw_in, fftw_out, &k[0], planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2R, Dims: append([]int(nil), dims...), R2R: append([]R2RKind(nil), kinds...)}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+func Dht1d(in, out []float64, flag Flag) {
+	p := PlanDht1d(in, out, flag)
+	p.Execute()
+}
+
+func Dht2d(in, out [][]float64, flag Flag) {
+	p := PlanDht2d(in, out, flag)
+	p.Execute()
+}
+
+func Dht3d(in, out [][][]float64, flag Flag) {
+	p := PlanDht3d(in, out, flag)
+	p.Execute()
+}
+
+// PlanDht1d plans the discrete Hartley transform
+// out[k] = sum(in[j]*cas(2*pi*j*k/n)), where cas(t) = cos(t)+sin(t), which
+// takes real input to real output and is its own inverse up to a factor of
+// n.
+func PlanDht1d(in, out []float64, flag Flag) *Plan {
+	return PlanR2R1d(in, out, DHT, flag)
+}
+
+// PlanDht2d plans the Hartley transform along both dimensions.  Like fftw's,
+// this is the product of one dimensional transforms, with kernel
+// cas(a)*cas(b) rather than the cas(a+b) some texts use, but it is just as
+// much its own inverse, up to a factor of n0*n1.
+func PlanDht2d(in, out [][]float64, flag Flag) *Plan {
+	return PlanR2R2d(in, out, []R2RKind{DHT, DHT}, flag)
+}
+
+// PlanDht3d plans the Hartley transform along all three dimensions, in the
+// product form described by PlanDht2d.
+func PlanDht3d(in, out [][][]float64, flag Flag) *Plan {
+	return PlanR2R3d(in, out, []R2RKind{DHT, DHT, DHT}, flag)
+}
@@ -0,0 +1,206 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func R2R1dSpec(c gospec.Context) {
+	n := 8
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = float64(i*i%5) - 1.5
+	}
+	kinds := []R2RKind{R2HC, HC2R, DHT, REDFT00, REDFT01, REDFT10, REDFT11, RODFT00, RODFT01, RODFT10, RODFT11}
+
+	c.Specify("Real-to-real transforms invert up to their logical size.", func() {
+		for _, k := range kinds {
+			y := make([]float64, n)
+			back := make([]float64, n)
+			p := PlanR2R1d(x, y, k, Estimate)
+			c.Expect(p.Geometry().Kind, gospec.Equals, R2R)
+			c.Expect(p.Geometry().R2R, gospec.ContainsInOrder, []R2RKind{k})
+			p.Execute()
+			R2R1d(y, back, k.Inverse(), Estimate)
+			for i := range x {
+				c.Expect(back[i]/float64(k.LogicalSize(n)), gospec.IsWithin(1e-9), x[i])
+			}
+		}
+	})
+
+	c.Specify("REDFT10 is the unnormalized DCT-II.", func() {
+		y := make([]float64, n)
+		R2R1d(x, y, REDFT10, Estimate)
+		for k := range y {
+			sum := 0.0
+			for j, v := range x {
+				sum += 2 * v * math.Cos(math.Pi*float64(k)*(float64(j)+0.5)/float64(n))
+			}
+			c.Expect(y[k], gospec.IsWithin(1e-9), sum)
+		}
+	})
+
+	c.Specify("DHT is the discrete Hartley transform.", func() {
+		y := make([]float64, n)
+		R2R1d(x, y, DHT, Estimate)
+		for k := range y {
+			sum := 0.0
+			for j, v := range x {
+				a := 2 * math.Pi * float64(j*k) / float64(n)
+				sum += v * (math.Cos(a) + math.Sin(a))
+			}
+			c.Expect(y[k], gospec.IsWithin(1e-9), sum)
+		}
+	})
+
+	c.Specify("Plans of different kinds have different geometries.", func() {
+		y := make([]float64, n)
+		g1 := PlanR2R1d(x, y, REDFT10, Estimate).Geometry()
+		g2 := PlanR2R1d(x, y, REDFT01, Estimate).Geometry()
+		c.Expect(g1.Equal(g2), gospec.IsFalse)
+		c.Expect(g1.Key() == g2.Key(), gospec.IsFalse)
+		c.Expect(g1.Equal(PlanR2R1d(x, y, REDFT10, Estimate).Geometry()), gospec.IsTrue)
+	})
+}
+
+func R2RNdSpec(c gospec.Context) {
+	n0, n1, n2 := 3, 4, 5
+	data := make([]float64, n0*n1*n2)
+	for i := range data {
+		data[i] = float64(i*7%11) - 5
+	}
+	kinds := []R2RKind{REDFT10, RODFT00, DHT}
+
+	c.Specify("Multi-dimensional r2r transforms apply each kind along its axis.", func() {
+		out := make([]float64, len(data))
+		R2RNd([]int{n0, n1, n2}, data, out, kinds, Estimate)
+		// The same transform, one axis at a time.
+		want := append([]float64(nil), data...)
+		dims := []int{n0, n1, n2}
+		strides := []int{n1 * n2, n2, 1}
+		for axis, k := range kinds {
+			n := dims[axis]
+			line := make([]float64, n)
+			res := make([]float64, n)
+			p := PlanR2R1d(line, res, k, Estimate)
+			for start := range want {
+				if start/strides[axis]%n != 0 {
+					continue
+				}
+				for i := range line {
+					line[i] = want[start+i*strides[axis]]
+				}
+				p.Execute()
+				for i := range res {
+					want[start+i*strides[axis]] = res[i]
+				}
+			}
+		}
+		for i := range out {
+			c.Expect(out[i], gospec.IsWithin(1e-9), want[i])
+		}
+
+		// The 3d form agrees with the rank-N one.
+		in3 := make([][][]float64, n0)
+		out3 := make([][][]float64, n0)
+		flat := make([]float64, len(data))
+		for i := range in3 {
+			in3[i] = make([][]float64, n1)
+			out3[i] = make([][]float64, n1)
+			for j := range in3[i] {
+				k := (i*n1 + j) * n2
+				in3[i][j] = data[k : k+n2]
+				out3[i][j] = flat[k : k+n2]
+			}
+		}
+		R2R3d(in3, out3, kinds, Estimate)
+		for i := range flat {
+			c.Expect(flat[i], gospec.IsWithin(1e-9), out[i])
+		}
+	})
+
+	c.Specify("2d r2r transforms invert up to their logical size.", func() {
+		x := alloc2dReal(4, 6)
+		y := alloc2dReal(4, 6)
+		for i := range x {
+			for j := range x[i] {
+				x[i][j] = float64(i*j%5) + 0.5
+			}
+		}
+		k2 := []R2RKind{REDFT10, RODFT11}
+		R2R2d(x, y, k2, Estimate)
+		R2R2d(y, y, []R2RKind{k2[0].Inverse(), k2[1].Inverse()}, Estimate)
+		scale := float64(REDFT10.LogicalSize(4) * RODFT11.LogicalSize(6))
+		for i := range x {
+			for j := range x[i] {
+				c.Expect(y[i][j]/scale, gospec.IsWithin(1e-9), x[i][j])
+			}
+		}
+	})
+
+	c.Specify("Each dimension needs a kind.", func() {
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		out := make([]float64, len(data))
+		PlanR2RNd([]int{n0, n1, n2}, data, out, kinds[:2], Estimate)
+	})
+}
+
+func DhtSpec(c gospec.Context) {
+	c.Specify("The 1d DHT is its own inverse up to n.", func() {
+		x := []float64{1, 4, -2, 8, 5, -7}
+		y := make([]float64, len(x))
+		back := make([]float64, len(x))
+		Dht1d(x, y, Estimate)
+		Dht1d(y, back, Estimate)
+		for i := range x {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-9), x[i])
+		}
+	})
+
+	c.Specify("The 2d DHT is the product of 1d transforms and its own inverse.", func() {
+		x := alloc2dReal(3, 4)
+		for i := range x {
+			for j := range x[i] {
+				x[i][j] = float64(i*4+j*j) - 3
+			}
+		}
+		y := alloc2dReal(3, 4)
+		Dht2d(x, y, Estimate)
+		cas := func(t float64) float64 { return math.Cos(t) + math.Sin(t) }
+		for k0 := range y {
+			for k1 := range y[k0] {
+				sum := 0.0
+				for j0 := range x {
+					for j1 := range x[j0] {
+						sum += x[j0][j1] * cas(2*math.Pi*float64(j0*k0)/3) * cas(2*math.Pi*float64(j1*k1)/4)
+					}
+				}
+				c.Expect(y[k0][k1], gospec.IsWithin(1e-9), sum)
+			}
+		}
+		back := alloc2dReal(3, 4)
+		PlanDht2d(y, back, Estimate).Execute()
+		for i := range x {
+			for j := range x[i] {
+				c.Expect(back[i][j]/12, gospec.IsWithin(1e-9), x[i][j])
+			}
+		}
+	})
+
+	c.Specify("The 3d DHT is its own inverse.", func() {
+		data := make([]float64, 2*3*2)
+		for i := range data {
+			data[i] = float64(i*i%5) - 2
+		}
+		in := unflatten3d([]int{2, 3, 2}, data)
+		out := unflatten3d([]int{2, 3, 2}, make([]float64, len(data)))
+		back := make([]float64, len(data))
+		Dht3d(in, out, Estimate)
+		Dht3d(out, unflatten3d([]int{2, 3, 2}, back), Estimate)
+		for i := range data {
+			c.Expect(back[i]/12, gospec.IsWithin(1e-9), data[i])
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package fftw
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// A Colormap maps a level between 0 and 1 to a colour.
+type Colormap func(v float64) color.Color
+
+// Grayscale maps levels from black to white.
+func Grayscale(v float64) color.Color {
+	return color.Gray{uint8(math.Floor(clamp01(v)*255 + 0.5))}
+}
+
+// Viridis and Inferno approximate the matplotlib colormaps of the same names,
+// which are perceptually uniform and readable in greyscale.
+var (
+	Viridis = gradient(
+		color.RGBA{68, 1, 84, 255}, color.RGBA{59, 82, 139, 255}, color.RGBA{33, 145, 140, 255},
+		color.RGBA{94, 201, 98, 255}, color.RGBA{253, 231, 37, 255})
+	Inferno = gradient(
+		color.RGBA{0, 0, 4, 255}, color.RGBA{87, 16, 110, 255}, color.RGBA{188, 55, 84, 255},
+		color.RGBA{249, 142, 9, 255}, color.RGBA{252, 255, 164, 255})
+)
+
+// gradient returns a Colormap that interpolates linearly between evenly
+// spaced stops.
+func gradient(stops ...color.RGBA) Colormap {
+	return func(v float64) color.Color {
+		x := clamp01(v) * float64(len(stops)-1)
+		i := int(x)
+		if i >= len(stops)-1 {
+			return stops[len(stops)-1]
+		}
+		f := x - float64(i)
+		a, b := stops[i], stops[i+1]
+		lerp := func(p, q uint8) uint8 {
+			return uint8(math.Floor(float64(p)*(1-f) + float64(q)*f + 0.5))
+		}
+		return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 255}
+	}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 || math.IsNaN(v) {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// RenderOptions controls how RenderSpectrogram maps levels to colours.
+type RenderOptions struct {
+	// Colormap colours the levels, Grayscale if nil.
+	Colormap Colormap
+	// DynamicRange, if positive, is the range in dB below the loudest level
+	// that is shown; anything quieter is drawn as the bottom of the scale.
+	DynamicRange float64
+	// Gamma, if positive, is applied to the scaled levels, so values above
+	// one compress the quiet end of the scale and values below one expand it.
+	Gamma float64
+	// Equalize replaces each level by its rank among all of the levels, so
+	// that every colour is used equally often.
+	Equalize bool
+}
+
+// SpectrogramDB returns the power of each bin of spectra in dB, with
+// silent bins at -Inf.
+func SpectrogramDB(spectra [][]complex128) [][]float64 {
+	db := make([][]float64, len(spectra))
+	for t, frame := range spectra {
+		db[t] = make([]float64, len(frame))
+		for k, v := range frame {
+			db[t][k] = 10 * math.Log10(real(v)*real(v)+imag(v)*imag(v))
+		}
+	}
+	return db
+}
+
+// RenderSpectrogram draws db, one frame of levels in dB per row as returned
+// by SpectrogramDB, as an image with time running left to right and
+// frequency increasing upwards.
+func RenderSpectrogram(db [][]float64, opts RenderOptions) image.Image {
+	frames := len(db)
+	bins := 0
+	if frames > 0 {
+		bins = len(db[0])
+	}
+	img := image.NewRGBA(image.Rect(0, 0, frames, bins))
+	if frames == 0 || bins == 0 {
+		return img
+	}
+	cmap := opts.Colormap
+	if cmap == nil {
+		cmap = Grayscale
+	}
+
+	max, min := math.Inf(-1), math.Inf(1)
+	for _, frame := range db {
+		for _, v := range frame {
+			max = math.Max(max, v)
+			if !math.IsInf(v, -1) {
+				min = math.Min(min, v)
+			}
+		}
+	}
+	if opts.DynamicRange > 0 && max-opts.DynamicRange > min {
+		min = max - opts.DynamicRange
+	}
+	level := func(v float64) float64 {
+		if max <= min {
+			// Constant levels are drawn at the top of the scale, unless
+			// everything is silent.
+			if math.IsInf(max, -1) {
+				return 0
+			}
+			return 1
+		}
+		return (math.Max(v, min) - min) / (max - min)
+	}
+	if opts.Equalize {
+		// Levels are replaced by their rank, ties sharing the highest.
+		sorted := make([]float64, 0, frames*bins)
+		for _, frame := range db {
+			for _, v := range frame {
+				sorted = append(sorted, math.Max(v, min))
+			}
+		}
+		sort.Float64s(sorted)
+		level = func(v float64) float64 {
+			if len(sorted) < 2 {
+				return 1
+			}
+			rank := sort.Search(len(sorted), func(i int) bool { return sorted[i] > math.Max(v, min) })
+			return float64(rank-1) / float64(len(sorted)-1)
+		}
+	}
+
+	for t, frame := range db {
+		for k, v := range frame {
+			l := level(v)
+			if opts.Gamma > 0 {
+				l = math.Pow(l, opts.Gamma)
+			}
+			img.Set(t, bins-1-k, cmap(l))
+		}
+	}
+	return img
+}
@@ -0,0 +1,52 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"image/color"
+	"math"
+)
+
+func RenderSpectrogramSpec(c gospec.Context) {
+	db := [][]float64{
+		{-100, -60, -20},
+		{-40, 0, math.Inf(-1)},
+	}
+	gray := func(x, y int, opts RenderOptions) uint8 {
+		return color.GrayModel.Convert(RenderSpectrogram(db, opts).At(x, y)).(color.Gray).Y
+	}
+
+	c.Specify("Time runs along x and frequency up y.", func() {
+		b := RenderSpectrogram(db, RenderOptions{}).Bounds()
+		c.Expect(b.Dx(), gospec.Equals, 2)
+		c.Expect(b.Dy(), gospec.Equals, 3)
+		c.Expect(gray(1, 1, RenderOptions{}), gospec.Equals, uint8(255))
+		c.Expect(gray(1, 0, RenderOptions{}), gospec.Equals, uint8(0))
+	})
+
+	c.Specify("Levels below the dynamic range are clipped.", func() {
+		opts := RenderOptions{DynamicRange: 50}
+		c.Expect(gray(0, 2, opts), gospec.Equals, uint8(0))
+		c.Expect(gray(0, 1, opts), gospec.Equals, uint8(0))
+		c.Expect(gray(1, 2, opts), gospec.Equals, uint8(51))
+		c.Expect(gray(0, 0, opts), gospec.Equals, uint8(153))
+	})
+
+	c.Specify("Gamma reshapes the scale.", func() {
+		opts := RenderOptions{DynamicRange: 50, Gamma: 2}
+		c.Expect(gray(0, 0, opts), gospec.Equals, uint8(92))
+	})
+
+	c.Specify("Equalization uses the scale evenly.", func() {
+		even := [][]float64{{0, 1, 2, 30, 60, 1000}}
+		img := RenderSpectrogram(even, RenderOptions{Equalize: true})
+		for k := range even[0] {
+			y := color.GrayModel.Convert(img.At(0, 5-k)).(color.Gray).Y
+			c.Expect(y, gospec.Equals, uint8(math.Floor(float64(k)*255/5+0.5)))
+		}
+	})
+
+	c.Specify("Colormaps run from their first to their last stop.", func() {
+		c.Expect(Viridis(0), gospec.Equals, color.Color(color.RGBA{68, 1, 84, 255}))
+		c.Expect(Inferno(1), gospec.Equals, color.Color(color.RGBA{252, 255, 164, 255}))
+	})
+}
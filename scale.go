@@ -0,0 +1,84 @@
+package fftw
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// asReals returns the real and imaginary parts of a as one []float64 that
+// shares a's memory.
+func asReals(a []complex128) []float64 {
+	var r []float64
+	header := (*reflect.SliceHeader)(unsafe.Pointer(&r))
+	header.Data = (*reflect.SliceHeader)(unsafe.Pointer(&a)).Data
+	header.Len = 2 * len(a)
+	header.Cap = 2 * len(a)
+	return r
+}
+
+// ScaleReal multiplies every element of a by factor, in place.
+func ScaleReal(a []float64, factor float64) {
+	// Unrolled so that the compiler keeps four independent multiplies in
+	// flight.
+	n := len(a) &^ 3
+	for i := 0; i < n; i += 4 {
+		b := a[i : i+4 : i+4]
+		b[0] *= factor
+		b[1] *= factor
+		b[2] *= factor
+		b[3] *= factor
+	}
+	for i := n; i < len(a); i++ {
+		a[i] *= factor
+	}
+}
+
+// Scale multiplies every element of a by the real factor, in place.  This is
+// half the work of multiplying by complex(factor, 0).
+func Scale(a []complex128, factor float64) {
+	ScaleReal(asReals(a), factor)
+}
+
+// Normalization is a convention for scaling transforms.  fftw doesn't
+// normalize, so a forward transform followed by a backward one multiplies
+// the data by its length n.
+type Normalization int
+
+const (
+	// Unnormalized leaves the data as fftw produced it.
+	Unnormalized Normalization = iota
+	// ByN divides by n, which undoes the scaling of a round trip when
+	// applied after the backward transform.
+	ByN
+	// Orthonormal divides by sqrt(n), which applied after both the forward
+	// and the backward transform makes each of them unitary.
+	Orthonormal
+)
+
+func (mode Normalization) factor(n int) float64 {
+	switch mode {
+	case ByN:
+		return 1 / float64(n)
+	case Orthonormal:
+		return 1 / math.Sqrt(float64(n))
+	}
+	return 1
+}
+
+// Normalize scales a, in place, according to mode, taking n to be len(a).
+// For multi-dimensional data pass the flat backing array, such as
+// x[0][:n0*n1] for x from Alloc2d.
+func Normalize(a []complex128, mode Normalization) {
+	if mode != Unnormalized && len(a) > 0 {
+		Scale(a, mode.factor(len(a)))
+	}
+}
+
+// NormalizeReal is Normalize for the real output of complex-to-real
+// transforms.
+func NormalizeReal(a []float64, mode Normalization) {
+	if mode != Unnormalized && len(a) > 0 {
+		ScaleReal(a, mode.factor(len(a)))
+	}
+}
@@ -0,0 +1,45 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func ScaleSpec(c gospec.Context) {
+	c.Specify("Scale multiplies complex arrays by a real factor.", func() {
+		a := Alloc1d(11)
+		for i := range a {
+			a[i] = complex(float64(i), float64(-2*i))
+		}
+		Scale(a, 0.5)
+		for i := range a {
+			c.Expect(a[i], gospec.Equals, complex(float64(i)/2, float64(-i)))
+		}
+	})
+
+	c.Specify("ScaleReal multiplies real arrays.", func() {
+		a := []float64{1, 2, 3, 4, 5, 6, 7}
+		ScaleReal(a, 3)
+		c.Expect(a, gospec.ContainsInOrder, []float64{3, 6, 9, 12, 15, 18, 21})
+	})
+
+	c.Specify("Normalize divides by n or sqrt(n).", func() {
+		a := []complex128{4, 4, 4, 4}
+		Normalize(a, Unnormalized)
+		c.Expect(a[0], gospec.Equals, complex128(4))
+		Normalize(a, Orthonormal)
+		c.Expect(a[1], gospec.Equals, complex128(2))
+		Normalize(a, ByN)
+		c.Expect(a[3], gospec.Equals, complex(0.5, 0))
+		r := []float64{9, 9, 9, 9, 9, 9, 9, 9, 9}
+		NormalizeReal(r, ByN)
+		c.Expect(r[8], gospec.IsWithin(1e-12), 1.0)
+		NormalizeReal(r, Orthonormal)
+		c.Expect(r[0], gospec.IsWithin(1e-12), 1/math.Sqrt(9))
+	})
+
+	c.Specify("Normalizing empty arrays is harmless.", func() {
+		Normalize(nil, ByN)
+		NormalizeReal(nil, Orthonormal)
+	})
+}
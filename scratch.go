@@ -0,0 +1,84 @@
+package fftw
+
+import (
+	"math/bits"
+	"sync"
+	"unsafe"
+)
+
+// scratchRegistry lends out zeroed scratch buffers, 16 byte aligned for
+// fftw's SIMD code, pooled by size so that the convolver, STFT and
+// resampler share their temporary memory rather than each allocating its
+// own for every call.  Sizes are rounded up to powers of two, so there are
+// only as many pools as bits in an int however many sizes are asked for.
+// It is safe for concurrent use.
+type scratchRegistry struct {
+	// pools[k] holds buffers of capacity 1<<k.
+	pools [bits.UintSize]sync.Pool
+}
+
+var scratch scratchRegistry
+
+// alignedReals returns a buffer of n float64s from the Go heap, 16 byte
+// aligned like those from fftw_malloc.
+func alignedReals(n int) []float64 {
+	// One spare element leaves room to align the buffer.
+	b := make([]float64, n+1)
+	if uintptr(unsafe.Pointer(&b[0]))%16 != 0 {
+		b = b[1:]
+	}
+	return b[:n:n]
+}
+
+// reals borrows a buffer of n float64s, which must be given back with
+// releaseReals once it is no longer used.
+func (r *scratchRegistry) reals(n int) []float64 {
+	if n == 0 {
+		return nil
+	}
+	k := bucket(n)
+	p, ok := r.pools[k].Get().(*[]float64)
+	if !ok {
+		return alignedReals(1 << k)[:n:n]
+	}
+	b := (*p)[:n:n]
+	for i := range b {
+		b[i] = 0
+	}
+	return b
+}
+
+// bucket returns the index of the pool lending buffers of n float64s, that
+// of the smallest power of two holding them.
+func bucket(n int) int {
+	return bits.Len(uint(n - 1))
+}
+
+func (r *scratchRegistry) releaseReals(b []float64) {
+	if len(b) == 0 {
+		return
+	}
+	// reals caps its buffers at the length asked for; the whole of the
+	// power of two behind them goes back.
+	k := bucket(len(b))
+	b = unsafe.Slice(&b[0], 1<<k)
+	r.pools[k].Put(&b)
+}
+
+// complexes borrows a buffer of n complex128s, which must be given back with
+// releaseComplexes.  It shares the pools of float64 buffers of twice the
+// length.
+func (r *scratchRegistry) complexes(n int) []complex128 {
+	if n == 0 {
+		return nil
+	}
+	b := r.reals(2 * n)
+	return unsafe.Slice((*complex128)(unsafe.Pointer(&b[0])), n)
+}
+
+func (r *scratchRegistry) releaseComplexes(b []complex128) {
+	if len(b) == 0 {
+		return
+	}
+	r.releaseReals(unsafe.Slice((*float64)(unsafe.Pointer(&b[0])), 2*len(b)))
+}
@@ -0,0 +1,57 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"unsafe"
+)
+
+func ScratchSpec(c gospec.Context) {
+	c.Specify("Scratch buffers are aligned, sized and zeroed.", func() {
+		for n := 1; n < 40; n++ {
+			r := scratch.reals(n)
+			x := scratch.complexes(n)
+			c.Expect(len(r), gospec.Equals, n)
+			c.Expect(cap(r), gospec.Equals, n)
+			c.Expect(len(x), gospec.Equals, n)
+			c.Expect(uintptr(unsafe.Pointer(&r[0]))%16, gospec.Equals, uintptr(0))
+			c.Expect(uintptr(unsafe.Pointer(&x[0]))%16, gospec.Equals, uintptr(0))
+			for i := range r {
+				c.Expect(r[i], gospec.Equals, 0.0)
+				r[i] = 1
+			}
+			for i := range x {
+				c.Expect(x[i], gospec.Equals, complex(0, 0))
+				x[i] = 1
+			}
+			scratch.releaseReals(r)
+			scratch.releaseComplexes(x)
+		}
+		// Reused buffers come back zeroed.
+		for n := 1; n < 40; n++ {
+			for _, v := range scratch.reals(2 * n) {
+				c.Expect(v, gospec.Equals, 0.0)
+			}
+		}
+	})
+
+	c.Specify("Scratch buffers are pooled by power of two.", func() {
+		for n, k := range map[int]int{1: 0, 2: 1, 3: 2, 4: 2, 5: 3, 1024: 10, 1025: 11} {
+			c.Expect(bucket(n), gospec.Equals, k)
+		}
+		// A buffer given back at one length comes back zeroed at another.
+		r := scratch.reals(5)
+		for i := range r {
+			r[i] = 1
+		}
+		scratch.releaseReals(r)
+		for _, v := range scratch.reals(8) {
+			c.Expect(v, gospec.Equals, 0.0)
+		}
+	})
+
+	c.Specify("Empty scratch buffers are nil.", func() {
+		c.Expect(len(scratch.reals(0)), gospec.Equals, 0)
+		scratch.releaseReals(nil)
+		scratch.releaseComplexes(nil)
+	})
+}
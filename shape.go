@@ -0,0 +1,117 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+)
+
+// maxShapeCandidates bounds the number of shapes SuggestShape will plan.
+const maxShapeCandidates = 512
+
+// smooth reports whether n has no prime factors larger than 7, which are
+// the sizes fftw handles fastest.
+func smooth(n int) bool {
+	for _, f := range []int{2, 3, 5, 7} {
+		for n%f == 0 {
+			n /= f
+		}
+	}
+	return n == 1
+}
+
+// shapeCost returns fftw's estimate of the cost of transforming an array
+// with dimensions n, without executing anything, or +Inf if fftw can't plan
+// it.  buf need only be aligned; an estimating planner never touches it.
+func shapeCost(n []int, buf unsafe.Pointer) float64 {
+	nn := make([]C.int, len(n))
+	for i := range n {
+		nn[i] = C.int(n[i])
+	}
+	fftw_buf := (*C.fftw_complex)(buf)
+	planner.Lock()
+	p := C.fftw_plan_dft(C.int(len(nn)), &nn[0], fftw_buf, fftw_buf, C.FFTW_FORWARD, C.FFTW_ESTIMATE)
+	planner.Unlock()
+	if p == nil {
+		return math.Inf(1)
+	}
+	defer func() {
+		planner.Lock()
+		C.fftw_destroy_plan(p)
+		planner.Unlock()
+	}()
+	return float64(C.fftw_cost(p))
+}
+
+// SuggestShape returns the dimensions, each padded by at most a fraction
+// maxPad of its original size, that fftw estimates will be cheapest to
+// transform.  For example SuggestShape([]int{1021}, 0.1) might return
+// []int{1024}, since 1021 is prime.
+func SuggestShape(dims []int, maxPad float64) []int {
+	if len(dims) == 0 {
+		return nil
+	}
+	if maxPad < 0 {
+		maxPad = 0
+	}
+	// The candidates for each dimension are its original size and every
+	// 7-smooth size within the padding budget.
+	candidates := make([][]int, len(dims))
+	for i, n := range dims {
+		limit := int(math.Floor(float64(n) * (1 + maxPad)))
+		candidates[i] = []int{n}
+		for m := n + 1; m <= limit; m++ {
+			if smooth(m) {
+				candidates[i] = append(candidates[i], m)
+			}
+		}
+	}
+
+	// Estimating plans never touch their arrays, only look at their
+	// alignment, so a single small buffer does for every shape.
+	buf := fftwMalloc(1, 16)
+	defer C.fftw_free(buf)
+
+	// If there are too many combinations, keep the cheapest few sizes in
+	// each dimension, judged by their 1d cost per element.
+	keep := int(math.Pow(maxShapeCandidates, 1/float64(len(dims))))
+	if keep < 1 {
+		keep = 1
+	}
+	for i := range candidates {
+		if len(candidates[i]) <= keep {
+			continue
+		}
+		c := candidates[i]
+		cost := make(map[int]float64, len(c))
+		for _, m := range c {
+			cost[m] = shapeCost([]int{m}, buf) / float64(m)
+		}
+		sort.Slice(c, func(a, b int) bool { return cost[c[a]] < cost[c[b]] })
+		candidates[i] = c[:keep]
+	}
+
+	best := append([]int(nil), dims...)
+	bestCost := shapeCost(best, buf)
+	shape := make([]int, len(dims))
+	var search func(d int)
+	search = func(d int) {
+		if d == len(dims) {
+			cost := shapeCost(shape, buf)
+			if cost < bestCost {
+				bestCost = cost
+				copy(best, shape)
+			}
+			return
+		}
+		for _, m := range candidates[d] {
+			shape[d] = m
+			search(d + 1)
+		}
+	}
+	search(0)
+	return best
+}
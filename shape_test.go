@@ -0,0 +1,28 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func SuggestShapeSpec(c gospec.Context) {
+	c.Specify("Suggested shapes stay within the padding budget.", func() {
+		dims := []int{97, 61, 30}
+		shape := SuggestShape(dims, 0.25)
+		c.Expect(len(shape), gospec.Equals, len(dims))
+		for i := range dims {
+			c.Expect(shape[i] >= dims[i], gospec.IsTrue)
+			c.Expect(float64(shape[i]) <= float64(dims[i])*1.25, gospec.IsTrue)
+		}
+	})
+
+	c.Specify("Awkward prime sizes get padded.", func() {
+		shape := SuggestShape([]int{97}, 0.1)
+		c.Expect(shape[0] > 97, gospec.IsTrue)
+		c.Expect(smooth(shape[0]), gospec.IsTrue)
+	})
+
+	c.Specify("Sizes that are already fast are left alone.", func() {
+		c.Expect(SuggestShape([]int{128}, 0.1)[0], gospec.Equals, 128)
+		c.Expect(SuggestShape([]int{97, 13}, 0)[0], gospec.Equals, 97)
+	})
+}
@@ -0,0 +1,34 @@
+package fftw
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// CircularShift returns x delayed by delay samples, wrapping around at the
+// ends, so that the result y has y[i] = x[i-delay].  The shift is done by
+// a phase ramp in the frequency domain, so delay needn't be an integer;
+// fractional delays give the band-limited interpolation of x.
+func CircularShift(x []float64, delay float64) []float64 {
+	n := len(x)
+	if n == 0 {
+		return nil
+	}
+	signal := make([]float64, n)
+	copy(signal, x)
+	F_signal := make([]complex128, n/2+1)
+	PlanDftR2C1d(signal, F_signal, Estimate).Execute()
+	for k := range F_signal {
+		theta := -2 * math.Pi * float64(k) * delay / float64(n)
+		if n%2 == 0 && k == n/2 {
+			// The Nyquist bin has no partner to keep the result real, so
+			// only the real part of its phase shift can be kept.
+			F_signal[k] *= complex(math.Cos(theta), 0)
+		} else {
+			F_signal[k] *= cmplx.Rect(1, theta)
+		}
+	}
+	PlanDftC2R1d(F_signal, signal, Estimate).Execute()
+	ScaleReal(signal, 1/float64(n))
+	return signal
+}
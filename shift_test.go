@@ -0,0 +1,58 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func CircularShiftSpec(c gospec.Context) {
+	x := []float64{3, 1, 4, 1, 5, 9, 2, 6}
+
+	c.Specify("Integer shifts rotate the signal.", func() {
+		for _, d := range []int{0, 1, 3, -2, 8, 11} {
+			y := CircularShift(x, float64(d))
+			for i := range y {
+				j := ((i-d)%len(x) + len(x)) % len(x)
+				c.Expect(y[i], gospec.IsWithin(1e-9), x[j])
+			}
+		}
+	})
+
+	c.Specify("Fractional shifts delay band-limited signals exactly.", func() {
+		n := 15
+		f := func(t float64) float64 {
+			return math.Sin(2*math.Pi*2*t/float64(n)) + math.Cos(2*math.Pi*5*t/float64(n))
+		}
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = f(float64(i))
+		}
+		y := CircularShift(s, 0.3)
+		for i := range y {
+			c.Expect(y[i], gospec.IsWithin(1e-9), f(float64(i)-0.3))
+		}
+	})
+
+	c.Specify("Shifting by a fraction and back gives the original.", func() {
+		// A fractional shift by d scales the Nyquist component of x, which
+		// is nyquist*(-1)^i, by cos(pi*d).
+		nyquist := 0.0
+		for i, v := range x {
+			nyquist += v * math.Pow(-1, float64(i))
+		}
+		nyquist /= float64(len(x))
+		y := CircularShift(CircularShift(x, 2.25), -2.25)
+		lost := 1 - math.Cos(math.Pi*2.25)*math.Cos(math.Pi*2.25)
+		for i := range y {
+			want := x[i] - lost*nyquist*math.Pow(-1, float64(i))
+			c.Expect(y[i], gospec.IsWithin(1e-9), want)
+		}
+		z := CircularShift(CircularShift(x, 2.5), 1.5)
+		lost = 1 - math.Cos(math.Pi*2.5)*math.Cos(math.Pi*1.5)
+		for i := range z {
+			j := (i + len(x) - 4) % len(x)
+			want := x[j] - lost*nyquist*math.Pow(-1, float64(j))
+			c.Expect(z[i], gospec.IsWithin(1e-9), want)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package fftw
+
+import (
+	"fmt"
+)
+
+// A SpectrumTracker records the isotropic spectrum of a simulated field at
+// each step, as IsotropicSpectrum2d and IsotropicSpectrum3d compute it, for
+// watching the energy cascade of a simulation as it runs.  Its plan, buffers
+// and the shell of each mode are made once, so each step allocates only the
+// spectrum it records.  SpectrumTrackers are not safe for concurrent use.
+type SpectrumTracker struct {
+	// Times and Spectra hold the time and spectrum of each recorded step.
+	Times   []float64
+	Spectra [][]float64
+
+	dims     []int
+	field    []float64
+	spectrum []complex128
+	plan     *Plan
+	// shell and weight hold the shell of each bin of the half spectrum and
+	// the weight of its power in the sum.
+	shell  []int
+	weight []float64
+	shells int
+}
+
+// NewSpectrumTracker returns a tracker for fields of dimensions dims, which
+// must be 2d or 3d.
+func NewSpectrumTracker(dims []int) *SpectrumTracker {
+	if len(dims) != 2 && len(dims) != 3 {
+		panic(fmt.Sprint("NewSpectrumTracker needs 2d or 3d dimensions, got ", dims))
+	}
+	_, size, half := manyDims("NewSpectrumTracker", dims, 1)
+	t := &SpectrumTracker{
+		dims:     append([]int(nil), dims...),
+		field:    make([]float64, size),
+		spectrum: make([]complex128, half),
+		shell:    make([]int, half),
+		weight:   make([]float64, half),
+		shells:   shellCount(dims),
+	}
+	spec := PlanSpec{Kind: R2C, Dims: t.dims, Dir: Forward, Flag: Estimate}
+	t.plan = spec.PlanDftR2C(t.field, t.spectrum)
+	n := float64(size)
+	forEachMode(dims, func(i int, k []int, w int) {
+		t.shell[i] = shellIndex(k)
+		t.weight[i] = float64(w) / (n * n)
+	})
+	return t
+}
+
+// add adds the shell power of the field in t.field to e.
+func (t *SpectrumTracker) add(e []float64) {
+	t.plan.Execute()
+	for i, v := range t.spectrum {
+		e[t.shell[i]] += t.weight[i] * (real(v)*real(v) + imag(v)*imag(v))
+	}
+}
+
+func (t *SpectrumTracker) record(time float64, e []float64) []float64 {
+	t.Times = append(t.Times, time)
+	t.Spectra = append(t.Spectra, e)
+	return e
+}
+
+// Record2d records, and returns, the spectrum at time of the 2d field made
+// of components, such as the components of a velocity field, whose spectra
+// are summed.
+func (t *SpectrumTracker) Record2d(time float64, components ...[][]float64) []float64 {
+	if len(t.dims) != 2 {
+		panic(fmt.Sprint("Record2d needs a tracker for 2d fields, got one for dimensions ", t.dims))
+	}
+	e := make([]float64, t.shells)
+	for _, u := range components {
+		if len(u) != t.dims[0] {
+			panic(fmt.Sprint("Record2d needs fields of dimensions ", t.dims, ", got ", len(u), " rows"))
+		}
+		for i, row := range u {
+			if len(row) != t.dims[1] {
+				panic(fmt.Sprint("Record2d needs fields of dimensions ", t.dims, ", got a row of ", len(row)))
+			}
+			copy(t.field[i*t.dims[1]:], row)
+		}
+		t.add(e)
+	}
+	return t.record(time, e)
+}
+
+// Record3d is Record2d for 3d fields.
+func (t *SpectrumTracker) Record3d(time float64, components ...[][][]float64) []float64 {
+	if len(t.dims) != 3 {
+		panic(fmt.Sprint("Record3d needs a tracker for 3d fields, got one for dimensions ", t.dims))
+	}
+	e := make([]float64, t.shells)
+	plane := t.dims[1] * t.dims[2]
+	for _, u := range components {
+		if len(u) != t.dims[0] {
+			panic(fmt.Sprint("Record3d needs fields of dimensions ", t.dims, ", got ", len(u), " planes"))
+		}
+		for i, p := range u {
+			if len(p) != t.dims[1] {
+				panic(fmt.Sprint("Record3d needs fields of dimensions ", t.dims, ", got a plane of ", len(p), " rows"))
+			}
+			for j, row := range p {
+				if len(row) != t.dims[2] {
+					panic(fmt.Sprint("Record3d needs fields of dimensions ", t.dims, ", got a row of ", len(row)))
+				}
+				copy(t.field[i*plane+j*t.dims[2]:], row)
+			}
+		}
+		t.add(e)
+	}
+	return t.record(time, e)
+}
+
+// Energy returns the total of each recorded spectrum, the mean square of
+// its field, as a time series alongside Times.
+func (t *SpectrumTracker) Energy() []float64 {
+	e := make([]float64, len(t.Spectra))
+	for i, s := range t.Spectra {
+		e[i] = sum(s)
+	}
+	return e
+}
@@ -0,0 +1,52 @@
+package fftw
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func SpectrumTrackerSpec(c gospec.Context) {
+	c.Specify("Recorded spectra match IsotropicSpectrum.", func() {
+		rng := rand.New(rand.NewSource(6))
+		t := NewSpectrumTracker([]int{6, 8})
+		for step := 0; step < 3; step++ {
+			u := alloc2dReal(6, 8)
+			v := alloc2dReal(6, 8)
+			for i := range u {
+				for j := range u[i] {
+					u[i][j] = rng.NormFloat64()
+					v[i][j] = rng.NormFloat64()
+				}
+			}
+			e := t.Record2d(float64(step)*0.1, u, v)
+			eu, ev := IsotropicSpectrum2d(u), IsotropicSpectrum2d(v)
+			for k := range e {
+				c.Expect(e[k], gospec.IsWithin(1e-12), eu[k]+ev[k])
+			}
+		}
+		c.Expect(t.Times, gospec.ContainsInOrder, []float64{0, 0.1, 0.2})
+		c.Expect(len(t.Spectra), gospec.Equals, 3)
+	})
+
+	c.Specify("A decaying mode shows up in the energy series.", func() {
+		t := NewSpectrumTracker([]int{4, 4, 8})
+		u := alloc3dReal(4, 4, 8)
+		for step := 0; step < 4; step++ {
+			a := math.Exp(-float64(step))
+			for i := range u {
+				for j := range u[i] {
+					for k := range u[i][j] {
+						u[i][j][k] = a * math.Sin(2*math.Pi*float64(k)*2/8)
+					}
+				}
+			}
+			e := t.Record3d(float64(step), u)
+			c.Expect(e[2], gospec.IsWithin(1e-12), a*a/2)
+		}
+		for step, e := range t.Energy() {
+			c.Expect(e, gospec.IsWithin(1e-12), math.Exp(-2*float64(step))/2)
+		}
+	})
+}
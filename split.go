@@ -0,0 +1,92 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// checkSplit panics unless the real and imaginary parts of a split array
+// have the same length.
+func checkSplit(name string, re, im []float64) {
+	if len(re) != len(im) {
+		panic(fmt.Sprint(name, " needs real and imaginary parts of the same length, got ", len(re), " and ", len(im)))
+	}
+}
+
+// newSplitPlan records the imaginary arrays of a split plan.
+func newSplitPlan(p C.fftw_plan, geom Geometry, in, out, imIn, imOut *C.double) *Plan {
+	geom.Split = true
+	np := newPlan(p, geom, unsafe.Pointer(in), unsafe.Pointer(out))
+	np.imIn, np.imOut = unsafe.Pointer(imIn), unsafe.Pointer(imOut)
+	return np
+}
+
+// PlanGuruSplitDft plans complex transforms, as PlanGuruDft does, of arrays
+// stored as separate planes of real parts, ri and ro, and imaginary parts,
+// ii and io, as many file formats and libraries store them, without
+// interleaving them first.  Strides count float64s.
+func PlanGuruSplitDft(dims, howmany []IODim, ri, ii, ro, io []float64, dir Direction, flag Flag) *Plan {
+	checkSplit("PlanGuruSplitDft", ri, ii)
+	checkSplit("PlanGuruSplitDft", ro, io)
+	g := newGuruPlan("PlanGuruSplitDft", C2C, dims, howmany, len(ri), len(ro), fullLen, fullLen)
+	g.geom.Dir = dir
+	fftw_ri := (*C.double)(unsafe.Pointer(&ri[0]))
+	fftw_ii := (*C.double)(unsafe.Pointer(&ii[0]))
+	fftw_ro := (*C.double)(unsafe.Pointer(&ro[0]))
+	fftw_io := (*C.double)(unsafe.Pointer(&io[0]))
+	// Split plans are always forward; swapping the real and imaginary
+	// parts of both arrays turns a forward transform into a backward one.
+	a, b, x, y := fftw_ri, fftw_ii, fftw_ro, fftw_io
+	if dir == Backward {
+		a, b, x, y = fftw_ii, fftw_ri, fftw_io, fftw_ro
+	}
+	planner.Lock()
+	p := C.fftw_plan_guru64_split_dft(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], a, b, x, y, planFlags(flag))
+	planner.Unlock()
+	return newSplitPlan(p, g.geom, fftw_ri, fftw_ro, fftw_ii, fftw_io)
+}
+
+// PlanGuruSplitDftR2C plans real-to-complex transforms, as PlanGuruDftR2C
+// does, writing the real and imaginary parts of the output to ro and io.
+func PlanGuruSplitDftR2C(dims, howmany []IODim, in, ro, io []float64, flag Flag) *Plan {
+	checkSplit("PlanGuruSplitDftR2C", ro, io)
+	g := newGuruPlan("PlanGuruSplitDftR2C", R2C, dims, howmany, len(in), len(ro), fullLen, halfLen)
+	g.geom.Dir = Forward
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_ro := (*C.double)(unsafe.Pointer(&ro[0]))
+	fftw_io := (*C.double)(unsafe.Pointer(&io[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_split_dft_r2c(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_in, fftw_ro, fftw_io, planFlags(flag))
+	planner.Unlock()
+	return newSplitPlan(p, g.geom, fftw_in, fftw_ro, nil, fftw_io)
+}
+
+// PlanGuruSplitDftC2R plans complex-to-real transforms, as PlanGuruDftC2R
+// does, reading the real and imaginary parts of the input from ri and ii.
+func PlanGuruSplitDftC2R(dims, howmany []IODim, ri, ii, out []float64, flag Flag) *Plan {
+	checkSplit("PlanGuruSplitDftC2R", ri, ii)
+	g := newGuruPlan("PlanGuruSplitDftC2R", C2R, dims, howmany, len(ri), len(out), halfLen, fullLen)
+	g.geom.Dir = Backward
+	fftw_ri := (*C.double)(unsafe.Pointer(&ri[0]))
+	fftw_ii := (*C.double)(unsafe.Pointer(&ii[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_guru64_split_dft_c2r(C.int(len(g.dims)), &g.dims[0], C.int(len(howmany)), &g.loops[0], fftw_ri, fftw_ii, fftw_out, planFlags(flag))
+	planner.Unlock()
+	if p == nil {
+		panic(fmt.Sprint("fftw could not plan complex-to-real transforms of dimensions ", dims, " with flags ", flag))
+	}
+	return newSplitPlan(p, g.geom, fftw_ri, fftw_out, fftw_ii, nil)
+}
+
+// PlanSplitDft1d plans a one dimensional complex transform of the split
+// array ri, ii to the split array ro, io.
+func PlanSplitDft1d(ri, ii, ro, io []float64, dir Direction, flag Flag) *Plan {
+	if len(ri) != len(ro) {
+		panic(fmt.Sprint("PlanSplitDft1d needs arrays of the same length, got ", len(ri), " and ", len(ro)))
+	}
+	return PlanGuruSplitDft([]IODim{{len(ri), 1, 1}}, nil, ri, ii, ro, io, dir, flag)
+}
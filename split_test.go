@@ -0,0 +1,61 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func SplitSpec(c gospec.Context) {
+	x := []complex128{1 + 2i, -1, 3i, 2 - 1i, 0.5, 4}
+	re := make([]float64, len(x))
+	im := make([]float64, len(x))
+	for i, v := range x {
+		re[i], im[i] = real(v), imag(v)
+	}
+
+	c.Specify("Split transforms match interleaved ones in both directions.", func() {
+		for _, dir := range []Direction{Forward, Backward} {
+			ro := make([]float64, len(x))
+			io := make([]float64, len(x))
+			p := PlanSplitDft1d(re, im, ro, io, dir, Estimate)
+			p.Execute()
+			want := make([]complex128, len(x))
+			PlanDft1d(x, want, dir, Estimate).Execute()
+			for k, v := range want {
+				c.Expect(ro[k], gospec.IsWithin(1e-9), real(v))
+				c.Expect(io[k], gospec.IsWithin(1e-9), imag(v))
+			}
+			c.Expect(p.Geometry().Split, gospec.IsTrue)
+			c.Expect(p.Geometry().Dir, gospec.Equals, dir)
+		}
+	})
+
+	c.Specify("Split real transforms round trip.", func() {
+		ro := make([]float64, 4)
+		io := make([]float64, 4)
+		PlanGuruSplitDftR2C([]IODim{{6, 1, 1}}, nil, re, ro, io, Estimate).Execute()
+		want := make([]complex128, 4)
+		PlanDftR2C1d(append([]float64(nil), re...), want, Estimate).Execute()
+		for k, v := range want {
+			c.Expect(ro[k], gospec.IsWithin(1e-9), real(v))
+			c.Expect(io[k], gospec.IsWithin(1e-9), imag(v))
+		}
+		back := make([]float64, 6)
+		PlanGuruSplitDftC2R([]IODim{{6, 1, 1}}, nil, ro, io, back, Estimate).Execute()
+		for i := range back {
+			c.Expect(back[i]/6, gospec.IsWithin(1e-9), re[i])
+		}
+	})
+
+	c.Specify("Split plans are distinct from interleaved ones and aren't warmed up.", func() {
+		ro := make([]float64, len(x))
+		io := make([]float64, len(x))
+		p := PlanSplitDft1d(re, im, ro, io, Forward, Estimate)
+		p.WarmUp()
+		for _, v := range ro {
+			c.Expect(v, gospec.Equals, 0.0)
+		}
+		q := PlanGuruDft([]IODim{{6, 1, 1}}, nil, make([]complex128, 6), make([]complex128, 6), Forward, Estimate)
+		c.Expect(p.Geometry().Equal(q.Geometry()), gospec.IsFalse)
+		c.Expect(p.Geometry().Key() == q.Geometry().Key(), gospec.IsFalse)
+	})
+}
@@ -0,0 +1,60 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// NoiseFloor estimates the noise floor of the power spectrum psd as its
+// running median over width bins, truncated at the ends.  The median ignores
+// narrow spurs as long as they are less than half as wide as the window.
+func NoiseFloor(psd []float64, width int) []float64 {
+	if width < 1 {
+		panic(fmt.Sprint("NoiseFloor needs a positive width, got ", width))
+	}
+	floor := make([]float64, len(psd))
+	window := make([]float64, 0, width)
+	for k := range psd {
+		lo, hi := k-width/2, k-width/2+width
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(psd) {
+			hi = len(psd)
+		}
+		window = append(window[:0], psd[lo:hi]...)
+		floor[k] = median(window)
+	}
+	return floor
+}
+
+// A Spur is a narrow peak standing out of the noise floor of a spectrum.
+type Spur struct {
+	Bin   int
+	Freq  float64
+	Level float64
+	Floor float64
+	// Margin is how far the spur is above the floor, in dB.
+	Margin float64
+}
+
+// FindSpurs returns the local peaks of the power spectrum psd, sampled at
+// freqs, that stand more than threshold dB above its noise floor, estimated
+// by NoiseFloor with the given width.
+func FindSpurs(freqs, psd []float64, width int, threshold float64) []Spur {
+	if len(freqs) != len(psd) {
+		panic(fmt.Sprint("FindSpurs needs a frequency per bin, got ", len(freqs), " for ", len(psd), " bins"))
+	}
+	floor := NoiseFloor(psd, width)
+	var spurs []Spur
+	for k, p := range psd {
+		if (k > 0 && psd[k-1] > p) || (k+1 < len(psd) && psd[k+1] >= p) {
+			continue
+		}
+		margin := 10 * math.Log10(p/floor[k])
+		if margin > threshold {
+			spurs = append(spurs, Spur{k, freqs[k], p, floor[k], margin})
+		}
+	}
+	return spurs
+}
@@ -0,0 +1,36 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/rand"
+)
+
+func SpurSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(13))
+	psd := make([]float64, 200)
+	freqs := make([]float64, len(psd))
+	for k := range psd {
+		psd[k] = 1e-6 * (1 + 0.2*rng.Float64())
+		freqs[k] = float64(k) * 10
+	}
+	psd[50] = 1e-3
+	psd[120] = 1e-4
+	psd[121] = 5e-5
+
+	c.Specify("The noise floor ignores narrow spurs.", func() {
+		floor := NoiseFloor(psd, 15)
+		for k := range floor {
+			c.Expect(floor[k] > 1e-6 && floor[k] < 1.2e-6, gospec.IsTrue)
+		}
+	})
+
+	c.Specify("Spurs above the threshold are found once each.", func() {
+		spurs := FindSpurs(freqs, psd, 15, 10)
+		c.Expect(len(spurs), gospec.Equals, 2)
+		c.Expect(spurs[0].Bin, gospec.Equals, 50)
+		c.Expect(spurs[0].Freq, gospec.Equals, 500.0)
+		c.Expect(spurs[0].Margin > 28, gospec.IsTrue)
+		c.Expect(spurs[1].Bin, gospec.Equals, 120)
+		c.Expect(len(FindSpurs(freqs, psd, 15, 25)), gospec.Equals, 1)
+	})
+}
@@ -0,0 +1,247 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// A frameFFT transforms n0 x n1 frames, reusing one buffer and its plans,
+// and fine, upsampled frames with another.
+type frameFFT struct {
+	buf               [][]complex128
+	forward, backward *Plan
+	fine              [][]complex128
+	fineBackward      *Plan
+}
+
+func newFrameFFT(n0, n1 int) *frameFFT {
+	f := &frameFFT{buf: Alloc2d(n0, n1)}
+	f.forward = PlanDft2d(f.buf, f.buf, Forward, Estimate)
+	f.backward = PlanDft2d(f.buf, f.buf, Backward, Estimate)
+	return f
+}
+
+func (f *frameFFT) free() {
+	Free2d(f.buf)
+	if f.fine != nil {
+		Free2d(f.fine)
+	}
+}
+
+// spectrum returns the spectrum of x, which must be n0 x n1.
+func (f *frameFFT) spectrum(x [][]float64) [][]complex128 {
+	if len(x) != len(f.buf) {
+		panic(fmt.Sprint("Frames must all be ", len(f.buf), "x", len(f.buf[0]), ", got ", len(x), " rows"))
+	}
+	for i := range x {
+		if len(x[i]) != len(f.buf[i]) {
+			panic(fmt.Sprint("Frames must all be ", len(f.buf), "x", len(f.buf[0]), ", got a row of length ", len(x[i])))
+		}
+		for j, v := range x[i] {
+			f.buf[i][j] = complex(v, 0)
+		}
+	}
+	f.forward.Execute()
+	s := make([][]complex128, len(f.buf))
+	for i := range s {
+		s[i] = append([]complex128(nil), f.buf[i]...)
+	}
+	return s
+}
+
+// PhaseCorrelate2d returns the shift, in rows and columns, that best
+// carries ref onto img, so that img(y, x) ≈ ref(y-dy, x-dx), along with the
+// height of the correlation peak, which is near 1 for frames that differ
+// only by a whole number of samples and near 0 for unrelated ones.  The
+// peak gives the shift to the nearest sample, and the slope of the phase
+// of the cross-power spectrum the rest of it.  Shifts lie within half the
+// frame.
+// The frames are treated as periodic, so they should be windowed or have
+// quiet edges.
+func PhaseCorrelate2d(ref, img [][]float64) (dy, dx, peak float64) {
+	if len(ref) == 0 || len(ref[0]) == 0 {
+		panic("PhaseCorrelate2d needs non-empty frames")
+	}
+	f := newFrameFFT(len(ref), len(ref[0]))
+	defer f.free()
+	return f.correlate(f.spectrum(ref), img)
+}
+
+func (f *frameFFT) correlate(ref [][]complex128, img [][]float64) (dy, dx, peak float64) {
+	s := f.spectrum(img)
+	// Bins where either frame has no energy carry no phase to compare, so
+	// they are left out rather than normalized to noise.
+	max := 0.0
+	for i := range s {
+		for j, v := range s[i] {
+			s[i][j] = v * cmplx.Conj(ref[i][j])
+			max = math.Max(max, cmplx.Abs(s[i][j]))
+		}
+	}
+	kept := 0
+	for i := range s {
+		for j, c := range s[i] {
+			f.buf[i][j] = 0
+			if a := cmplx.Abs(c); a > 1e-9*max {
+				f.buf[i][j] = c / complex(a, 0)
+				kept++
+			}
+		}
+	}
+	f.backward.Execute()
+	n0, n1 := len(f.buf), len(f.buf[0])
+	if kept == 0 {
+		return 0, 0, 0
+	}
+	scale := 1 / float64(kept)
+	r := func(i, j int) float64 {
+		return real(f.buf[i][j]) * scale
+	}
+	bi, bj := 0, 0
+	for i := range f.buf {
+		for j := range f.buf[i] {
+			if r(i, j) > r(bi, bj) {
+				bi, bj = i, j
+			}
+		}
+	}
+	peak = r(bi, bj)
+	iy, ix := signedFreq(bi, n0)*float64(n0), signedFreq(bj, n1)*float64(n1)
+	// What is left of the shift after the whole samples are taken out is
+	// a phase ramp across the cross-power spectrum, whose slope is fitted
+	// by least squares, each bin weighted by its power.  The Nyquist bins,
+	// whose phase is ambiguous, are left out.
+	var syy, syx, sxx, by, bx float64
+	for i := range s {
+		fy := signedFreq(i, n0)
+		for j, c := range s[i] {
+			fx := signedFreq(j, n1)
+			if 2*i == n0 || 2*j == n1 {
+				continue
+			}
+			c *= cmplx.Rect(1, 2*math.Pi*(fy*iy+fx*ix))
+			w, phase := cmplx.Abs(c), cmplx.Phase(c)
+			syy += w * fy * fy
+			syx += w * fy * fx
+			sxx += w * fx * fx
+			by -= w * fy * phase / (2 * math.Pi)
+			bx -= w * fx * phase / (2 * math.Pi)
+		}
+	}
+	if det := syy*sxx - syx*syx; det > 0 {
+		iy += (by*sxx - bx*syx) / det
+		ix += (bx*syy - by*syx) / det
+	}
+	dy, dx = iy, ix
+	return dy, dx, peak
+}
+
+// FourierShift2d returns x shifted by dy rows and dx columns, a fraction
+// of a sample if need be, by multiplying its spectrum by a linear phase.
+// The shift is periodic, so what leaves one edge comes back at the other.
+func FourierShift2d(x [][]float64, dy, dx float64) [][]float64 {
+	if len(x) == 0 || len(x[0]) == 0 {
+		return nil
+	}
+	f := newFrameFFT(len(x), len(x[0]))
+	defer f.free()
+	out := alloc2dReal(len(x), len(x[0]))
+	f.resample(f.spectrum(x), dy, dx, 1, out)
+	return out
+}
+
+// resample shifts the spectrum s by dy, dx and adds its inverse transform,
+// interpolated to factor times as many samples on each axis, into out.
+func (f *frameFFT) resample(s [][]complex128, dy, dx float64, factor int, out [][]float64) {
+	n0, n1 := len(s), len(s[0])
+	m0, m1 := n0*factor, n1*factor
+	fine, p := f.buf, f.backward
+	if factor > 1 {
+		if len(f.fine) != m0 {
+			f.fine = Alloc2d(m0, m1)
+			f.fineBackward = PlanDft2d(f.fine, f.fine, Backward, Estimate)
+		}
+		fine, p = f.fine, f.fineBackward
+	}
+	for i := range fine {
+		for j := range fine[i] {
+			fine[i][j] = 0
+		}
+	}
+	// bins returns the bins of the fine spectrum that bin k of an n point
+	// axis goes to, with their frequencies.  The Nyquist bin of an even
+	// axis stands for both +n/2 and -n/2, so when upsampling it is split
+	// between the two.
+	bins := func(k, n, m int) ([]int, []float64) {
+		fk := signedFreq(k, n)
+		b := (int(math.Round(fk*float64(n))) + m) % m
+		if m > n && 2*k == n {
+			return []int{b, m - n/2}, []float64{fk, -fk}
+		}
+		return []int{b}, []float64{fk}
+	}
+	for i := range s {
+		ys, fys := bins(i, n0, m0)
+		for j, v := range s[i] {
+			xs, fxs := bins(j, n1, m1)
+			w := 1 / float64(len(ys)*len(xs))
+			for a, y := range ys {
+				for b, x := range xs {
+					fine[y][x] += v * cmplx.Rect(w, -2*math.Pi*(fys[a]*dy+fxs[b]*dx))
+				}
+			}
+		}
+	}
+	p.Execute()
+	scale := 1 / float64(n0*n1)
+	for i := range out {
+		for j := range out[i] {
+			out[i][j] += real(fine[i][j]) * scale
+		}
+	}
+}
+
+// A Stack is the result of StackFrames.
+type Stack struct {
+	// Image is the average of the registered frames.
+	Image [][]float64
+	// Shifts holds the shift, in rows and columns, of each frame from the
+	// first, as PhaseCorrelate2d finds it.
+	Shifts [][2]float64
+	// Peaks holds the height of each frame's correlation peak, low for
+	// frames that failed to register.
+	Peaks []float64
+}
+
+// StackFrames registers each frame against the first with
+// PhaseCorrelate2d, shifts it into place with a Fourier shift and averages
+// them, as for astronomy and microscopy exposures that drift between
+// frames.  Averaging n frames cuts uncorrelated noise by a factor of √n.
+// If upsample is more than 1 the frames are interpolated by zero padding
+// their spectra onto a grid upsample times finer on each axis before they
+// are averaged, so that Image is upsample times larger in each direction.
+func StackFrames(frames [][][]float64, upsample int) *Stack {
+	if len(frames) == 0 || len(frames[0]) == 0 || len(frames[0][0]) == 0 {
+		panic("StackFrames needs at least one non-empty frame")
+	}
+	if upsample < 1 {
+		panic(fmt.Sprint("StackFrames needs a positive upsampling factor, got ", upsample))
+	}
+	n0, n1 := len(frames[0]), len(frames[0][0])
+	f := newFrameFFT(n0, n1)
+	defer f.free()
+	ref := f.spectrum(frames[0])
+	st := &Stack{Image: alloc2dReal(n0*upsample, n1*upsample)}
+	for _, frame := range frames {
+		dy, dx, peak := f.correlate(ref, frame)
+		st.Shifts = append(st.Shifts, [2]float64{dy, dx})
+		st.Peaks = append(st.Peaks, peak)
+		f.resample(f.spectrum(frame), -dy, -dx, upsample, st.Image)
+	}
+	scale := 1 / float64(len(frames))
+	for i := range st.Image {
+		ScaleReal(st.Image[i], scale)
+	}
+	return st
+}
@@ -0,0 +1,98 @@
+package fftw
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+// texture returns an n x n periodic image with energy at every frequency
+// below the Nyquist frequency, shifted by dy, dx.
+func texture(n int, dy, dx float64) [][]float64 {
+	rng := rand.New(rand.NewSource(1))
+	img := alloc2dReal(n, n)
+	for k := 0; k < n/2; k++ {
+		for l := 1 - n/2; l < n/2; l++ {
+			if k == 0 && l <= 0 {
+				continue
+			}
+			amp := 1 / (1 + float64(k*k+l*l)/4)
+			phase := 2 * math.Pi * rng.Float64()
+			for i := range img {
+				for j := range img[i] {
+					t := 2 * math.Pi * (float64(k)*(float64(i)-dy) + float64(l)*(float64(j)-dx)) / float64(n)
+					img[i][j] += amp * math.Cos(t+phase)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func StackSpec(c gospec.Context) {
+	n := 16
+
+	c.Specify("Phase correlation finds whole and fractional shifts.", func() {
+		dy, dx, peak := PhaseCorrelate2d(texture(n, 0, 0), texture(n, 3, -2))
+		c.Expect(dy, gospec.IsWithin(1e-6), 3.0)
+		c.Expect(dx, gospec.IsWithin(1e-6), -2.0)
+		c.Expect(peak, gospec.IsWithin(1e-6), 1.0)
+		dy, dx, _ = PhaseCorrelate2d(texture(n, 0, 0), texture(n, 1.3, -0.4))
+		c.Expect(dy, gospec.IsWithin(1e-6), 1.3)
+		c.Expect(dx, gospec.IsWithin(1e-6), -0.4)
+	})
+
+	c.Specify("Fourier shifts move band-limited images exactly.", func() {
+		got := FourierShift2d(texture(n, 0, 0), 0.7, -1.25)
+		want := texture(n, 0.7, -1.25)
+		for i := range got {
+			for j := range got[i] {
+				c.Expect(got[i][j], gospec.IsWithin(1e-9), want[i][j])
+			}
+		}
+	})
+
+	c.Specify("Stacking registers and averages noisy frames.", func() {
+		rng := rand.New(rand.NewSource(3))
+		shifts := [][2]float64{{0, 0}, {2, 1}, {-1, 3}, {0.5, -2}}
+		var frames [][][]float64
+		for _, s := range shifts {
+			f := texture(n, s[0], s[1])
+			for i := range f {
+				for j := range f[i] {
+					f[i][j] += 0.05 * rng.NormFloat64()
+				}
+			}
+			frames = append(frames, f)
+		}
+		st := StackFrames(frames, 1)
+		for k, s := range shifts {
+			c.Expect(st.Shifts[k][0], gospec.IsWithin(0.15), s[0])
+			c.Expect(st.Shifts[k][1], gospec.IsWithin(0.15), s[1])
+		}
+		want := texture(n, 0, 0)
+		for i := range want {
+			for j := range want[i] {
+				c.Expect(st.Image[i][j], gospec.IsWithin(0.15), want[i][j])
+			}
+		}
+	})
+
+	c.Specify("Upsampled stacks interpolate between the samples.", func() {
+		frames := [][][]float64{texture(8, 0, 0), texture(8, 1, 2)}
+		st := StackFrames(frames, 2)
+		c.Expect(len(st.Image), gospec.Equals, 16)
+		c.Expect(len(st.Image[0]), gospec.Equals, 16)
+		// The even samples of the upsampled stack are the first frame's,
+		// and the others are between them.
+		want := texture(8, 0, 0)
+		half := texture(8, -0.5, -0.5)
+		for i := range want {
+			for j := range want[i] {
+				c.Expect(st.Image[2*i][2*j], gospec.IsWithin(1e-6), want[i][j])
+				c.Expect(st.Image[2*i+1][2*j+1], gospec.IsWithin(1e-6), half[i][j])
+			}
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package fftw
+
+import (
+	"context"
+	"math"
+)
+
+// An STFT computes short-time Fourier transforms of real signals, using the
+// same frame length, hop and window for every frame and reusing its plans
+// and buffers.  Frames are centred on multiples of the hop, with the signal
+// zero padded by half a frame at each end, so a signal of n samples has
+// n/hop+1 frames.  An STFT is not safe for concurrent use.
+type STFT struct {
+	n, hop   int
+	w        Window
+	window   []float64
+	frame    []float64
+	spectrum []complex128
+	forward  *Plan
+	backward *Plan
+}
+
+// NewSTFT returns an STFT with frames of n samples, hop samples apart,
+// tapered by the window w.  Each frame's spectrum has n/2+1 bins.
+func NewSTFT(n, hop int, w Window) *STFT {
+	s := new(STFT)
+	s.n = n
+	s.hop = hop
+	s.w = w
+	s.window = Window1d(n, w)
+	s.frame = make([]float64, n)
+	s.spectrum = make([]complex128, n/2+1)
+	s.forward = PlanDftR2C1d(s.frame, s.spectrum, Estimate)
+	s.backward = PlanDftC2R1d(s.spectrum, s.frame, Estimate)
+	return s
+}
+
+// Frequencies returns the frequency of each bin of a frame's spectrum.
+func (s *STFT) Frequencies(sampleRate float64) []float64 {
+	return rfftFreqs(s.n, sampleRate)
+}
+
+// frames returns the number of frames in a signal of n samples.
+func (s *STFT) frames(n int) int {
+	return n/s.hop + 1
+}
+
+// Transform returns the spectrum of each frame of x.
+func (s *STFT) Transform(x []float64) [][]complex128 {
+	return s.transform(x, s.window)
+}
+
+// TransformContext is Transform for long signals, reporting progress to
+// progress, if it is not nil, after each frame.  If ctx is cancelled it
+// stops early, returning the frames done so far and ctx's error.
+func (s *STFT) TransformContext(ctx context.Context, x []float64, progress ProgressFunc) ([][]complex128, error) {
+	return s.transformContext(ctx, x, s.window, progress)
+}
+
+// transform is Transform with a different taper, which must have the
+// frame length.
+func (s *STFT) transform(x []float64, window []float64) [][]complex128 {
+	spectra, _ := s.transformContext(context.Background(), x, window, nil)
+	return spectra
+}
+
+func (s *STFT) transformContext(ctx context.Context, x []float64, window []float64, progress ProgressFunc) ([][]complex128, error) {
+	frames := s.frames(len(x))
+	tracker := newProgressTracker(progress, frames)
+	bins := s.n/2 + 1
+	spectra := make([][]complex128, frames)
+	data := make([]complex128, frames*bins)
+	for t := range spectra {
+		start := t*s.hop - s.n/2
+		for m := range s.frame {
+			if i := start + m; i >= 0 && i < len(x) {
+				s.frame[m] = x[i] * window[m]
+			} else {
+				s.frame[m] = 0
+			}
+		}
+		s.forward.Execute()
+		spectra[t] = data[t*bins : (t+1)*bins]
+		copy(spectra[t], s.spectrum)
+		tracker.report(t + 1)
+		if err := ctx.Err(); err != nil {
+			return spectra[:t+1], err
+		}
+	}
+	return spectra, nil
+}
+
+// Inverse returns the length sample signal whose STFT is closest, in the
+// least squares sense, to spectra.  The spectra of an unmodified signal give
+// back that signal, wherever the window overlap leaves it covered.
+func (s *STFT) Inverse(spectra [][]complex128, length int) []float64 {
+	if len(spectra) == 0 {
+		return make([]float64, length)
+	}
+	pad := s.n / 2
+	size := (len(spectra)-1)*s.hop + s.n
+	if size < length+pad {
+		size = length + pad
+	}
+	out := make([]float64, size)
+	weight := scratch.reals(size)
+	defer scratch.releaseReals(weight)
+	for t := range spectra {
+		copy(s.spectrum, spectra[t])
+		s.backward.Execute()
+		start := t * s.hop
+		for m, v := range s.frame {
+			w := s.window[m]
+			out[start+m] += v * w / float64(s.n)
+			weight[start+m] += w * w
+		}
+	}
+	for i := range out {
+		if weight[i] > 1e-10 {
+			out[i] /= weight[i]
+		}
+	}
+	return out[pad : pad+length]
+}
+
+// derivativeWindow returns the derivative, with respect to the sample index,
+// of the STFT's window.
+func (s *STFT) derivativeWindow() []float64 {
+	const h = 1e-5
+	d := make([]float64, s.n)
+	if s.n < 2 {
+		return d
+	}
+	at := func(pos float64) float64 {
+		return s.w(math.Abs(pos))
+	}
+	for m := range d {
+		pos := windowPos(m, s.n)
+		d[m] = (at(pos+h) - at(pos-h)) / (2 * h) * 2 / float64(s.n-1)
+	}
+	return d
+}
@@ -0,0 +1,85 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+	"math/rand"
+)
+
+func STFTSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(5))
+	x := make([]float64, 300)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	s := NewSTFT(64, 16, Hann)
+	spectra := s.Transform(x)
+
+	c.Specify("Signals are split into centred frames.", func() {
+		c.Expect(len(spectra), gospec.Equals, 300/16+1)
+		for _, f := range spectra {
+			c.Expect(len(f), gospec.Equals, 33)
+		}
+		freqs := s.Frequencies(64)
+		c.Expect(freqs[32], gospec.IsWithin(1e-12), 32.0)
+	})
+
+	c.Specify("The inverse STFT reconstructs the signal.", func() {
+		y := s.Inverse(spectra, len(x))
+		c.Expect(len(y), gospec.Equals, len(x))
+		for i := range x {
+			c.Expect(y[i], gospec.IsWithin(1e-9), x[i])
+		}
+		c.Expect(len(s.Inverse(nil, 10)), gospec.Equals, 10)
+	})
+}
+
+func SynchrosqueezeSpec(c gospec.Context) {
+	n := 64
+	x := make([]float64, 1024)
+	for i := range x {
+		x[i] = math.Cos(2 * math.Pi * 10.3 * float64(i) / float64(n))
+	}
+	s := NewSTFT(n, 16, Hann)
+	spectra := s.Transform(x)
+	squeezed := s.Synchrosqueeze(x, 1e-3)
+
+	c.Specify("Synchrosqueezing concentrates a tone into one bin.", func() {
+		for t := 8; t < len(squeezed)-8; t++ {
+			total, plain := 0.0, 0.0
+			for k := range squeezed[t] {
+				total += cmplx.Abs(squeezed[t][k])
+				plain += cmplx.Abs(spectra[t][k])
+			}
+			c.Expect(cmplx.Abs(squeezed[t][10])/total > 0.95, gospec.IsTrue)
+			c.Expect(cmplx.Abs(spectra[t][10])/plain < 0.75, gospec.IsTrue)
+		}
+	})
+
+	c.Specify("Synchrosqueezing keeps the sum of each frame.", func() {
+		for t := range squeezed {
+			var a, b complex128
+			for k := range squeezed[t] {
+				a += squeezed[t][k]
+				if v := spectra[t][k]; cmplx.Abs(v) > 1e-3 {
+					b += v * cmplx.Rect(1, 2*math.Pi*float64(k)*float64(n/2)/float64(n))
+				}
+			}
+			c.Expect(cmplx.Abs(a-b), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("Synchrosqueezing works for odd frame lengths.", func() {
+		odd := NewSTFT(63, 16, Hann)
+		squeezed := odd.Synchrosqueeze(x, 1e-3)
+		for t := 8; t < len(squeezed)-8; t++ {
+			total := 0.0
+			for k := range squeezed[t] {
+				total += cmplx.Abs(squeezed[t][k])
+			}
+			// 10.3 cycles per 64 samples is 10.14 per 63.
+			c.Expect(cmplx.Abs(squeezed[t][10])/total > 0.95, gospec.IsTrue)
+		}
+	})
+}
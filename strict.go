@@ -0,0 +1,54 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"sync/atomic"
+)
+
+var strict int32
+
+// SetStrict turns strict mode on or off for plans made afterwards.  In
+// strict mode every plan is made as if with Estimate, whatever flags it
+// asks for, and without regard to the alignment of its arrays, so that the
+// same transform of the same size always gets the same algorithm and gives
+// bit for bit the same results from run to run and machine to machine with
+// the same fftw library.  Without it, Measure and the flags like it time
+// candidate algorithms, which can pick different ones from run to run, and
+// also leave behind wisdom that changes later Estimate plans, and arrays of
+// different alignments can get different SIMD code.
+//
+// Strict mode can't make results reproducible across different builds of
+// fftw or different CPUs, where the algorithms themselves differ; use
+// RecordGolden to detect that.  Nor does it affect the reductions in this
+// package's own code, which all sum in a fixed order.
+func SetStrict(on bool) {
+	v := int32(0)
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&strict, v)
+}
+
+// Strict reports whether strict mode is on.
+func Strict() bool {
+	return atomic.LoadInt32(&strict) != 0
+}
+
+// planFlags returns the flags to pass to fftw for a plan asking for flag.
+// Every plan in the package goes through it.
+func planFlags(flag Flag) C.uint {
+	if Strict() {
+		flag &^= Measure | Patient | Exhaustive | WisdomOnly
+		flag |= Estimate | Unaligned
+	}
+	return C.uint(flag)
+}
+
+// PlannerFlags returns the flags to pass to fftw for a plan asking for
+// flag, as the package's own plans pass them, so that bindings in other
+// packages, such as fftw32 and mpi, honor strict mode too.
+func PlannerFlags(flag Flag) uint {
+	return uint(planFlags(flag))
+}
@@ -0,0 +1,71 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func StrictSpec(c gospec.Context) {
+	defer SetStrict(false)
+
+	c.Specify("Strict mode plans everything as Estimate, ignoring alignment.", func() {
+		c.Expect(Strict(), gospec.IsFalse)
+		c.Expect(uint(planFlags(Measure)), gospec.Equals, uint(Measure))
+		SetStrict(true)
+		c.Expect(Strict(), gospec.IsTrue)
+		c.Expect(planFlags(Measure), gospec.Equals, planFlags(Estimate))
+		c.Expect(uint(planFlags(Measure|PreserveInput))&uint(PreserveInput) != 0, gospec.IsTrue)
+		c.Expect(uint(planFlags(Estimate))&uint(Estimate) != 0, gospec.IsTrue)
+	})
+
+	c.Specify("Strict plans give the same results as ordinary ones.", func() {
+		x := make([]complex128, 12)
+		for i := range x {
+			x[i] = complex(float64(i%5), float64(i%3))
+		}
+		a := make([]complex128, 12)
+		b := make([]complex128, 12)
+		PlanDft1d(x, a, Forward, Estimate).Execute()
+		SetStrict(true)
+		PlanDft1d(x, b, Forward, Measure).Execute()
+		SetStrict(false)
+		for i := range a {
+			c.Expect(real(b[i]), gospec.IsWithin(1e-9), real(a[i]))
+			c.Expect(imag(b[i]), gospec.IsWithin(1e-9), imag(a[i]))
+		}
+	})
+
+	c.Specify("Strict mode ignores WisdomOnly.", func() {
+		SetStrict(true)
+		x := make([]complex128, 8)
+		c.Expect(PlanDft1d(x, x, Forward, Estimate|WisdomOnly), gospec.Not(gospec.IsNil))
+	})
+}
+
+func FlagSpec(c gospec.Context) {
+	c.Specify("Flags print as the flags they are made of.", func() {
+		c.Expect(Measure.String(), gospec.Equals, "Measure")
+		c.Expect((Estimate | PreserveInput).String(), gospec.Equals, "Estimate|PreserveInput")
+		c.Expect((Patient | Unaligned | ConserveMemory).String(), gospec.Equals, "Patient|Unaligned|ConserveMemory")
+	})
+
+	c.Specify("WisdomOnly plans fail without wisdom.", func() {
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		x := make([]complex128, 8)
+		PlanDft1d(x, x, Forward, Estimate|WisdomOnly)
+	})
+
+	c.Specify("Every effort gives the same transform.", func() {
+		for _, f := range []Flag{Estimate, Measure, Patient, Exhaustive | DestroyInput, Measure | Unaligned | ConserveMemory} {
+			in := make([]complex128, 6)
+			out := make([]complex128, 6)
+			p := PlanDft1d(in, out, Forward, f)
+			in[0] = 2
+			p.Execute()
+			for _, v := range out {
+				c.Expect(real(v), gospec.IsWithin(1e-12), 2.0)
+			}
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Strides say where the elements of a batch of one dimensional transforms
+// are in an array: element j of transform i is at i*Dist + j*Stride.  For
+// example, channel c of interleaved audio with k channels starts at index c
+// with a Stride of k, and all k channels are a batch with a Stride of k and
+// a Dist of 1.
+type Strides struct {
+	Stride, Dist int
+}
+
+// check panics unless n elements of each of howmany transforms laid out as
+// s fit in an array of length size.
+func (s Strides) check(name string, n, howmany, size int) {
+	if s.Stride < 1 || s.Dist < 0 || (howmany > 1 && s.Dist < 1) {
+		panic(fmt.Sprint(name, " needs a positive stride and distance, got ", s))
+	}
+	if last := (howmany-1)*s.Dist + (n-1)*s.Stride; last >= size {
+		panic(fmt.Sprint(name, " reaches index ", last, " of an array of length ", size))
+	}
+}
+
+func checkStrided(name string, n, howmany int) {
+	if n < 1 || howmany < 1 {
+		panic(fmt.Sprint(name, " needs a positive length and count, got ", n, " and ", howmany))
+	}
+}
+
+// PlanDftStrided plans howmany complex transforms of length n at once, on
+// the elements of in and out laid out as is and os say, which saves copying
+// data in and out of contiguous arrays.
+func PlanDftStrided(n, howmany int, in []complex128, is Strides, out []complex128, os Strides, dir Direction, flag Flag) *Plan {
+	checkStrided("PlanDftStrided", n, howmany)
+	is.check("PlanDftStrided", n, howmany, len(in))
+	os.check("PlanDftStrided", n, howmany, len(out))
+	nn := cInt(n)
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft(1, &nn, cInt(howmany),
+		fftw_in, nil, cInt(is.Stride), cInt(is.Dist),
+		fftw_out, nil, cInt(os.Stride), cInt(os.Dist),
+		C.int(dir), planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: C2C, Dims: []int{n}, Dir: dir, Batch: howmany, In: is, Out: os}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftR2CStrided plans howmany real-to-complex transforms of length n
+// at once, on the elements of in and out laid out as is and os say.  Each
+// transform has n/2+1 outputs.
+func PlanDftR2CStrided(n, howmany int, in []float64, is Strides, out []complex128, os Strides, flag Flag) *Plan {
+	checkStrided("PlanDftR2CStrided", n, howmany)
+	is.check("PlanDftR2CStrided", n, howmany, len(in))
+	os.check("PlanDftR2CStrided", n/2+1, howmany, len(out))
+	nn := cInt(n)
+	fftw_in := (*C.double)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.fftw_complex)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft_r2c(1, &nn, cInt(howmany),
+		fftw_in, nil, cInt(is.Stride), cInt(is.Dist),
+		fftw_out, nil, cInt(os.Stride), cInt(os.Dist),
+		planFlags(flag))
+	planner.Unlock()
+	return newPlan(p, Geometry{Kind: R2C, Dims: []int{n}, Dir: Forward, Batch: howmany, In: is, Out: os}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
+
+// PlanDftC2RStrided plans howmany complex-to-real transforms of length n at
+// once, the inverse of PlanDftR2CStrided.  Like every complex-to-real
+// transform it overwrites its input unless flag includes PreserveInput.
+func PlanDftC2RStrided(n, howmany int, in []complex128, is Strides, out []float64, os Strides, flag Flag) *Plan {
+	checkStrided("PlanDftC2RStrided", n, howmany)
+	is.check("PlanDftC2RStrided", n/2+1, howmany, len(in))
+	os.check("PlanDftC2RStrided", n, howmany, len(out))
+	nn := cInt(n)
+	fftw_in := (*C.fftw_complex)(unsafe.Pointer(&in[0]))
+	fftw_out := (*C.double)(unsafe.Pointer(&out[0]))
+	planner.Lock()
+	p := C.fftw_plan_many_dft_c2r(1, &nn, cInt(howmany),
+		fftw_in, nil, cInt(is.Stride), cInt(is.Dist),
+		fftw_out, nil, cInt(os.Stride), cInt(os.Dist),
+		planFlags(flag))
+	planner.Unlock()
+	if p == nil {
+		panic(fmt.Sprint("fftw could not plan ", howmany, " complex-to-real transforms of length ", n, " with flags ", flag))
+	}
+	return newPlan(p, Geometry{Kind: C2R, Dims: []int{n}, Dir: Backward, Batch: howmany, In: is, Out: os}, unsafe.Pointer(fftw_in), unsafe.Pointer(fftw_out))
+}
@@ -0,0 +1,68 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+)
+
+func StridedSpec(c gospec.Context) {
+	// Eight frames of three interleaved channels.
+	const n, channels = 8, 3
+	x := make([]float64, n*channels)
+	for i := range x {
+		x[i] = float64(i*i%11) - 5
+	}
+	channel := func(ch int) []float64 {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = x[i*channels+ch]
+		}
+		return out
+	}
+
+	c.Specify("One channel of interleaved data can be transformed in place.", func() {
+		out := make([]complex128, n/2+1)
+		PlanDftR2CStrided(n, 1, x[1:], Strides{Stride: channels}, out, Strides{Stride: 1}, Estimate).Execute()
+		want := make([]complex128, n/2+1)
+		PlanDftR2C1d(channel(1), want, Estimate).Execute()
+		for k := range want {
+			c.Expect(cmplx.Abs(out[k]-want[k]), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("Every channel can be transformed and restored as a batch.", func() {
+		spectra := make([]complex128, (n/2+1)*channels)
+		PlanDftR2CStrided(n, channels, x, Strides{channels, 1}, spectra, Strides{channels, 1}, Estimate).Execute()
+		for ch := 0; ch < channels; ch++ {
+			want := make([]complex128, n/2+1)
+			PlanDftR2C1d(channel(ch), want, Estimate).Execute()
+			for k := range want {
+				c.Expect(cmplx.Abs(spectra[k*channels+ch]-want[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		back := make([]float64, len(x))
+		PlanDftC2RStrided(n, channels, spectra, Strides{channels, 1}, back, Strides{channels, 1}, Estimate).Execute()
+		for i := range x {
+			c.Expect(back[i]/n, gospec.IsWithin(1e-9), x[i])
+		}
+	})
+
+	c.Specify("Complex transforms can write strided output.", func() {
+		in := []complex128{1, 2i, -1, 3}
+		out := make([]complex128, 8)
+		p := PlanDftStrided(4, 1, in, Strides{Stride: 1}, out, Strides{Stride: 2}, Forward, Estimate)
+		p.Execute()
+		want := make([]complex128, 4)
+		PlanDft1d(in, want, Forward, Estimate).Execute()
+		for k := range want {
+			c.Expect(cmplx.Abs(out[2*k]-want[k]), gospec.IsWithin(1e-9), 0.0)
+			c.Expect(out[2*k+1], gospec.Equals, complex128(0))
+		}
+		c.Expect(p.Geometry().Out, gospec.Equals, Strides{Stride: 2})
+	})
+
+	c.Specify("Strides that run off the end of an array are refused.", func() {
+		defer func() { c.Expect(recover() != nil, gospec.IsTrue) }()
+		PlanDftR2CStrided(n, 1, x[1:], Strides{Stride: channels + 1}, make([]complex128, n/2+1), Strides{Stride: 1}, Estimate)
+	})
+}
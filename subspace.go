@@ -0,0 +1,116 @@
+package fftw
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// Autocorrelation returns the biased autocorrelation estimate of x,
+// r[l] = sum(x[i]*x[i+l])/len(x), for lags 0 through maxLag.  It is computed
+// from the power spectrum of x, zero padded so that the circular correlation
+// doesn't wrap around.
+func Autocorrelation(x []float64, maxLag int) []float64 {
+	n := len(x)
+	if maxLag < 0 || maxLag >= n {
+		panic(fmt.Sprint("Autocorrelation needs a lag between 0 and ", n-1, ", got ", maxLag))
+	}
+	size := n + maxLag
+	signal := make([]float64, size)
+	copy(signal, x)
+	F_signal := make([]complex128, size/2+1)
+	PlanDftR2C1d(signal, F_signal, Estimate).Execute()
+	for k, v := range F_signal {
+		F_signal[k] = complex(real(v)*real(v)+imag(v)*imag(v), 0)
+	}
+	PlanDftC2R1d(F_signal, signal, Estimate).Execute()
+	r := signal[:maxLag+1]
+	ScaleReal(r, 1/float64(size*n))
+	return r
+}
+
+// AutocorrelationMatrix returns the m x m Toeplitz matrix of the
+// autocorrelation of x, whose entry (i, j) is r[|i-j|].
+func AutocorrelationMatrix(x []float64, m int) *mat.SymDense {
+	r := Autocorrelation(x, m-1)
+	R := mat.NewSymDense(m, nil)
+	for i := 0; i < m; i++ {
+		for j := i; j < m; j++ {
+			R.SetSym(i, j, r[j-i])
+		}
+	}
+	return R
+}
+
+// subspaces returns the eigenvectors of the m x m autocorrelation matrix of
+// x, split into the p with the largest eigenvalues, which span the signal
+// subspace, and the rest, which span the noise subspace.
+func subspaces(x []float64, m, p int) (signal, noise *mat.Dense) {
+	if p < 1 || p >= m {
+		panic(fmt.Sprint("Subspace estimation needs between 1 and ", m-1, " signal dimensions, got ", p))
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(AutocorrelationMatrix(x, m), true) {
+		panic("Could not find the eigenvectors of the autocorrelation matrix")
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	// Eigenvalues come in ascending order.
+	noise = mat.DenseCopyOf(vectors.Slice(0, m, 0, m-p))
+	signal = mat.DenseCopyOf(vectors.Slice(0, m, m-p, m))
+	return signal, noise
+}
+
+// MUSIC returns the MUSIC pseudospectrum of x at each of freqs, in cycles
+// per sample, using an m x m autocorrelation matrix of which p dimensions
+// are signal.  A real sinusoid takes up two dimensions, so p is twice the
+// number of sinusoids.  The pseudospectrum peaks sharply at the frequencies
+// of the sinusoids, resolving tones much closer together than a DFT of x
+// can, but its height says nothing about their power.
+func MUSIC(x []float64, m, p int, freqs []float64) []float64 {
+	_, noise := subspaces(x, m, p)
+	_, cols := noise.Dims()
+	out := make([]float64, len(freqs))
+	for i, f := range freqs {
+		sum := 0.0
+		for j := 0; j < cols; j++ {
+			var dot complex128
+			for k := 0; k < m; k++ {
+				dot += cmplx.Rect(noise.At(k, j), -2*math.Pi*f*float64(k))
+			}
+			sum += real(dot)*real(dot) + imag(dot)*imag(dot)
+		}
+		out[i] = 1 / sum
+	}
+	return out
+}
+
+// ESPRIT estimates the frequencies, in cycles per sample and in ascending
+// order, of the p/2 real sinusoids in x from the rotational invariance of
+// the signal subspace of its m x m autocorrelation matrix.
+func ESPRIT(x []float64, m, p int) []float64 {
+	signal, _ := subspaces(x, m, p)
+	// The signal subspace shifted by one sample is the unshifted one rotated
+	// by a matrix whose eigenvalues are exp(+-2*pi*i*f).
+	u1 := signal.Slice(0, m-1, 0, p)
+	u2 := signal.Slice(1, m, 0, p)
+	var phi mat.Dense
+	if err := phi.Solve(u1, u2); err != nil {
+		panic(fmt.Sprint("ESPRIT could not solve for the rotation: ", err))
+	}
+	var eig mat.Eigen
+	if !eig.Factorize(&phi, mat.EigenNone) {
+		panic("ESPRIT could not find the eigenvalues of the rotation")
+	}
+	var freqs []float64
+	for _, v := range eig.Values(nil) {
+		// Keep one of each conjugate pair.
+		if f := cmplx.Phase(v) / (2 * math.Pi); f > 0 {
+			freqs = append(freqs, f)
+		}
+	}
+	sort.Float64s(freqs)
+	return freqs
+}
@@ -0,0 +1,43 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/rand"
+)
+
+func SubspaceSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(11))
+	x := make([]float64, 256)
+	for i := range x {
+		t := float64(i)
+		x[i] = math.Cos(2*math.Pi*0.1*t) + 0.5*math.Cos(2*math.Pi*0.13*t+1) + 0.01*rng.NormFloat64()
+	}
+
+	c.Specify("The FFT autocorrelation matches the direct sum.", func() {
+		r := Autocorrelation(x, 5)
+		for l := range r {
+			sum := 0.0
+			for i := 0; i+l < len(x); i++ {
+				sum += x[i] * x[i+l]
+			}
+			c.Expect(r[l], gospec.IsWithin(1e-9), sum/float64(len(x)))
+		}
+		R := AutocorrelationMatrix(x, 4)
+		c.Expect(R.At(3, 1), gospec.IsWithin(1e-12), r[2])
+	})
+
+	c.Specify("ESPRIT finds the frequencies of the sinusoids.", func() {
+		f := ESPRIT(x, 16, 4)
+		c.Expect(len(f), gospec.Equals, 2)
+		c.Expect(f[0], gospec.IsWithin(2e-3), 0.1)
+		c.Expect(f[1], gospec.IsWithin(2e-3), 0.13)
+	})
+
+	c.Specify("The MUSIC pseudospectrum peaks at the sinusoids.", func() {
+		p := MUSIC(x, 16, 4, []float64{0.1, 0.115, 0.13, 0.3})
+		c.Expect(p[0] > 10*p[1], gospec.IsTrue)
+		c.Expect(p[2] > 10*p[1], gospec.IsTrue)
+		c.Expect(p[0] > 10*p[3], gospec.IsTrue)
+	})
+}
@@ -0,0 +1,66 @@
+package fftw
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// A SwappablePlan executes a plan that can be replaced while other
+// goroutines are executing it, such as by a better plan found by a
+// background Measure, or one made after importing wisdom, without pausing
+// the stream it is transforming.
+//
+// It always transforms the arrays of the plan it was made with.  Replacement
+// plans may be planned on other arrays, which keeps planning with Measure
+// from overwriting the live data, as long as those arrays have the same
+// alignment, as arrays from Alloc1d do.
+type SwappablePlan struct {
+	plan atomic.Value
+	// The arrays to transform, and the plan they were planned with.
+	base *Plan
+}
+
+// NewSwappablePlan returns a SwappablePlan that starts out executing p.
+func NewSwappablePlan(p *Plan) *SwappablePlan {
+	if p.prepare != nil {
+		panic("Plans that emulate PreserveInput can't be swapped")
+	}
+	if p.geom.Split {
+		panic("Split plans can't be swapped")
+	}
+	s := &SwappablePlan{base: p}
+	s.plan.Store(p)
+	return s
+}
+
+// Plan returns the plan s is currently executing.
+func (s *SwappablePlan) Plan() *Plan {
+	return s.plan.Load().(*Plan)
+}
+
+// Execute executes the current plan on s's arrays.  Executions already
+// under way when the plan is swapped finish with the old plan.
+func (s *SwappablePlan) Execute() {
+	p := s.Plan()
+	if p == s.base {
+		p.Execute()
+		return
+	}
+	p.executeOn(s.base.in, s.base.out)
+}
+
+// Swap makes p the plan that later executions use, and returns the plan it
+// replaces.  p must have the same geometry as s's plans and arrays of the
+// same alignment.
+func (s *SwappablePlan) Swap(p *Plan) *Plan {
+	if !p.geom.Equal(s.base.geom) {
+		panic(fmt.Sprint("Can't swap a plan of geometry ", p.geom, " for one of ", s.base.geom))
+	}
+	if p.prepare != nil {
+		panic("Plans that emulate PreserveInput can't be swapped")
+	}
+	if p.inAlign != s.base.inAlign || p.outAlign != s.base.outAlign {
+		panic("Can't swap in a plan for arrays of a different alignment")
+	}
+	return s.plan.Swap(p).(*Plan)
+}
@@ -0,0 +1,66 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"sync"
+)
+
+func SwappablePlanSpec(c gospec.Context) {
+	data := Alloc1d(32)
+	s := NewSwappablePlan(PlanDft1d(data, data, Forward, Estimate))
+
+	c.Specify("Swapped in plans transform the original arrays.", func() {
+		// Measuring on other arrays leaves the live data alone.
+		scratch := Alloc1d(32)
+		measured := PlanDft1d(scratch, scratch, Forward, Measure)
+		for i := range data {
+			data[i] = complex(float64(i%4), 0)
+		}
+		old := s.Swap(measured)
+		c.Expect(old == measured, gospec.IsFalse)
+		c.Expect(s.Plan() == measured, gospec.IsTrue)
+		s.Execute()
+		// The signal has period 4, so only every eighth bin is nonzero.
+		c.Expect(real(data[0]), gospec.IsWithin(1e-9), 48.0)
+		for k := 1; k < 32; k++ {
+			if k%8 != 0 {
+				c.Expect(real(data[k]), gospec.IsWithin(1e-9), 0.0)
+				c.Expect(imag(data[k]), gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+		for i := range scratch {
+			c.Expect(scratch[i], gospec.Equals, complex(0, 0))
+		}
+	})
+
+	c.Specify("Plans can be swapped while executing.", func() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s.Execute()
+			}
+		}()
+		for i := 0; i < 20; i++ {
+			scratch := Alloc1d(32)
+			s.Swap(PlanDft1d(scratch, scratch, Forward, Estimate))
+		}
+		wg.Wait()
+	})
+
+	c.Specify("Plans of other sizes can't be swapped in.", func() {
+		other := Alloc1d(16)
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		s.Swap(PlanDft1d(other, other, Forward, Estimate))
+	})
+
+	c.Specify("Plans in other directions can't be swapped in.", func() {
+		defer func() {
+			c.Expect(recover() != nil, gospec.IsTrue)
+		}()
+		s.Swap(PlanDft1d(data, data, Backward, Estimate))
+	})
+}
@@ -0,0 +1,45 @@
+package fftw
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Synchrosqueeze returns the synchrosqueezed STFT of x, which sharpens the
+// ridges of slowly varying tones and chirps into single bins.  Each
+// coefficient of the ordinary STFT whose magnitude exceeds threshold is
+// moved to the bin of its instantaneous frequency, estimated from the STFT
+// taken with the derivative of the window.  Bin l of each frame of the
+// result holds the sum of the coefficients of that frame moved to l, and is
+// zero if none were.
+//
+// Before they are summed, the coefficients are phase referenced to the
+// centre of the frame rather than its start, so that the bins of a tone add
+// up coherently: coefficient k of a frame of length n is multiplied by
+// exp(2*pi*i*k*floor(n/2)/n), which is (-1)^k when n is even.
+func (s *STFT) Synchrosqueeze(x []float64, threshold float64) [][]complex128 {
+	vg := s.Transform(x)
+	vd := s.transform(x, s.derivativeWindow())
+	bins := s.n/2 + 1
+	out := make([][]complex128, len(vg))
+	data := make([]complex128, len(vg)*bins)
+	for t := range vg {
+		out[t] = data[t*bins : (t+1)*bins]
+		for k, v := range vg[t] {
+			if cmplx.Abs(v) <= threshold {
+				continue
+			}
+			// The instantaneous frequency in cycles per sample.
+			f := float64(k)/float64(s.n) - imag(vd[t][k]/v)/(2*math.Pi)
+			l := int(math.Floor(f*float64(s.n) + 0.5))
+			if l < 0 {
+				l = 0
+			}
+			if l >= bins {
+				l = bins - 1
+			}
+			out[t][l] += v * cmplx.Rect(1, 2*math.Pi*float64(k)*float64(s.n/2)/float64(s.n))
+		}
+	}
+	return out
+}
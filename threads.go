@@ -0,0 +1,131 @@
+package fftw
+
+// #cgo linux LDFLAGS: -ldl
+// #define _GNU_SOURCE
+// #include <fftw3.h>
+// #include <dlfcn.h>
+//
+// // make_planner_thread_safe calls fftw_make_planner_thread_safe if fftw
+// // has it, looking it up at run time so that older versions still link.
+// static void make_planner_thread_safe(void) {
+//   void (*f)(void) = (void (*)(void))dlsym(RTLD_DEFAULT, "fftw_make_planner_thread_safe");
+//   if (f != NULL) {
+//     f();
+//   }
+// }
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ThreadOptions controls the worker threads that run multithreaded plans.
+type ThreadOptions struct {
+	// CPUs lists the CPUs workers may run on, or is empty to let them run on
+	// any, for example to keep them off the cores serving an audio callback.
+	CPUs []int
+	// Priority is the SCHED_FIFO real-time priority of workers, from 1 to
+	// 99, or 0 to leave them with the default scheduling.  Real-time
+	// priorities usually need privileges.
+	Priority int
+}
+
+var (
+	initThreadsLock sync.Mutex
+	threadsReady    bool
+)
+
+// initThreads prepares fftw for multithreaded plans, once, or again after
+// Cleanup.
+func initThreads() {
+	initThreadsLock.Lock()
+	defer initThreadsLock.Unlock()
+	if !threadsReady {
+		if C.fftw_init_threads() == 0 {
+			panic("fftw could not initialize threads")
+		}
+		threadsReady = true
+	}
+}
+
+// planner serializes every plan the package makes or destroys, from any
+// goroutine, since fftw's planner isn't safe for concurrent use.  fftw's
+// own planner lock, which serializes planning just as well, is only in
+// FFTW 3.3.5 and later, so the package doesn't rely on it.
+var planner sync.Mutex
+
+// makePlannerThreadSafe turns on fftw's planner lock where fftw has it, so
+// that other code in the process planning with fftw is safe alongside the
+// package.
+func makePlannerThreadSafe() {
+	C.make_planner_thread_safe()
+}
+
+func init() {
+	makePlannerThreadSafe()
+}
+
+// threadsLock guards planThreads and fftw's own thread count, which the
+// planner reads when it makes a plan.
+var threadsLock sync.Mutex
+
+// planThreads is the number of threads plans are made with.
+var planThreads = 1
+
+// SetThreads makes plans made afterwards run on n threads, or on
+// GOMAXPROCS threads if n is 0.  Plans already made keep the number they
+// were made with.  Threads only pay for large transforms; plans are made
+// single threaded until SetThreads is called.
+func SetThreads(n int) {
+	if n < 0 {
+		panic(fmt.Sprint("SetThreads needs a non-negative number of threads, got ", n))
+	}
+	if n == 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	threadsLock.Lock()
+	defer threadsLock.Unlock()
+	setThreads(n)
+}
+
+func setThreads(n int) {
+	initThreads()
+	C.fftw_plan_with_nthreads(C.int(n))
+	planThreads = n
+}
+
+// Threads returns the number of threads plans are made with.
+func Threads() int {
+	threadsLock.Lock()
+	defer threadsLock.Unlock()
+	return planThreads
+}
+
+// ThreadBackend returns the fftw threading library the package is linked
+// against: "pthreads", fftw3_threads, by default, or "openmp", fftw3_omp,
+// when built with the omp tag.
+func ThreadBackend() string {
+	return threadBackend
+}
+
+// withThreads makes plans with n threads, if n is positive, until the
+// function it returns is called, holding off SetThreads and other plans
+// asking for their own count until then.
+func withThreads(n int) func() {
+	if n < 1 {
+		return func() {}
+	}
+	threadsLock.Lock()
+	old := planThreads
+	if n != old {
+		setThreads(n)
+	}
+	return func() {
+		if n != old {
+			setThreads(old)
+		}
+		threadsLock.Unlock()
+	}
+}
@@ -0,0 +1,194 @@
+package fftw
+
+// #cgo LDFLAGS: -lpthread -ldl
+// #define _GNU_SOURCE
+// #include <fftw3.h>
+// #include <dlfcn.h>
+// #include <errno.h>
+// #include <pthread.h>
+// #include <sched.h>
+// #include <stddef.h>
+//
+// static pthread_mutex_t options_lock = PTHREAD_MUTEX_INITIALIZER;
+// static cpu_set_t options_cpus;
+// static int options_have_cpus;
+// static int options_priority;
+// static int options_installed;
+//
+// typedef void (*parallel_loop_func)(void *(*)(char *), char *, size_t, int, void *);
+// typedef void (*set_callback_func)(parallel_loop_func, void *);
+//
+// // threads_set_callback finds fftw_threads_set_callback, which is only in
+// // FFTW 3.3.9 and later, at run time, so that older versions still link.
+// static set_callback_func threads_set_callback(void) {
+//   return (set_callback_func)dlsym(RTLD_DEFAULT, "fftw_threads_set_callback");
+// }
+//
+// struct job {
+//   void *(*work)(char *);
+//   char *data;
+// };
+//
+// static void *run_job(void *arg) {
+//   struct job *j = arg;
+//   return j->work ? j->work(j->data) : NULL;
+// }
+//
+// static int start_worker(pthread_t *t, struct job *j) {
+//   pthread_attr_t attr;
+//   pthread_attr_init(&attr);
+//   pthread_mutex_lock(&options_lock);
+//   if (options_have_cpus) {
+//     pthread_attr_setaffinity_np(&attr, sizeof(options_cpus), &options_cpus);
+//   }
+//   if (options_priority > 0) {
+//     struct sched_param sp = { .sched_priority = options_priority };
+//     pthread_attr_setinheritsched(&attr, PTHREAD_EXPLICIT_SCHED);
+//     pthread_attr_setschedpolicy(&attr, SCHED_FIFO);
+//     pthread_attr_setschedparam(&attr, &sp);
+//   }
+//   pthread_mutex_unlock(&options_lock);
+//   int err = pthread_create(t, &attr, run_job, j);
+//   pthread_attr_destroy(&attr);
+//   return err;
+// }
+//
+// // parallel_loop runs each of fftw's jobs on a worker thread started with
+// // the current options, or on the calling thread if one can't be started.
+// static void parallel_loop(void *(*work)(char *), char *jobdata, size_t elsize, int njobs, void *data) {
+//   pthread_t threads[njobs];
+//   struct job jobs[njobs];
+//   int started[njobs];
+//   for (int i = 0; i < njobs; i++) {
+//     jobs[i].work = work;
+//     jobs[i].data = jobdata + i * elsize;
+//     started[i] = start_worker(&threads[i], &jobs[i]) == 0;
+//     if (!started[i]) {
+//       work(jobs[i].data);
+//     }
+//   }
+//   for (int i = 0; i < njobs; i++) {
+//     if (started[i]) {
+//       pthread_join(threads[i], NULL);
+//     }
+//   }
+// }
+//
+// static int set_thread_options(int *cpus, int ncpus, int priority) {
+//   set_callback_func set_callback = threads_set_callback();
+//   if (set_callback == NULL) {
+//     return ENOSYS;
+//   }
+//   if (ncpus == 0 && priority == 0) {
+//     // Hand the workers back to fftw's own pool.
+//     pthread_mutex_lock(&options_lock);
+//     options_have_cpus = 0;
+//     options_priority = 0;
+//     options_installed = 0;
+//     pthread_mutex_unlock(&options_lock);
+//     set_callback(NULL, NULL);
+//     return 0;
+//   }
+//   cpu_set_t set;
+//   CPU_ZERO(&set);
+//   for (int i = 0; i < ncpus; i++) {
+//     CPU_SET(cpus[i], &set);
+//   }
+//   pthread_mutex_lock(&options_lock);
+//   cpu_set_t old_cpus = options_cpus;
+//   int old_have_cpus = options_have_cpus, old_priority = options_priority;
+//   options_cpus = set;
+//   options_have_cpus = ncpus > 0;
+//   options_priority = priority;
+//   pthread_mutex_unlock(&options_lock);
+//
+//   // Check that the platform permits the options by starting a worker.
+//   struct job j = { NULL, NULL };
+//   pthread_t t;
+//   int err = start_worker(&t, &j);
+//   if (err != 0) {
+//     pthread_mutex_lock(&options_lock);
+//     options_cpus = old_cpus;
+//     options_have_cpus = old_have_cpus;
+//     options_priority = old_priority;
+//     pthread_mutex_unlock(&options_lock);
+//     return err;
+//   }
+//   pthread_join(t, NULL);
+//   set_callback(parallel_loop, NULL);
+//   pthread_mutex_lock(&options_lock);
+//   options_installed = 1;
+//   pthread_mutex_unlock(&options_lock);
+//   return 0;
+// }
+//
+// static int thread_options_installed(void) {
+//   pthread_mutex_lock(&options_lock);
+//   int installed = options_installed;
+//   pthread_mutex_unlock(&options_lock);
+//   return installed;
+// }
+//
+// static void *record_cpu(char *data) {
+//   *(int *)data = sched_getcpu();
+//   return NULL;
+// }
+//
+// static void worker_cpus(int *cpus, int n) {
+//   parallel_loop(record_cpu, (char *)cpus, sizeof(int), n, NULL);
+// }
+import "C"
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// SetThreadOptions makes the workers of multithreaded plans run as opts
+// says, starting a worker to check that the platform permits it and
+// returning the error if it doesn't.  Options apply to workers started
+// afterwards, and a worker that later can't be started with them runs its
+// share of a transform on the calling thread instead.  Workers are started
+// for each execution, so that they never outlive it.  The zero
+// ThreadOptions hands the workers back to fftw's own thread pool.
+// SetThreadOptions needs FFTW 3.3.9 or later, and returns an error with
+// older versions.
+func SetThreadOptions(opts ThreadOptions) error {
+	if opts.Priority < 0 || opts.Priority > 99 {
+		return fmt.Errorf("thread priority %d is outside 0 to 99", opts.Priority)
+	}
+	cpus := make([]C.int, len(opts.CPUs)+1)
+	for i, cpu := range opts.CPUs {
+		if cpu < 0 || cpu >= C.CPU_SETSIZE {
+			return fmt.Errorf("CPU %d is outside 0 to %d", cpu, C.CPU_SETSIZE-1)
+		}
+		cpus[i] = C.int(cpu)
+	}
+	initThreads()
+	err := C.set_thread_options(&cpus[0], C.int(len(opts.CPUs)), C.int(opts.Priority))
+	if err == C.ENOSYS {
+		return fmt.Errorf("thread options need FFTW 3.3.9 or later")
+	}
+	if err != 0 {
+		return fmt.Errorf("can't start threads with options %+v: %v", opts, syscall.Errno(err))
+	}
+	return nil
+}
+
+// workerCPUs runs n jobs as a multithreaded plan would and returns the CPU
+// each ran on.
+func workerCPUs(n int) []int {
+	cpus := make([]C.int, n)
+	C.worker_cpus(&cpus[0], C.int(n))
+	out := make([]int, n)
+	for i, cpu := range cpus {
+		out[i] = int(cpu)
+	}
+	return out
+}
+
+// threadOptionsInstalled reports whether workers are started by the
+// package, as options say, rather than by fftw.
+func threadOptionsInstalled() bool {
+	return C.thread_options_installed() != 0
+}
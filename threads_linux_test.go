@@ -0,0 +1,38 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"testing"
+)
+
+func ThreadOptionsSpec(c gospec.Context) {
+	defer SetThreadOptions(ThreadOptions{})
+
+	c.Specify("Workers run on the CPUs they are given.", func() {
+		c.Expect(SetThreadOptions(ThreadOptions{CPUs: []int{0}}), gospec.IsNil)
+		for _, cpu := range workerCPUs(4) {
+			c.Expect(cpu, gospec.Equals, 0)
+		}
+	})
+
+	c.Specify("Zero options hand the workers back to fftw.", func() {
+		c.Expect(SetThreadOptions(ThreadOptions{CPUs: []int{0}}), gospec.IsNil)
+		c.Expect(threadOptionsInstalled(), gospec.IsTrue)
+		c.Expect(SetThreadOptions(ThreadOptions{}), gospec.IsNil)
+		c.Expect(threadOptionsInstalled(), gospec.IsFalse)
+	})
+
+	c.Specify("Options the platform can't honor are refused.", func() {
+		c.Expect(SetThreadOptions(ThreadOptions{CPUs: []int{-1}}), gospec.Not(gospec.IsNil))
+		c.Expect(SetThreadOptions(ThreadOptions{CPUs: []int{1 << 20}}), gospec.Not(gospec.IsNil))
+		c.Expect(SetThreadOptions(ThreadOptions{Priority: 100}), gospec.Not(gospec.IsNil))
+		c.Expect(len(workerCPUs(2)), gospec.Equals, 2)
+	})
+}
+
+// ThreadOptionsSpec is only built on Linux, so it has its own runner.
+func TestThreadOptions(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(ThreadOptionsSpec)
+	gospec.MainGoTest(r, t)
+}
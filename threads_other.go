@@ -0,0 +1,15 @@
+//go:build !linux
+
+package fftw
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// SetThreadOptions makes the workers of multithreaded plans run as opts
+// says.  Only Linux lets fftw's workers be controlled, elsewhere it returns
+// an error.
+func SetThreadOptions(opts ThreadOptions) error {
+	return fmt.Errorf("thread options aren't supported on %s", runtime.GOOS)
+}
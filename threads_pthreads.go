@@ -0,0 +1,8 @@
+//go:build !omp
+
+package fftw
+
+// #cgo LDFLAGS: -lfftw3_threads -lpthread
+import "C"
+
+const threadBackend = "pthreads"
@@ -0,0 +1,67 @@
+package fftw
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func ThreadsSpec(c gospec.Context) {
+	defer SetThreads(1)
+
+	c.Specify("SetThreads defaults to GOMAXPROCS.", func() {
+		SetThreads(3)
+		c.Expect(Threads(), gospec.Equals, 3)
+		SetThreads(0)
+		c.Expect(Threads(), gospec.Equals, runtime.GOMAXPROCS(0))
+	})
+
+	c.Specify("The threading backend is one fftw ships.", func() {
+		c.Expect(ThreadBackend() == "pthreads" || ThreadBackend() == "openmp", gospec.IsTrue)
+	})
+
+	c.Specify("Threaded plans transform as single threaded ones do.", func() {
+		SetThreads(4)
+		in := make([]complex128, 64)
+		out := make([]complex128, 64)
+		in[1] = 1
+		PlanDft1d(in, out, Forward, Estimate).Execute()
+		c.Expect(real(out[16]), gospec.IsWithin(1e-12), 0.0)
+		c.Expect(imag(out[16]), gospec.IsWithin(1e-12), -1.0)
+	})
+
+	c.Specify("Plan specs make plans with their own thread count.", func() {
+		SetThreads(2)
+		s, _ := ParsePlanSpec("r2c 8 threads=6")
+		in := make([]float64, 8)
+		in[0] = 1
+		out := make([]complex128, 5)
+		s.PlanDftR2C(in, out).Execute()
+		c.Expect(Threads(), gospec.Equals, 2)
+		c.Expect(real(out[3]), gospec.IsWithin(1e-12), 1.0)
+	})
+
+	c.Specify("Plans can be made from many goroutines at once.", func() {
+		var wg sync.WaitGroup
+		results := make([]complex128, 16)
+		for g := range results {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				n := 8 + g
+				in := make([]complex128, n)
+				out := make([]complex128, n)
+				in[0] = complex(float64(g), 0)
+				s := PlanSpec{Kind: C2C, Dims: []int{n}, Dir: Forward, Flag: Estimate, Threads: 1 + g%3}
+				s.PlanDft(in, out).Execute()
+				results[g] = out[n-1]
+				runtime.GC()
+			}(g)
+		}
+		wg.Wait()
+		for g, v := range results {
+			c.Expect(real(v), gospec.IsWithin(1e-12), float64(g))
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package fftw
+
+// #include <fftw3.h>
+import "C"
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	timeLimitLock sync.Mutex
+	timeLimit     time.Duration
+)
+
+// SetPlanTimeLimit bounds the time Measure and the flags like it spend
+// planning each transform made afterwards to roughly d, after which fftw
+// takes the best plan it has found so far.  A d of 0 or less removes the
+// limit.  Estimate plans take no measurable time either way.
+func SetPlanTimeLimit(d time.Duration) {
+	timeLimitLock.Lock()
+	defer timeLimitLock.Unlock()
+	if d <= 0 {
+		d = 0
+		C.fftw_set_timelimit(C.FFTW_NO_TIMELIMIT)
+	} else {
+		C.fftw_set_timelimit(C.double(d.Seconds()))
+	}
+	timeLimit = d
+}
+
+// PlanTimeLimit returns the limit SetPlanTimeLimit last set, or 0 if there
+// is none.
+func PlanTimeLimit() time.Duration {
+	timeLimitLock.Lock()
+	defer timeLimitLock.Unlock()
+	return timeLimit
+}
@@ -0,0 +1,31 @@
+package fftw
+
+import (
+	"time"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func PlanTimeLimitSpec(c gospec.Context) {
+	defer SetPlanTimeLimit(0)
+
+	c.Specify("The limit is recorded until it is removed.", func() {
+		c.Expect(PlanTimeLimit(), gospec.Equals, time.Duration(0))
+		SetPlanTimeLimit(200 * time.Millisecond)
+		c.Expect(PlanTimeLimit(), gospec.Equals, 200*time.Millisecond)
+		SetPlanTimeLimit(-time.Second)
+		c.Expect(PlanTimeLimit(), gospec.Equals, time.Duration(0))
+	})
+
+	c.Specify("Limited plans still transform correctly.", func() {
+		SetPlanTimeLimit(time.Millisecond)
+		in := make([]complex128, 32)
+		out := make([]complex128, 32)
+		p := PlanDft1d(in, out, Forward, Measure)
+		in[0] = 1
+		p.Execute()
+		for _, v := range out {
+			c.Expect(real(v), gospec.IsWithin(1e-12), 1.0)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package fftw
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// A TransferEstimate is the frequency response of a system estimated from
+// its input and output by TransferFunction.
+type TransferEstimate struct {
+	Freqs []float64
+	// H1 = Pxy/Pxx is unbiased by noise on the output.
+	H1 []complex128
+	// H2 = Pyy/Pyx is unbiased by noise on the input.
+	H2 []complex128
+	// Coherence is |Pxy|^2/(Pxx*Pyy), one where the output is entirely
+	// explained by the input and falling towards zero as noise dominates.
+	Coherence []float64
+}
+
+// TransferFunction estimates the frequency response of the system that
+// turned input into output, averaging cross spectra over segments with
+// Welch's method.
+func TransferFunction(input, output []float64, opts WelchOptions) TransferEstimate {
+	if len(input) != len(output) {
+		panic(fmt.Sprint("TransferFunction needs signals of the same length, got ", len(input), " and ", len(output)))
+	}
+	length, taper, starts := opts.segments(len(input))
+	m := length/2 + 1
+	pxx := make([]float64, m)
+	pyy := make([]float64, m)
+	pxy := make([]complex128, m)
+	x := make([]float64, length)
+	y := make([]float64, length)
+	for _, s := range starts {
+		for i := range x {
+			x[i] = input[s+i] * taper[i]
+			y[i] = output[s+i] * taper[i]
+		}
+		X, Y := DftPair(x, y)
+		for k := range X {
+			pxx[k] += real(X[k])*real(X[k]) + imag(X[k])*imag(X[k])
+			pyy[k] += real(Y[k])*real(Y[k]) + imag(Y[k])*imag(Y[k])
+			pxy[k] += cmplx.Conj(X[k]) * Y[k]
+		}
+	}
+
+	var t TransferEstimate
+	t.Freqs = opts.freqs(length)
+	t.H1 = make([]complex128, m)
+	t.H2 = make([]complex128, m)
+	t.Coherence = make([]float64, m)
+	for k := 0; k < m; k++ {
+		if pxx[k] > 0 {
+			t.H1[k] = pxy[k] / complex(pxx[k], 0)
+		}
+		if pxy[k] != 0 {
+			t.H2[k] = complex(pyy[k], 0) / cmplx.Conj(pxy[k])
+		}
+		if pxx[k] > 0 && pyy[k] > 0 {
+			a := cmplx.Abs(pxy[k])
+			t.Coherence[k] = a * a / (pxx[k] * pyy[k])
+		}
+	}
+	return t
+}
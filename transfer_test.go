@@ -0,0 +1,55 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+	"math/rand"
+)
+
+func TransferFunctionSpec(c gospec.Context) {
+	rng := rand.New(rand.NewSource(2))
+	x := make([]float64, 8192)
+	for i := range x {
+		x[i] = rng.NormFloat64()
+	}
+	// y is x passed through the filter 0.5 + 0.25 z^-1.
+	y := make([]float64, len(x))
+	for i := range y {
+		y[i] = 0.5 * x[i]
+		if i > 0 {
+			y[i] += 0.25 * x[i-1]
+		}
+	}
+	opts := WelchOptions{SegmentLength: 128, Overlap: 64, SampleRate: 1000}
+	t := TransferFunction(x, y, opts)
+
+	c.Specify("Frequencies are labelled using the sample rate.", func() {
+		c.Expect(len(t.Freqs), gospec.Equals, 65)
+		c.Expect(t.Freqs[0], gospec.Equals, 0.0)
+		c.Expect(t.Freqs[64], gospec.IsWithin(1e-9), 500.0)
+	})
+
+	c.Specify("H1 and H2 recover a known filter with coherence near one.", func() {
+		for k, f := range t.Freqs {
+			h := complex(0.5, 0) + 0.25*cmplx.Exp(complex(0, -2*math.Pi*f/1000))
+			c.Expect(cmplx.Abs(t.H1[k]-h), gospec.IsWithin(0.02), 0.0)
+			c.Expect(cmplx.Abs(t.H2[k]-h), gospec.IsWithin(0.02), 0.0)
+			c.Expect(t.Coherence[k], gospec.IsWithin(0.02), 1.0)
+		}
+	})
+
+	c.Specify("Coherence drops when the output is unrelated noise.", func() {
+		z := make([]float64, len(x))
+		for i := range z {
+			z[i] = rng.NormFloat64()
+		}
+		u := TransferFunction(x, z, opts)
+		mean := 0.0
+		for _, v := range u.Coherence {
+			mean += v
+		}
+		mean /= float64(len(u.Coherence))
+		c.Expect(mean < 0.2, gospec.IsTrue)
+	})
+}
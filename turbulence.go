@@ -0,0 +1,186 @@
+package fftw
+
+import (
+	"math"
+)
+
+// realSpectrum returns the non-redundant half of the spectrum of the
+// row-major periodic field x of dimensions dims.
+func realSpectrum(dims []int, x []float64) []complex128 {
+	spec := PlanSpec{Kind: R2C, Dims: dims, Dir: Forward, Flag: Estimate}
+	_, n := spec.Size()
+	out := make([]complex128, n)
+	spec.PlanDftR2C(x, out).Execute()
+	return out
+}
+
+// forEachMode calls fn with the flat index, signed wavenumbers and
+// multiplicity of each bin of a half spectrum of dimensions dims.  Bins
+// of the halved last dimension other than 0 and n/2 stand for their
+// conjugates as well, and so count twice.
+func forEachMode(dims []int, fn func(i int, k []int, weight int)) {
+	last := dims[len(dims)-1]
+	half := append([]int(nil), dims...)
+	half[len(half)-1] = last/2 + 1
+	k := make([]int, len(dims))
+	idx := make([]int, len(dims))
+	for i := 0; ; i++ {
+		for d, j := range idx {
+			k[d] = j
+			if d < len(dims)-1 && 2*j > dims[d] {
+				k[d] -= dims[d]
+			}
+		}
+		w := 2
+		if j := idx[len(idx)-1]; j == 0 || 2*j == last {
+			w = 1
+		}
+		fn(i, k, w)
+		d := len(idx) - 1
+		for ; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < half[d] {
+				break
+			}
+			idx[d] = 0
+		}
+		if d < 0 {
+			return
+		}
+	}
+}
+
+// forEachSeparation calls fn with the flat index and signed, minimum
+// image separation of each point of a periodic grid of dimensions dims.
+func forEachSeparation(dims []int, fn func(i int, r []int)) {
+	r := make([]int, len(dims))
+	idx := make([]int, len(dims))
+	for i := 0; ; i++ {
+		for d, j := range idx {
+			r[d] = j
+			if 2*j > dims[d] {
+				r[d] -= dims[d]
+			}
+		}
+		fn(i, r)
+		d := len(idx) - 1
+		for ; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < dims[d] {
+				break
+			}
+			idx[d] = 0
+		}
+		if d < 0 {
+			return
+		}
+	}
+}
+
+// shellIndex returns the shell of integer radius nearest |k|.
+func shellIndex(k []int) int {
+	s := 0
+	for _, v := range k {
+		s += v * v
+	}
+	return int(math.Round(math.Sqrt(float64(s))))
+}
+
+// shellCount returns the number of shells needed to hold every
+// wavenumber or separation of a grid of dimensions dims.
+func shellCount(dims []int) int {
+	k := make([]int, len(dims))
+	for i, n := range dims {
+		k[i] = n / 2
+	}
+	return shellIndex(k) + 1
+}
+
+// isotropicSpectrum sums the power of the field x into shells.
+func isotropicSpectrum(dims []int, x []float64) []float64 {
+	s := realSpectrum(dims, x)
+	n := float64(len(x))
+	e := make([]float64, shellCount(dims))
+	forEachMode(dims, func(i int, k []int, w int) {
+		v := s[i]
+		e[shellIndex(k)] += float64(w) * (real(v)*real(v) + imag(v)*imag(v)) / (n * n)
+	})
+	return e
+}
+
+// autocorrelation returns the periodic autocorrelation of x,
+// C(r) = mean over x of x(p) x(p+r), computed from its power spectrum.
+func autocorrelation(dims []int, x []float64) []float64 {
+	s := realSpectrum(dims, x)
+	n := float64(len(x))
+	for i, v := range s {
+		s[i] = complex((real(v)*real(v)+imag(v)*imag(v))/(n*n), 0)
+	}
+	c := make([]float64, len(x))
+	PlanSpec{Kind: C2R, Dims: dims, Dir: Backward, Flag: Estimate}.PlanDftC2R(s, c).Execute()
+	return c
+}
+
+// shellAverage averages the values of the periodic grid c, of dimensions
+// dims, over shells of separation.
+func shellAverage(dims []int, c []float64) []float64 {
+	sum := make([]float64, shellCount(dims))
+	count := make([]int, len(sum))
+	forEachSeparation(dims, func(i int, r []int) {
+		s := shellIndex(r)
+		sum[s] += c[i]
+		count[s]++
+	})
+	for s := range sum {
+		if count[s] > 0 {
+			sum[s] /= float64(count[s])
+		}
+	}
+	return sum
+}
+
+// structureFunction returns the shell averaged second order structure
+// function of x, from its autocorrelation.
+func structureFunction(dims []int, x []float64) []float64 {
+	c := autocorrelation(dims, x)
+	s := shellAverage(dims, c)
+	for r := range s {
+		s[r] = 2 * (c[0] - s[r])
+	}
+	return s
+}
+
+// IsotropicSpectrum2d returns the isotropic power spectrum of the periodic
+// field u, as used to check simulated turbulence against Kolmogorov's k^-5/3
+// law: E[k] is the power of the modes whose wavenumber, in cycles over the
+// field, is nearest k, so that E sums to the mean square of u.  The field
+// is taken to span the same length on both axes, as in a periodic box.
+// The spectrum of a velocity field is the sum of its components' spectra,
+// halved to give kinetic energy.
+func IsotropicSpectrum2d(u [][]float64) []float64 {
+	dims, flat := flatten2d(u)
+	return isotropicSpectrum(dims, flat)
+}
+
+// IsotropicSpectrum3d is IsotropicSpectrum2d for 3d fields, whose power is
+// summed over spherical shells of wavenumber.
+func IsotropicSpectrum3d(u [][][]float64) []float64 {
+	dims, flat := flatten3d(u)
+	return isotropicSpectrum(dims, flat)
+}
+
+// StructureFunction2d returns the second order structure function of the
+// periodic field u, S[r] = <(u(p+d) - u(p))²> averaged over every point p
+// and every separation d, in samples, whose length is nearest r.  It is
+// computed from the power spectrum, in O(n log n) time rather than the
+// O(n²) of comparing every pair of points.
+func StructureFunction2d(u [][]float64) []float64 {
+	dims, flat := flatten2d(u)
+	return structureFunction(dims, flat)
+}
+
+// StructureFunction3d is StructureFunction2d for 3d fields.
+func StructureFunction3d(u [][][]float64) []float64 {
+	dims, flat := flatten3d(u)
+	return structureFunction(dims, flat)
+}
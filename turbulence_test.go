@@ -0,0 +1,84 @@
+package fftw
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func TurbulenceSpec(c gospec.Context) {
+	c.Specify("The isotropic spectrum puts each mode in its shell.", func() {
+		u := alloc2dReal(16, 12)
+		for i := range u {
+			for j := range u[i] {
+				u[i][j] = 1 + 2*math.Cos(2*math.Pi*(3*float64(i)/16+4*float64(j)/12))
+			}
+		}
+		e := IsotropicSpectrum2d(u)
+		c.Expect(len(e), gospec.Equals, 11)
+		for k, v := range e {
+			switch k {
+			case 0:
+				c.Expect(v, gospec.IsWithin(1e-9), 1.0)
+			case 5:
+				c.Expect(v, gospec.IsWithin(1e-9), 2.0)
+			default:
+				c.Expect(v, gospec.IsWithin(1e-9), 0.0)
+			}
+		}
+	})
+
+	c.Specify("The spectrum sums to the mean square of the field.", func() {
+		rng := rand.New(rand.NewSource(1))
+		u := make([][][]float64, 4)
+		ms := 0.0
+		for i := range u {
+			u[i] = alloc2dReal(6, 5)
+			for j := range u[i] {
+				for k := range u[i][j] {
+					u[i][j][k] = rng.NormFloat64()
+					ms += u[i][j][k] * u[i][j][k] / 120
+				}
+			}
+		}
+		c.Expect(sum(IsotropicSpectrum3d(u)), gospec.IsWithin(1e-9), ms)
+	})
+
+	c.Specify("Structure functions match the direct average over pairs.", func() {
+		rng := rand.New(rand.NewSource(2))
+		n0, n1 := 6, 8
+		u := alloc2dReal(n0, n1)
+		for i := range u {
+			for j := range u[i] {
+				u[i][j] = rng.NormFloat64()
+			}
+		}
+		got := StructureFunction2d(u)
+		sums := make([]float64, len(got))
+		counts := make([]float64, len(got))
+		for di := 0; di < n0; di++ {
+			for dj := 0; dj < n1; dj++ {
+				ri, rj := di, dj
+				if 2*ri > n0 {
+					ri -= n0
+				}
+				if 2*rj > n1 {
+					rj -= n1
+				}
+				s := shellIndex([]int{ri, rj})
+				for i := range u {
+					for j := range u[i] {
+						d := u[(i+di)%n0][(j+dj)%n1] - u[i][j]
+						sums[s] += d * d
+						counts[s]++
+					}
+				}
+			}
+		}
+		c.Expect(got[0], gospec.IsWithin(1e-9), 0.0)
+		for r := range got {
+			c.Expect(got[r], gospec.IsWithin(1e-9), sums[r]/counts[r])
+		}
+	})
+}
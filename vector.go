@@ -0,0 +1,209 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// waveNumbers returns the angular wavevector of each bin of the half
+// spectrum of a field of dimensions dims sampled spacing apart.  If odd is
+// set, components at the Nyquist frequency of even dimensions are zeroed,
+// as an odd derivative of a real field is ambiguous there.
+func waveNumbers(dims []int, spacing []float64, odd bool) [][]float64 {
+	_, half := PlanSpec{Kind: R2C, Dims: dims}.Size()
+	ks := make([][]float64, half)
+	forEachMode(dims, func(i int, m []int, weight int) {
+		k := make([]float64, len(dims))
+		for d, md := range m {
+			if !odd || 2*md != dims[d] {
+				k[d] = 2 * math.Pi * float64(md) / (float64(dims[d]) * spacing[d])
+			}
+		}
+		ks[i] = k
+	})
+	return ks
+}
+
+// realInverse returns the normalized inverse of the half spectrum s of a
+// real field of dimensions dims, destroying s.
+func realInverse(dims []int, s []complex128) []float64 {
+	spec := PlanSpec{Kind: C2R, Dims: dims, Dir: Backward, Flag: Estimate}
+	_, n := spec.Size()
+	x := make([]float64, n)
+	spec.PlanDftC2R(s, x).Execute()
+	ScaleReal(x, 1/float64(n))
+	return x
+}
+
+func spectralGradient(dims []int, spacing []float64, u []float64) [][]float64 {
+	s := realSpectrum(dims, u)
+	ks := waveNumbers(dims, spacing, true)
+	g := make([][]float64, len(dims))
+	for d := range g {
+		ds := make([]complex128, len(s))
+		for i, v := range s {
+			ds[i] = v * complex(0, ks[i][d])
+		}
+		g[d] = realInverse(dims, ds)
+	}
+	return g
+}
+
+func divergence(dims []int, spacing []float64, v [][]float64) []float64 {
+	ks := waveNumbers(dims, spacing, true)
+	div := make([]complex128, len(ks))
+	for d, c := range v {
+		for i, x := range realSpectrum(dims, c) {
+			div[i] += x * complex(0, ks[i][d])
+		}
+	}
+	return realInverse(dims, div)
+}
+
+// curl returns the components of the curl of the 3d field v, or its one
+// scalar component, ∂0 v1 - ∂1 v0, if v is 2d.
+func curl(dims []int, spacing []float64, v [][]float64) [][]float64 {
+	ks := waveNumbers(dims, spacing, true)
+	s := make([][]complex128, len(v))
+	for d, c := range v {
+		s[d] = realSpectrum(dims, c)
+	}
+	// partial is the spectrum of ∂a v[b] - ∂b v[a].
+	partial := func(a, b int) []float64 {
+		p := make([]complex128, len(ks))
+		for i, k := range ks {
+			p[i] = complex(0, k[a])*s[b][i] - complex(0, k[b])*s[a][i]
+		}
+		return realInverse(dims, p)
+	}
+	if len(v) == 2 {
+		return [][]float64{partial(0, 1)}
+	}
+	return [][]float64{partial(1, 2), partial(2, 0), partial(0, 1)}
+}
+
+// helmholtz splits v into its divergence free and curl free parts by
+// projecting each mode onto its wavevector.
+func helmholtz(dims []int, spacing []float64, v [][]float64) (solenoidal, irrotational [][]float64) {
+	ks := waveNumbers(dims, spacing, false)
+	s := make([][]complex128, len(v))
+	for d, c := range v {
+		s[d] = realSpectrum(dims, c)
+	}
+	irr := make([][]complex128, len(v))
+	for d := range irr {
+		irr[d] = make([]complex128, len(ks))
+	}
+	for i, k := range ks {
+		k2 := squaredNorm(k)
+		if k2 == 0 {
+			continue
+		}
+		var dot complex128
+		for d := range s {
+			dot += complex(k[d], 0) * s[d][i]
+		}
+		for d := range irr {
+			irr[d][i] = dot * complex(k[d]/k2, 0)
+		}
+	}
+	solenoidal = make([][]float64, len(v))
+	irrotational = make([][]float64, len(v))
+	for d := range v {
+		irrotational[d] = realInverse(dims, irr[d])
+		solenoidal[d] = make([]float64, len(v[d]))
+		for j := range v[d] {
+			solenoidal[d][j] = v[d][j] - irrotational[d][j]
+		}
+	}
+	return solenoidal, irrotational
+}
+
+func flattenVector2d(name string, v [2][][]float64) ([]int, [][]float64) {
+	dims, x := flatten2d(v[0])
+	dims1, y := flatten2d(v[1])
+	if dims1[0] != dims[0] || dims1[1] != dims[1] {
+		panic(fmt.Sprint(name, " needs components of the same dimensions, got ", dims, " and ", dims1))
+	}
+	return dims, [][]float64{x, y}
+}
+
+func flattenVector3d(name string, v [3][][][]float64) ([]int, [][]float64) {
+	dims, x := flatten3d(v[0])
+	flat := [][]float64{x}
+	for _, c := range v[1:] {
+		dimsC, y := flatten3d(c)
+		if dimsC[0] != dims[0] || dimsC[1] != dims[1] || dimsC[2] != dims[2] {
+			panic(fmt.Sprint(name, " needs components of the same dimensions, got ", dims, " and ", dimsC))
+		}
+		flat = append(flat, y)
+	}
+	return dims, flat
+}
+
+// Gradient2d returns the spectral gradient of the periodic field u, sampled
+// spacing apart along each axis, as its components along each axis.
+func Gradient2d(u [][]float64, spacing [2]float64) [2][][]float64 {
+	dims, flat := flatten2d(u)
+	g := spectralGradient(dims, spacing[:], flat)
+	return [2][][]float64{unflatten2d(dims, g[0]), unflatten2d(dims, g[1])}
+}
+
+// Gradient3d is Gradient2d for 3d fields.
+func Gradient3d(u [][][]float64, spacing [3]float64) [3][][][]float64 {
+	dims, flat := flatten3d(u)
+	g := spectralGradient(dims, spacing[:], flat)
+	return [3][][][]float64{unflatten3d(dims, g[0]), unflatten3d(dims, g[1]), unflatten3d(dims, g[2])}
+}
+
+// Divergence2d returns the spectral divergence of the periodic vector field
+// v, whose components v[0] and v[1] lie along the first and second axes.
+func Divergence2d(v [2][][]float64, spacing [2]float64) [][]float64 {
+	dims, flat := flattenVector2d("Divergence2d", v)
+	return unflatten2d(dims, divergence(dims, spacing[:], flat))
+}
+
+// Divergence3d is Divergence2d for 3d fields.
+func Divergence3d(v [3][][][]float64, spacing [3]float64) [][][]float64 {
+	dims, flat := flattenVector3d("Divergence3d", v)
+	return unflatten3d(dims, divergence(dims, spacing[:], flat))
+}
+
+// Curl2d returns the spectral curl of the periodic vector field v, the
+// scalar ∂v[1]/∂x0 - ∂v[0]/∂x1, which is the vorticity of a velocity field.
+func Curl2d(v [2][][]float64, spacing [2]float64) [][]float64 {
+	dims, flat := flattenVector2d("Curl2d", v)
+	return unflatten2d(dims, curl(dims, spacing[:], flat)[0])
+}
+
+// Curl3d returns the spectral curl of the periodic vector field v.
+func Curl3d(v [3][][][]float64, spacing [3]float64) [3][][][]float64 {
+	dims, flat := flattenVector3d("Curl3d", v)
+	c := curl(dims, spacing[:], flat)
+	return [3][][][]float64{unflatten3d(dims, c[0]), unflatten3d(dims, c[1]), unflatten3d(dims, c[2])}
+}
+
+// HelmholtzDecompose2d splits the periodic vector field v into a
+// solenoidal, divergence free, part and an irrotational, curl free, part
+// that sum to v.  The mean of v, which is both, goes to the solenoidal
+// part, as is usual for the velocity of an incompressible flow.
+func HelmholtzDecompose2d(v [2][][]float64, spacing [2]float64) (solenoidal, irrotational [2][][]float64) {
+	dims, flat := flattenVector2d("HelmholtzDecompose2d", v)
+	s, r := helmholtz(dims, spacing[:], flat)
+	for d := range solenoidal {
+		solenoidal[d] = unflatten2d(dims, s[d])
+		irrotational[d] = unflatten2d(dims, r[d])
+	}
+	return solenoidal, irrotational
+}
+
+// HelmholtzDecompose3d is HelmholtzDecompose2d for 3d fields.
+func HelmholtzDecompose3d(v [3][][][]float64, spacing [3]float64) (solenoidal, irrotational [3][][][]float64) {
+	dims, flat := flattenVector3d("HelmholtzDecompose3d", v)
+	s, r := helmholtz(dims, spacing[:], flat)
+	for d := range solenoidal {
+		solenoidal[d] = unflatten3d(dims, s[d])
+		irrotational[d] = unflatten3d(dims, r[d])
+	}
+	return solenoidal, irrotational
+}
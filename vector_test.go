@@ -0,0 +1,114 @@
+package fftw
+
+import (
+	"math"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func VectorSpec(c gospec.Context) {
+	n0, n1 := 16, 12
+	h := [2]float64{0.25, 0.5}
+	a := 2 * math.Pi / (float64(n0) * h[0])
+	b := 2 * 2 * math.Pi / (float64(n1) * h[1])
+	// phi is a potential and psi a stream function, whose gradient and
+	// skew gradient are curl free and divergence free fields.
+	phi := alloc2dReal(n0, n1)
+	var grad, skew [2][][]float64
+	for d := range grad {
+		grad[d] = alloc2dReal(n0, n1)
+		skew[d] = alloc2dReal(n0, n1)
+	}
+	for i := 0; i < n0; i++ {
+		for j := 0; j < n1; j++ {
+			x, y := float64(i)*h[0], float64(j)*h[1]
+			phi[i][j] = math.Sin(a*x) * math.Cos(b*y)
+			grad[0][i][j] = a * math.Cos(a*x) * math.Cos(b*y)
+			grad[1][i][j] = -b * math.Sin(a*x) * math.Sin(b*y)
+			// The skew gradient of psi = cos(a x) sin(2 b y).
+			skew[0][i][j] = 2 * b * math.Cos(a*x) * math.Cos(2*b*y)
+			skew[1][i][j] = a * math.Sin(a*x) * math.Sin(2*b*y)
+		}
+	}
+
+	c.Specify("Gradients are exact for band limited fields.", func() {
+		g := Gradient2d(phi, h)
+		for d := range g {
+			for i := range g[d] {
+				for j := range g[d][i] {
+					c.Expect(g[d][i][j], gospec.IsWithin(1e-10), grad[d][i][j])
+				}
+			}
+		}
+	})
+
+	c.Specify("Gradients are curl free and skew gradients divergence free.", func() {
+		for _, row := range Curl2d(grad, h) {
+			for _, v := range row {
+				c.Expect(v, gospec.IsWithin(1e-10), 0.0)
+			}
+		}
+		for _, row := range Divergence2d(skew, h) {
+			for _, v := range row {
+				c.Expect(v, gospec.IsWithin(1e-10), 0.0)
+			}
+		}
+	})
+
+	c.Specify("The Helmholtz decomposition separates the two.", func() {
+		var v [2][][]float64
+		for d := range v {
+			v[d] = alloc2dReal(n0, n1)
+			for i := range v[d] {
+				for j := range v[d][i] {
+					v[d][i][j] = grad[d][i][j] + skew[d][i][j] + 0.5
+				}
+			}
+		}
+		sol, irr := HelmholtzDecompose2d(v, h)
+		for d := range v {
+			for i := range v[d] {
+				for j := range v[d][i] {
+					c.Expect(irr[d][i][j], gospec.IsWithin(1e-10), grad[d][i][j])
+					c.Expect(sol[d][i][j], gospec.IsWithin(1e-10), skew[d][i][j]+0.5)
+				}
+			}
+		}
+	})
+
+	c.Specify("3d fields are differentiated exactly.", func() {
+		// v = (-sin y, sin x, 0) on a 2π box, whose curl is
+		// (0, 0, cos x + cos y).
+		n := 8
+		s := 2 * math.Pi / float64(n)
+		var v [3][][][]float64
+		for d := range v {
+			v[d] = alloc3dReal(n, n, 4)
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				for k := 0; k < 4; k++ {
+					v[0][i][j][k] = -math.Sin(float64(j) * s)
+					v[1][i][j][k] = math.Sin(float64(i) * s)
+				}
+			}
+		}
+		w := Curl3d(v, [3]float64{s, s, 1})
+		div := Divergence3d(v, [3]float64{s, s, 1})
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				for k := 0; k < 4; k++ {
+					c.Expect(w[0][i][j][k], gospec.IsWithin(1e-10), 0.0)
+					c.Expect(w[1][i][j][k], gospec.IsWithin(1e-10), 0.0)
+					c.Expect(w[2][i][j][k], gospec.IsWithin(1e-10), math.Cos(float64(i)*s)+math.Cos(float64(j)*s))
+					c.Expect(div[i][j][k], gospec.IsWithin(1e-10), 0.0)
+				}
+			}
+		}
+		sol, irr := HelmholtzDecompose3d(v, [3]float64{s, s, 1})
+		c.Expect(irr[0][1][2][3], gospec.IsWithin(1e-10), 0.0)
+		c.Expect(sol[1][3][2][1], gospec.IsWithin(1e-10), v[1][3][2][1])
+		g := Gradient3d(v[1], [3]float64{s, s, 1})
+		c.Expect(g[0][2][0][0], gospec.IsWithin(1e-10), math.Cos(2*s))
+	})
+}
@@ -0,0 +1,124 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A FilterResponse gives the gain of a low-pass filter at a frequency r,
+// measured in units of the filter's cutoff, so that r is 1 at the cutoff.
+type FilterResponse func(r float64) float64
+
+// IdealLowpass passes frequencies up to the cutoff and nothing above it.
+func IdealLowpass(r float64) float64 {
+	if r <= 1 {
+		return 1
+	}
+	return 0
+}
+
+// GaussianLowpass rolls off smoothly, with a gain of 1/√2 at the cutoff and
+// no ringing.
+func GaussianLowpass(r float64) float64 {
+	return math.Exp(-math.Ln2 / 2 * r * r)
+}
+
+// Butterworth returns the response of a Butterworth filter of the given
+// order, which is flat below the cutoff and has a gain of 1/√2 at it.
+func Butterworth(order int) FilterResponse {
+	if order < 1 {
+		panic(fmt.Sprint("Butterworth needs a positive order, got ", order))
+	}
+	return func(r float64) float64 {
+		return 1 / math.Sqrt(1+math.Pow(r, 2*float64(order)))
+	}
+}
+
+// Highpass returns the high-pass complement, 1 - lowpass(r), of a response.
+func Highpass(lowpass FilterResponse) FilterResponse {
+	return func(r float64) float64 {
+		return 1 - lowpass(r)
+	}
+}
+
+func alloc3dReal(n0, n1, n2 int) [][][]float64 {
+	a := make([]float64, n0*n1*n2)
+	r := make([][][]float64, n0)
+	for i := range r {
+		r[i] = make([][]float64, n1)
+		for j := range r[i] {
+			k := (i*n1 + j) * n2
+			r[i][j] = a[k : k+n2]
+		}
+	}
+	return r
+}
+
+// FilterVolume filters the n0 x n1 x n2 volume x with response, returning
+// the filtered volume.  Each axis has its own cutoff, in cycles per sample
+// up to the Nyquist frequency of 0.5, so that stacks sampled more coarsely
+// along one axis, as confocal stacks are along z, can be filtered to the
+// same physical resolution on every axis.  The response is evaluated at the
+// ellipsoidal radius
+//
+//	r = √((f0/cutoff[0])² + (f1/cutoff[1])² + (f2/cutoff[2])²)
+//
+// and the volume is treated as periodic.
+func FilterVolume(x [][][]float64, cutoff [3]float64, response FilterResponse) [][][]float64 {
+	for i, c := range cutoff {
+		if c <= 0 {
+			panic(fmt.Sprint("FilterVolume needs positive cutoffs, got ", c, " on axis ", i))
+		}
+	}
+	n0 := len(x)
+	if n0 == 0 || len(x[0]) == 0 || len(x[0][0]) == 0 {
+		return nil
+	}
+	n1, n2 := len(x[0]), len(x[0][0])
+	h := n2/2 + 1
+	in := alloc3dReal(n0, n1, n2)
+	for i := range x {
+		if len(x[i]) != n1 {
+			panic(fmt.Sprint("FilterVolume needs a rectangular volume, plane ", i, " has ", len(x[i]), " rows, not ", n1))
+		}
+		for j := range x[i] {
+			if len(x[i][j]) != n2 {
+				panic(fmt.Sprint("FilterVolume needs a rectangular volume, row ", i, ",", j, " has length ", len(x[i][j]), ", not ", n2))
+			}
+			copy(in[i][j], x[i][j])
+		}
+	}
+	spectrum := make([][][]complex128, n0)
+	data := make([]complex128, n0*n1*h)
+	for i := range spectrum {
+		spectrum[i] = make([][]complex128, n1)
+		for j := range spectrum[i] {
+			k := (i*n1 + j) * h
+			spectrum[i][j] = data[k : k+h]
+		}
+	}
+	PlanDftR2C3d(in, spectrum, Estimate).Execute()
+	scale := 1 / float64(n0*n1*n2)
+	for i := range spectrum {
+		f0 := signedFreq(i, n0) / cutoff[0]
+		for j := range spectrum[i] {
+			f1 := signedFreq(j, n1) / cutoff[1]
+			for k := range spectrum[i][j] {
+				f2 := float64(k) / float64(n2) / cutoff[2]
+				g := response(math.Sqrt(f0*f0 + f1*f1 + f2*f2))
+				spectrum[i][j][k] *= complex(g*scale, 0)
+			}
+		}
+	}
+	PlanDftC2R3d(spectrum, in, Estimate).Execute()
+	return in
+}
+
+// signedFreq returns the frequency, in cycles per sample, of bin k of an n
+// point transform, negative for the bins above n/2.
+func signedFreq(k, n int) float64 {
+	if k > n/2 {
+		k -= n
+	}
+	return float64(k) / float64(n)
+}
@@ -0,0 +1,64 @@
+package fftw
+
+import (
+	"math"
+
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func VolumeFilterSpec(c gospec.Context) {
+	n0, n1, n2 := 8, 4, 8
+	// wave is a cosine of a quarter cycle per sample along axis 0 plus one
+	// along axis 2.
+	wave := func(axis int) [][][]float64 {
+		x := alloc3dReal(n0, n1, n2)
+		for i := range x {
+			for j := range x[i] {
+				for k := range x[i][j] {
+					x[i][j][k] = 2 + math.Cos(math.Pi/2*float64([]int{i, j, k}[axis]))
+				}
+			}
+		}
+		return x
+	}
+
+	c.Specify("Each axis is filtered with its own cutoff.", func() {
+		cutoff := [3]float64{0.1, 0.5, 0.4}
+		y := FilterVolume(wave(0), cutoff, IdealLowpass)
+		for i := range y {
+			c.Expect(y[i][1][3], gospec.IsWithin(1e-9), 2.0)
+		}
+		y = FilterVolume(wave(2), cutoff, IdealLowpass)
+		for k := range y[0][0] {
+			c.Expect(y[3][2][k], gospec.IsWithin(1e-9), 2+math.Cos(math.Pi/2*float64(k)))
+		}
+	})
+
+	c.Specify("High-pass responses complement low-pass ones.", func() {
+		x := wave(2)
+		cutoff := [3]float64{0.2, 0.2, 0.2}
+		lo := FilterVolume(x, cutoff, GaussianLowpass)
+		hi := FilterVolume(x, cutoff, Highpass(GaussianLowpass))
+		for i := range x {
+			for j := range x[i] {
+				for k := range x[i][j] {
+					c.Expect(lo[i][j][k]+hi[i][j][k], gospec.IsWithin(1e-9), x[i][j][k])
+				}
+			}
+		}
+		c.Expect(hi[0][0][0]+hi[0][0][2], gospec.IsWithin(1e-9), 0.0)
+	})
+
+	c.Specify("Responses have a gain of 1/√2 at the cutoff.", func() {
+		c.Expect(GaussianLowpass(1), gospec.IsWithin(1e-12), math.Sqrt(0.5))
+		c.Expect(Butterworth(4)(1), gospec.IsWithin(1e-12), math.Sqrt(0.5))
+		c.Expect(Butterworth(4)(0), gospec.IsWithin(1e-12), 1.0)
+		c.Expect(IdealLowpass(1.01), gospec.Equals, 0.0)
+	})
+
+	c.Specify("The input volume is left unchanged.", func() {
+		x := wave(0)
+		FilterVolume(x, [3]float64{0.1, 0.1, 0.1}, IdealLowpass)
+		c.Expect(x[1][0][0], gospec.IsWithin(1e-12), 2+math.Cos(math.Pi/2))
+	})
+}
@@ -0,0 +1,158 @@
+package fftw
+
+// #include <string.h>
+// #include <fftw3.h>
+import "C"
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Warm touches every page of buf so that the operating system maps it in
+// now, rather than one page fault at a time during the first transform.
+// This matters for multi-gigabyte buffers fresh from Alloc1d.  If workers is
+// more than one the pages are touched by that many goroutines in parallel.
+// The contents of buf are not changed.
+func Warm(buf []complex128, workers int) {
+	// Pages have to be written, not just read, or they may all be mapped
+	// to the same shared zero page.  Adding zero atomically writes without
+	// changing anything, and unlike storing back what was loaded it can't be
+	// optimized away; it is also safe on the pages neighbouring chunks
+	// share.
+	step := os.Getpagesize() / 16
+	if step < 1 {
+		step = 1
+	}
+	touch := func(b []complex128) {
+		for i := 0; i < len(b); i += step {
+			atomic.AddUint64((*uint64)(unsafe.Pointer(&b[i])), 0)
+		}
+		// The buffer needn't start on a page boundary, so make sure the
+		// last page gets touched too.
+		if len(b) > 0 {
+			atomic.AddUint64((*uint64)(unsafe.Pointer(&b[len(b)-1])), 0)
+		}
+	}
+	if workers <= 1 {
+		touch(buf)
+		return
+	}
+	chunk := (len(buf) + workers - 1) / workers
+	// Keep chunks page aligned so no page is touched twice.
+	chunk = (chunk + step - 1) / step * step
+	var wg sync.WaitGroup
+	for start := 0; start < len(buf); start += chunk {
+		end := start + chunk
+		if end > len(buf) {
+			end = len(buf)
+		}
+		wg.Add(1)
+		go func(b []complex128) {
+			defer wg.Done()
+			touch(b)
+		}(buf[start:end])
+	}
+	wg.Wait()
+}
+
+// WarmUp executes p once on scratch buffers, without touching the arrays p
+// was planned for.  Calling it straight after planning means that the first
+// real execution doesn't pay for page faults and cold instruction caches,
+// which matters to code with a strict latency budget for its first frame.
+//
+// Split plans aren't warmed up, since fftw can't run them on scratch
+// buffers of its own alignment.
+func (p *Plan) WarmUp() {
+	g := p.geom
+	if g.Split {
+		return
+	}
+	inLen, outLen := g.extents()
+	inBytes, outBytes := 16*inLen, 16*outLen
+	switch g.Kind {
+	case R2C:
+		inBytes = 8 * inLen
+	case C2R:
+		outBytes = 8 * outLen
+	case R2R:
+		inBytes, outBytes = 8*inLen, 8*outLen
+	}
+
+	// fftw requires the scratch buffers to be in place exactly when the
+	// original arrays were, and to have the same alignment as them.
+	// fftw_malloc returns maximally aligned memory, so offsetting into it
+	// by the original alignment reproduces that alignment.
+	scratch := func(bytes int, align C.int) unsafe.Pointer {
+		base := fftwMalloc(bytes+int(align), 1)
+		C.memset(base, 0, C.size_t(bytes+int(align)))
+		return unsafe.Pointer(uintptr(base) + uintptr(align))
+	}
+	free := func(buf unsafe.Pointer, align C.int) {
+		C.fftw_free(unsafe.Pointer(uintptr(buf) - uintptr(align)))
+	}
+	if inBytes < outBytes && g.InPlace {
+		inBytes = outBytes
+	}
+	in := scratch(inBytes, p.inAlign)
+	defer free(in, p.inAlign)
+	out := in
+	if !g.InPlace {
+		out = scratch(outBytes, p.outAlign)
+		defer free(out, p.outAlign)
+	}
+	if C.fftw_alignment_of((*C.double)(in)) != p.inAlign || C.fftw_alignment_of((*C.double)(out)) != p.outAlign {
+		// Only possible if fftw_malloc's alignment is weaker than fftw's
+		// SIMD alignment, in which case it's safer not to warm up at all.
+		return
+	}
+
+	p.executeOn(in, out)
+}
+
+// extents returns the number of elements of its arrays' types that a plan
+// of geometry g reaches in its input and output.
+func (g Geometry) extents() (in, out int) {
+	inLast, outLast := fullLen, fullLen
+	switch g.Kind {
+	case R2C:
+		outLast = halfLen
+	case C2R:
+		inLast = halfLen
+	}
+	if g.IO != nil {
+		in, out = 1, 1
+		for i, d := range g.IO {
+			ni, no := d.N, d.N
+			if i == len(g.Dims)-1 {
+				ni, no = inLast(d.N), outLast(d.N)
+			}
+			in += (ni - 1) * d.Is
+			out += (no - 1) * d.Os
+		}
+		return in, out
+	}
+	size := 1
+	for _, n := range g.Dims {
+		size *= n
+	}
+	in, out = size, size
+	if len(g.Dims) > 0 {
+		last := g.Dims[len(g.Dims)-1]
+		in, out = size/last*inLast(last), size/last*outLast(last)
+	}
+	batch := g.Batch
+	if batch == 0 {
+		batch = 1
+	}
+	if g.In.Stride > 0 {
+		// Strided plans are one dimensional.
+		n := g.Dims[0]
+		in = (batch-1)*g.In.Dist + (inLast(n)-1)*g.In.Stride + 1
+		out = (batch-1)*g.Out.Dist + (outLast(n)-1)*g.Out.Stride + 1
+		return in, out
+	}
+	return in * batch, out * batch
+}
@@ -0,0 +1,45 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// residentPages returns how many of the pages of b are in memory.
+func residentPages(b []byte) int {
+	vec := make([]byte, (len(b)+os.Getpagesize()-1)/os.Getpagesize())
+	_, _, errno := syscall.Syscall(syscall.SYS_MINCORE, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), uintptr(unsafe.Pointer(&vec[0])))
+	if errno != 0 {
+		panic(errno)
+	}
+	n := 0
+	for _, v := range vec {
+		n += int(v & 1)
+	}
+	return n
+}
+
+func WarmResidencySpec(c gospec.Context) {
+	c.Specify("Warming maps in every page of a fresh buffer.", func() {
+		const pages = 64
+		for _, workers := range []int{1, 4} {
+			mem, err := syscall.Mmap(-1, 0, pages*os.Getpagesize(), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+			c.Assume(err, gospec.IsNil)
+			c.Expect(residentPages(mem), gospec.Equals, 0)
+			Warm(unsafe.Slice((*complex128)(unsafe.Pointer(&mem[0])), len(mem)/16), workers)
+			c.Expect(residentPages(mem), gospec.Equals, pages)
+			syscall.Munmap(mem)
+		}
+	})
+}
+
+// WarmResidencySpec uses mincore, which is Linux only, so it has its own
+// runner.
+func TestWarmResidency(t *testing.T) {
+	r := gospec.NewRunner()
+	r.AddSpec(WarmResidencySpec)
+	gospec.MainGoTest(r, t)
+}
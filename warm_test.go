@@ -0,0 +1,105 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+)
+
+func WarmSpec(c gospec.Context) {
+	c.Specify("Warming a buffer doesn't change its contents.", func() {
+		for _, workers := range []int{0, 1, 3, 8} {
+			d := Alloc1d(100003)
+			for i := range d {
+				d[i] = complex(float64(i), float64(-i))
+			}
+			Warm(d, workers)
+			for i := range d {
+				c.Expect(d[i], gospec.Equals, complex(float64(i), float64(-i)))
+			}
+		}
+	})
+
+	c.Specify("Warming empty buffers is harmless.", func() {
+		Warm(nil, 1)
+		Warm(nil, 4)
+	})
+}
+
+func WarmUpSpec(c gospec.Context) {
+	signal := Alloc1d(32)
+	out := Alloc1d(32)
+	for i := range signal {
+		signal[i] = complex(float64(i), 1)
+	}
+	inPlace := PlanDft1d(signal, signal, Forward, Estimate)
+	outOfPlace := PlanDft1d(signal, out, Forward, Estimate)
+	inPlace.WarmUp()
+	outOfPlace.WarmUp()
+	c.Specify("Warming up a plan doesn't touch its arrays.", func() {
+		for i := range signal {
+			c.Expect(signal[i], gospec.Equals, complex(float64(i), 1))
+			c.Expect(out[i], gospec.Equals, complex128(0))
+		}
+	})
+
+	real_signal := make([]float64, 16)
+	F_signal := make([]complex128, 9)
+	for i := range real_signal {
+		real_signal[i] = float64(i)
+	}
+	forward := PlanDftR2C1d(real_signal, F_signal, Estimate)
+	backward := PlanDftC2R1d(F_signal, real_signal, Estimate)
+	forward.WarmUp()
+	backward.WarmUp()
+	c.Specify("Warming up real plans doesn't touch their arrays.", func() {
+		for i := range real_signal {
+			c.Expect(real_signal[i], gospec.Equals, float64(i))
+		}
+		for i := range F_signal {
+			c.Expect(F_signal[i], gospec.Equals, complex128(0))
+		}
+	})
+
+	c.Specify("Plans still work after warming up.", func() {
+		outOfPlace.Execute()
+		c.Expect(real(out[0]), gospec.IsWithin(1e-9), float64(31*32/2))
+		c.Expect(imag(out[0]), gospec.IsWithin(1e-9), 32.0)
+		forward.Execute()
+		backward.Execute()
+		for i := range real_signal {
+			c.Expect(real_signal[i], gospec.IsWithin(1e-9), float64(16*i))
+		}
+	})
+
+	// Go only aligns float64s to 8 bytes, so this is misaligned for SIMD.
+	buf := make([]float64, 17)
+	shifted := buf[1:]
+	for i := range shifted {
+		shifted[i] = float64(i % 3)
+	}
+	F_shifted := make([]complex128, 9)
+	misaligned := PlanDftR2C1d(shifted, F_shifted, Estimate)
+	misaligned.WarmUp()
+	c.Specify("Plans on misaligned arrays can be warmed up.", func() {
+		for i := range shifted {
+			c.Expect(shifted[i], gospec.Equals, float64(i%3))
+		}
+		misaligned.Execute()
+		c.Expect(real(F_shifted[0]), gospec.IsWithin(1e-9), 15.0)
+	})
+
+	c.Specify("Scratch buffers cover batched, strided and guru plans.", func() {
+		batch := PlanManyDftR2C([]int{8}, 3, make([]float64, 24), make([]complex128, 15), Estimate).Geometry()
+		in, out := batch.extents()
+		c.Expect(in, gospec.Equals, 24)
+		c.Expect(out, gospec.Equals, 15)
+		strided := PlanDftC2RStrided(8, 2, make([]complex128, 10), Strides{2, 1}, make([]float64, 16), Strides{2, 1}, Estimate).Geometry()
+		in, out = strided.extents()
+		c.Expect(in, gospec.Equals, 10)
+		c.Expect(out, gospec.Equals, 16)
+		guru := PlanGuruDft([]IODim{{N: 4, Is: 3, Os: 1}}, []IODim{{N: 3, Is: 1, Os: 4}}, make([]complex128, 12), make([]complex128, 12), Forward, Estimate)
+		in, out = guru.Geometry().extents()
+		c.Expect(in, gospec.Equals, 12)
+		c.Expect(out, gospec.Equals, 12)
+		guru.WarmUp()
+	})
+}
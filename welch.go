@@ -0,0 +1,95 @@
+package fftw
+
+// WelchOptions controls how Welch's method splits a signal into segments.
+// The zero value uses 256 sample Hann windowed segments with no overlap and
+// a sample rate of one.
+type WelchOptions struct {
+	// SegmentLength is the number of samples in each segment.  Signals
+	// shorter than this are treated as a single segment.
+	SegmentLength int
+	// Overlap is the number of samples consecutive segments share.
+	Overlap int
+	// Window tapers each segment, Hann is used if it is nil.
+	Window Window
+	// SampleRate is used to label the frequency of each bin.
+	SampleRate float64
+}
+
+// segments returns the segment length, taper and start of each segment of
+// an n sample signal.
+func (o WelchOptions) segments(n int) (int, []float64, []int) {
+	length := o.SegmentLength
+	if length <= 0 {
+		length = 256
+	}
+	if length > n {
+		length = n
+	}
+	step := length - o.Overlap
+	if step < 1 {
+		step = 1
+	}
+	w := o.Window
+	if w == nil {
+		w = Hann
+	}
+	var starts []int
+	for s := 0; s+length <= n; s += step {
+		starts = append(starts, s)
+	}
+	return length, Window1d(length, w), starts
+}
+
+// freqs returns the frequency of each bin of the real transform of an n
+// sample segment.
+func (o WelchOptions) freqs(n int) []float64 {
+	return rfftFreqs(n, o.SampleRate)
+}
+
+// rfftFreqs returns the frequency of each bin of the real-to-complex
+// transform of n samples taken at the given rate, or at a rate of one if it
+// isn't positive.
+func rfftFreqs(n int, rate float64) []float64 {
+	if rate <= 0 {
+		rate = 1
+	}
+	f := make([]float64, n/2+1)
+	for i := range f {
+		f[i] = float64(i) * rate / float64(n)
+	}
+	return f
+}
+
+// WelchPSD estimates the one-sided power spectral density of x with Welch's
+// method: the periodograms of windowed, possibly overlapping segments are
+// averaged.  The PSD is scaled so that integrating it over frequency gives
+// the mean square of x.
+func WelchPSD(x []float64, opts WelchOptions) ([]float64, []float64) {
+	length, taper, starts := opts.segments(len(x))
+	frames := make([][]float64, len(starts))
+	for i, s := range starts {
+		frames[i] = make([]float64, length)
+		for j := range frames[i] {
+			frames[i][j] = x[s+j] * taper[j]
+		}
+	}
+	spectra := DftFrames(frames)
+
+	rate := opts.SampleRate
+	if rate <= 0 {
+		rate = 1
+	}
+	energy := 0.0
+	for _, w := range taper {
+		energy += w * w
+	}
+	psd := make([]float64, length/2+1)
+	for _, s := range spectra {
+		for k, v := range s {
+			psd[k] += real(v)*real(v) + imag(v)*imag(v)
+		}
+	}
+	ScaleReal(psd, 1/(rate*energy*float64(len(spectra))))
+	OneSided(psd, length, PreserveEdges)
+	return opts.freqs(length), psd
+}
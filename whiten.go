@@ -0,0 +1,35 @@
+package fftw
+
+import (
+	"math/cmplx"
+)
+
+// Whiten flattens the magnitude of spectrum in place while preserving its
+// phase, by dividing each bin by the mean magnitude of the bins within
+// smoothing bins of it.  With smoothing = 0 every non-zero bin ends up with
+// magnitude one, which is what phase correlation wants; larger values only
+// remove the broad spectral envelope and keep local peaks.
+func Whiten(spectrum []complex128, smoothing int) {
+	if smoothing < 0 {
+		smoothing = 0
+	}
+	// sum[i] is the total magnitude of spectrum[:i].
+	sum := make([]float64, len(spectrum)+1)
+	for i, v := range spectrum {
+		sum[i+1] = sum[i] + cmplx.Abs(v)
+	}
+	for i := range spectrum {
+		lo := i - smoothing
+		if lo < 0 {
+			lo = 0
+		}
+		hi := i + smoothing + 1
+		if hi > len(spectrum) {
+			hi = len(spectrum)
+		}
+		mean := (sum[hi] - sum[lo]) / float64(hi-lo)
+		if mean > 0 {
+			spectrum[i] /= complex(mean, 0)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func WhitenSpec(c gospec.Context) {
+	c.Specify("Whitening without smoothing leaves only the phase.", func() {
+		s := []complex128{complex(3, 4), complex(0, -2), 0, complex(-1, 0)}
+		Whiten(s, 0)
+		c.Expect(cmplx.Abs(s[0]-complex(0.6, 0.8)), gospec.IsWithin(1e-12), 0.0)
+		c.Expect(cmplx.Abs(s[1]-complex(0, -1)), gospec.IsWithin(1e-12), 0.0)
+		c.Expect(s[2], gospec.Equals, complex128(0))
+		c.Expect(cmplx.Abs(s[3]-complex(-1, 0)), gospec.IsWithin(1e-12), 0.0)
+	})
+
+	c.Specify("Whitening with smoothing removes a smooth envelope.", func() {
+		s := make([]complex128, 200)
+		for i := range s {
+			envelope := 10 + 5*math.Sin(float64(i)/40)
+			s[i] = cmplx.Rect(envelope, float64(i))
+		}
+		Whiten(s, 3)
+		for i := 3; i < len(s)-3; i++ {
+			c.Expect(cmplx.Abs(s[i]), gospec.IsWithin(1e-2), 1.0)
+			c.Expect(cmplx.Phase(s[i]), gospec.IsWithin(1e-9), cmplx.Phase(cmplx.Rect(1, float64(i))))
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package fftw
+
+import "fmt"
+
+// AnalyticSignal returns the analytic signal of x, x + i*H(x) where H is the
+// Hilbert transform, which has the same positive frequencies as x (doubled)
+// and no negative ones.
+func AnalyticSignal(x []float64) []complex128 {
+	n := len(x)
+	z := make([]complex128, n)
+	if n == 0 {
+		return z
+	}
+	for i := range x {
+		z[i] = complex(x[i], 0)
+	}
+	Dft1d(z, z, Forward, Estimate)
+	for k := 1; k < n; k++ {
+		switch {
+		case 2*k < n:
+			z[k] *= 2
+		case 2*k > n:
+			z[k] = 0
+		}
+	}
+	Dft1d(z, z, Backward, Estimate)
+	Scale(z, 1/float64(n))
+	return z
+}
+
+// WignerVille returns the pseudo Wigner-Ville distribution of x, computed
+// from its analytic signal with lags of up to nfreq/2 samples.  Row t is
+// the distribution at sample t, over nfreq frequency bins where bin k is
+// the frequency k/(2*nfreq) cycles per sample, from zero up to the Nyquist
+// frequency.
+//
+// The distribution has far better time-frequency resolution than a
+// spectrogram, and is perfectly concentrated along the instantaneous
+// frequency of a linear chirp, but signals with several components show
+// oscillating cross terms between them.
+func WignerVille(x []float64, nfreq int) [][]float64 {
+	if nfreq < 1 {
+		panic(fmt.Sprint("WignerVille needs at least one frequency bin, got ", nfreq))
+	}
+	z := AnalyticSignal(x)
+	n := len(z)
+	buf := Alloc1d(nfreq)
+	defer Free1d(buf)
+	p := PlanDft1d(buf, buf, Forward, Estimate)
+
+	w := make([][]float64, n)
+	data := make([]float64, n*nfreq)
+	for t := range w {
+		lags := t
+		if n-1-t < lags {
+			lags = n - 1 - t
+		}
+		if nfreq/2-1 < lags {
+			lags = nfreq/2 - 1
+		}
+		for i := range buf {
+			buf[i] = 0
+		}
+		for m := -lags; m <= lags; m++ {
+			a := z[t+m]
+			b := z[t-m]
+			buf[(m+nfreq)%nfreq] = a * complex(real(b), -imag(b))
+		}
+		p.Execute()
+		// The lag kernel is Hermitian, so its transform is real.
+		w[t] = data[t*nfreq : (t+1)*nfreq]
+		for k := range w[t] {
+			w[t][k] = real(buf[k])
+		}
+	}
+	return w
+}
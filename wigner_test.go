@@ -0,0 +1,43 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+	"math/cmplx"
+)
+
+func WignerVilleSpec(c gospec.Context) {
+	c.Specify("The analytic signal of a cosine is a complex exponential.", func() {
+		x := make([]float64, 64)
+		for i := range x {
+			x[i] = math.Cos(2 * math.Pi * 5 * float64(i) / 64)
+		}
+		z := AnalyticSignal(x)
+		for i := range z {
+			c.Expect(cmplx.Abs(z[i]-cmplx.Exp(complex(0, 2*math.Pi*5*float64(i)/64))), gospec.IsWithin(1e-9), 0.0)
+		}
+	})
+
+	c.Specify("The Wigner-Ville distribution follows a linear chirp.", func() {
+		// The instantaneous frequency rises from 0.05 to 0.35 cycles per sample.
+		n := 128
+		x := make([]float64, n)
+		for i := range x {
+			t := float64(i)
+			x[i] = math.Cos(2 * math.Pi * (0.05*t + 0.3*t*t/(2*float64(n))))
+		}
+		nfreq := 64
+		w := WignerVille(x, nfreq)
+		c.Expect(len(w), gospec.Equals, n)
+		for _, t := range []int{40, 64, 90} {
+			peak := 0
+			for k := range w[t] {
+				if w[t][k] > w[t][peak] {
+					peak = k
+				}
+			}
+			f := 0.05 + 0.3*float64(t)/float64(n)
+			c.Expect(float64(peak)/float64(2*nfreq), gospec.IsWithin(1.0/float64(2*nfreq)), f)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package fftw
+
+import (
+	"math"
+)
+
+// A Window gives the taper weight at a normalized distance r from the centre
+// of the window, where r = 0 is the centre and r = 1 is the edge.  Windows
+// should return 0 for r > 1, which is what clips the corners of radial windows.
+type Window func(r float64) float64
+
+// Hann is the raised cosine window, which falls smoothly to zero at its edge.
+func Hann(r float64) float64 {
+	if r > 1 {
+		return 0
+	}
+	return 0.5 * (1 + math.Cos(math.Pi*r))
+}
+
+// Tukey returns a window that is flat over the central 1-alpha of its width
+// and tapers to zero with a cosine over the rest.  Tukey(0) is rectangular
+// and Tukey(1) is the same as Hann.
+func Tukey(alpha float64) Window {
+	return func(r float64) float64 {
+		if r > 1 {
+			return 0
+		}
+		if r <= 1-alpha {
+			return 1
+		}
+		return 0.5 * (1 + math.Cos(math.Pi*(r-1+alpha)/alpha))
+	}
+}
+
+// Gaussian returns a window with standard deviation sigma, measured in
+// units of the window's half-width.
+func Gaussian(sigma float64) Window {
+	return func(r float64) float64 {
+		if r > 1 {
+			return 0
+		}
+		return math.Exp(-0.5 * (r / sigma) * (r / sigma))
+	}
+}
+
+// windowPos maps index i of an n point window onto [-1, 1].
+func windowPos(i, n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	return 2*float64(i)/float64(n-1) - 1
+}
+
+func alloc2dReal(n0, n1 int) [][]float64 {
+	a := make([]float64, n0*n1)
+	r := make([][]float64, n0)
+	for i := range r {
+		r[i] = a[i*n1 : (i+1)*n1]
+	}
+	return r
+}
+
+func Window1d(n int, w Window) []float64 {
+	r := make([]float64, n)
+	for i := range r {
+		r[i] = w(math.Abs(windowPos(i, n)))
+	}
+	return r
+}
+
+// Window2d returns the separable n0 x n1 window w(x)*w(y).
+func Window2d(n0, n1 int, w Window) [][]float64 {
+	w0 := Window1d(n0, w)
+	w1 := Window1d(n1, w)
+	r := alloc2dReal(n0, n1)
+	for i := range r {
+		for j := range r[i] {
+			r[i][j] = w0[i] * w1[j]
+		}
+	}
+	return r
+}
+
+// RadialWindow2d returns the n0 x n1 window w(sqrt(x*x + y*y)), which unlike
+// Window2d is isotropic and so does not favour the axes of the spectrum.
+func RadialWindow2d(n0, n1 int, w Window) [][]float64 {
+	r := alloc2dReal(n0, n1)
+	for i := range r {
+		x := windowPos(i, n0)
+		for j := range r[i] {
+			y := windowPos(j, n1)
+			r[i][j] = w(math.Sqrt(x*x + y*y))
+		}
+	}
+	return r
+}
+
+// ApplyWindow2d multiplies x, in place, by the window w.
+func ApplyWindow2d(x [][]complex128, w [][]float64) {
+	// TODO: check that x and w have the same dimensions
+	for i := range x {
+		for j := range x[i] {
+			x[i][j] *= complex(w[i][j], 0)
+		}
+	}
+}
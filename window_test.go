@@ -0,0 +1,75 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math"
+)
+
+func Window2dSpec(c gospec.Context) {
+	c.Specify("Hann windows are one in the middle and zero at the edges.", func() {
+		w := Window1d(9, Hann)
+		c.Expect(w[0], gospec.IsWithin(1e-12), 0.0)
+		c.Expect(w[4], gospec.IsWithin(1e-12), 1.0)
+		c.Expect(w[8], gospec.IsWithin(1e-12), 0.0)
+		for i := range w {
+			c.Expect(w[i], gospec.IsWithin(1e-12), w[len(w)-1-i])
+		}
+	})
+
+	c.Specify("Tukey windows are flat in the middle and match Hann at alpha = 1.", func() {
+		w := Window1d(21, Tukey(0.5))
+		for i := 5; i <= 15; i++ {
+			c.Expect(w[i], gospec.IsWithin(1e-12), 1.0)
+		}
+		c.Expect(w[0], gospec.IsWithin(1e-12), 0.0)
+		h := Window1d(21, Hann)
+		t := Window1d(21, Tukey(1))
+		for i := range h {
+			c.Expect(t[i], gospec.IsWithin(1e-12), h[i])
+		}
+	})
+
+	c.Specify("Gaussian windows fall off with the given width.", func() {
+		w := Window1d(11, Gaussian(0.5))
+		c.Expect(w[5], gospec.IsWithin(1e-12), 1.0)
+		c.Expect(w[0], gospec.IsWithin(1e-12), math.Exp(-2))
+	})
+
+	c.Specify("Separable 2d windows are the outer product of 1d windows.", func() {
+		w := Window2d(8, 5, Hann)
+		w0 := Window1d(8, Hann)
+		w1 := Window1d(5, Hann)
+		c.Expect(len(w), gospec.Equals, 8)
+		for i := range w {
+			c.Expect(len(w[i]), gospec.Equals, 5)
+			for j := range w[i] {
+				c.Expect(w[i][j], gospec.IsWithin(1e-12), w0[i]*w1[j])
+			}
+		}
+	})
+
+	c.Specify("Radial 2d windows are isotropic and zero in the corners.", func() {
+		w := RadialWindow2d(9, 9, Hann)
+		c.Expect(w[4][4], gospec.IsWithin(1e-12), 1.0)
+		c.Expect(w[0][0], gospec.IsWithin(1e-12), 0.0)
+		c.Expect(w[8][8], gospec.IsWithin(1e-12), 0.0)
+		c.Expect(w[2][4], gospec.IsWithin(1e-12), w[4][2])
+		c.Expect(w[4][0], gospec.IsWithin(1e-12), 0.0)
+	})
+
+	c.Specify("Applying a window multiplies the data by it.", func() {
+		x := Alloc2d(4, 6)
+		for i := range x {
+			for j := range x[i] {
+				x[i][j] = complex(1, 2)
+			}
+		}
+		w := Window2d(4, 6, Tukey(0.5))
+		ApplyWindow2d(x, w)
+		for i := range x {
+			for j := range x[i] {
+				c.Expect(x[i][j], gospec.Equals, complex(w[i][j], 2*w[i][j]))
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package fftw
+
+import (
+	"fmt"
+	"math"
+)
+
+// A WindowedFFT computes the spectra of windowed frames of a real signal.
+// The window is applied as each frame is copied into the plan's aligned
+// input buffer, so the frame is read once and never modified, rather than
+// windowed in one pass and copied in another.  A WindowedFFT is not safe for
+// concurrent use.
+type WindowedFFT struct {
+	window   []float64
+	in       []float64
+	spectrum []complex128
+	plan     *Plan
+}
+
+// NewWindowedFFT returns a WindowedFFT for frames of n samples tapered by w.
+func NewWindowedFFT(n int, w Window) *WindowedFFT {
+	if n < 1 {
+		panic(fmt.Sprint("NewWindowedFFT needs a positive frame length, got ", n))
+	}
+	f := new(WindowedFFT)
+	f.window = Window1d(n, w)
+	f.in = alignedReals(n)
+	f.spectrum = make([]complex128, n/2+1)
+	f.plan = PlanDftR2C1d(f.in, f.spectrum, Estimate)
+	return f
+}
+
+// Execute returns the spectrum of frame multiplied by the window.  Frames
+// shorter than the window are zero padded.  The spectrum is overwritten by
+// the next call to Execute.
+func (f *WindowedFFT) Execute(frame []float64) []complex128 {
+	if len(frame) > len(f.in) {
+		panic(fmt.Sprint("Frame of ", len(frame), " samples is longer than the window of ", len(f.in)))
+	}
+	for i, v := range frame {
+		f.in[i] = v * f.window[i]
+	}
+	for i := len(frame); i < len(f.in); i++ {
+		f.in[i] = 0
+	}
+	f.plan.Execute()
+	return f.spectrum
+}
+
+// WindowedFFT adds a node that computes the spectrum of each block's
+// samples tapered by w, in a single pass that leaves the samples unchanged.
+func (g *Graph) WindowedFFT(w Window) *Graph {
+	taper := Window1d(len(g.frame.Samples), w)
+	in := alignedReals(len(g.frame.Samples))
+	p := PlanDftR2C1d(in, g.frame.Spectrum, Estimate)
+	return g.Then(NodeFunc(func(f *Frame) {
+		for i, v := range f.Samples {
+			in[i] = v * taper[i]
+		}
+		p.Execute()
+	}))
+}
+
+// ExecuteMag computes the spectrum of frame as Execute does and writes the
+// magnitude of each bin into mag, which must have n/2+1 elements.
+func (f *WindowedFFT) ExecuteMag(frame, mag []float64) {
+	f.checkBins(mag)
+	for k, v := range f.Execute(frame) {
+		mag[k] = math.Hypot(real(v), imag(v))
+	}
+}
+
+// ExecutePower is ExecuteMag for the squared magnitude of each bin.
+func (f *WindowedFFT) ExecutePower(frame, power []float64) {
+	f.checkBins(power)
+	for k, v := range f.Execute(frame) {
+		power[k] = real(v)*real(v) + imag(v)*imag(v)
+	}
+}
+
+func (f *WindowedFFT) checkBins(out []float64) {
+	if len(out) != len(f.spectrum) {
+		panic(fmt.Sprint("Frames of ", len(f.in), " samples have ", len(f.spectrum), " bins, got a buffer of ", len(out)))
+	}
+}
@@ -0,0 +1,83 @@
+package fftw
+
+import (
+	"github.com/orfjackal/gospec/src/gospec"
+	"math/cmplx"
+	"testing"
+)
+
+func WindowedFFTSpec(c gospec.Context) {
+	x := make([]float64, 24)
+	for i := range x {
+		x[i] = float64(i*5%9) - 4
+	}
+	taper := Window1d(16, Hann)
+	want := func(frame []float64) []complex128 {
+		in := make([]float64, 16)
+		for i, v := range frame {
+			in[i] = v * taper[i]
+		}
+		out := make([]complex128, 9)
+		PlanDftR2C1d(in, out, Estimate).Execute()
+		return out
+	}
+
+	c.Specify("Windowed FFTs match windowing then transforming.", func() {
+		f := NewWindowedFFT(16, Hann)
+		for _, frame := range [][]float64{x[:16], x[8:24], x[16:]} {
+			orig := append([]float64(nil), frame...)
+			got := f.Execute(frame)
+			w := want(frame)
+			for k := range w {
+				c.Expect(real(got[k]), gospec.IsWithin(1e-9), real(w[k]))
+				c.Expect(imag(got[k]), gospec.IsWithin(1e-9), imag(w[k]))
+			}
+			c.Expect(frame, gospec.ContainsInOrder, orig)
+		}
+	})
+
+	c.Specify("Magnitudes and powers are computed from the spectrum.", func() {
+		f := NewWindowedFFT(16, Hann)
+		mag := make([]float64, 9)
+		power := make([]float64, 9)
+		f.ExecuteMag(x[4:20], mag)
+		f.ExecutePower(x[4:20], power)
+		for k, v := range want(x[4:20]) {
+			c.Expect(mag[k], gospec.IsWithin(1e-9), cmplx.Abs(v))
+			c.Expect(power[k], gospec.IsWithin(1e-9), cmplx.Abs(v)*cmplx.Abs(v))
+		}
+	})
+
+	c.Specify("Graphs can window and transform in one node.", func() {
+		var spectra [][]complex128
+		var samples SampleCollector
+		NewGraph(16, SliceSource(x)).WindowedFFT(Hann).
+			Spectrum(func(s []complex128) { spectra = append(spectra, append([]complex128(nil), s...)) }).
+			To(&samples).Run()
+		c.Expect(len(spectra), gospec.Equals, 2)
+		c.Expect(samples.Samples, gospec.ContainsInOrder, x)
+		for b, s := range spectra {
+			end := 16 * (b + 1)
+			if end > len(x) {
+				end = len(x)
+			}
+			w := want(x[16*b : end])
+			for k := range w {
+				c.Expect(real(s[k]), gospec.IsWithin(1e-9), real(w[k]))
+				c.Expect(imag(s[k]), gospec.IsWithin(1e-9), imag(w[k]))
+			}
+		}
+	})
+}
+
+// WindowedFFTAllocsSpec runs alone, since allocations made by the branches
+// of other specs would be counted against it.
+func WindowedFFTAllocsSpec(c gospec.Context) {
+	c.Specify("Windowed FFTs don't allocate.", func() {
+		x := make([]float64, 16)
+		power := make([]float64, 9)
+		f := NewWindowedFFT(16, Hann)
+		c.Expect(testing.AllocsPerRun(5, func() { f.Execute(x) }), gospec.Equals, 0.0)
+		c.Expect(testing.AllocsPerRun(5, func() { f.ExecutePower(x, power) }), gospec.Equals, 0.0)
+	})
+}